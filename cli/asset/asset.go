@@ -28,10 +28,12 @@ import (
 	"github.com/Ontology/account"
 	. "github.com/Ontology/cli/common"
 	. "github.com/Ontology/common"
+	"github.com/Ontology/common/config"
 	. "github.com/Ontology/core/asset"
 	"github.com/Ontology/core/contract"
 	"github.com/Ontology/core/signature"
 	"github.com/Ontology/core/transaction"
+	"github.com/Ontology/core/transaction/payload"
 	"github.com/Ontology/net/httpjsonrpc"
 	"math/rand"
 	"os"
@@ -91,6 +93,7 @@ func getUintHash(programHashStr, assetHashStr string) (Uint160, Uint256, error)
 }
 
 func signTransaction(signer *account.Account, tx *transaction.Transaction) error {
+	tx.SetNetworkMagic(uint32(config.Parameters.Magic))
 	signature, err := signature.SignBySigner(tx, signer)
 	if err != nil {
 		fmt.Println("SignBySigner failed")
@@ -169,7 +172,10 @@ func makeIssueTransaction(issuer *account.Account, programHashStr, assetHashStr
 	return hex.EncodeToString(buffer.Bytes()), nil
 }
 
-func makeTransferTransaction(signer *account.Account, programHashStr, assetHashStr string, value Fixed64, netWorkFee Fixed64) (string, error) {
+// makeTransferTransaction transfers value to the program hash, the output is
+// locked when lockType is not OutputLock_NONE.
+func makeTransferTransaction(signer *account.Account, programHashStr, assetHashStr string, value Fixed64, netWorkFee Fixed64,
+	lockType utxo.OutputLockType, lockValue uint32) (string, error) {
 	inputs := []*utxo.UTXOTxInput{}
 	outputs := []*utxo.TxOutput{}
 	// get user id & asset id
@@ -183,6 +189,11 @@ func makeTransferTransaction(signer *account.Account, programHashStr, assetHashS
 	if err != nil {
 		return "", err
 	}
+	// the transfer output comes first, followed by the change
+	if value != 0 {
+		outputs[0].LockType = lockType
+		outputs[0].LockValue = lockValue
+	}
 	tx, _ = transaction.NewTransferAssetTransaction(inputs, outputs)
 	txAttr := transaction.NewTxAttribute(transaction.Nonce, []byte(strconv.FormatInt(rand.Int63(), 10)))
 	tx.Attributes = make([]*transaction.TxAttribute, 0)
@@ -199,6 +210,61 @@ func makeTransferTransaction(signer *account.Account, programHashStr, assetHashS
 	return hex.EncodeToString(buffer.Bytes()), nil
 }
 
+// makeRenewTransaction renews the asset, the system fee is paid by the signer.
+func makeRenewTransaction(signer *account.Account, assetHashStr string, years uint32) (string, error) {
+	_, assetHash, err := getUintHash(ToHexString(signer.ProgramHash.ToArray()), assetHashStr)
+	if err != nil {
+		return "", err
+	}
+	reverseHash, _ := Uint256ParseFromBytes(assetHash.ToArrayReverse())
+	tx, _ := transaction.NewAssetRenewTransaction(&payload.AssetRenew{AssetID: reverseHash, Years: years}, []*utxo.UTXOTxInput{}, []*utxo.TxOutput{})
+	if feeAsset, ok := transaction.GetSystemFeeAssetID(); ok && tx.GetSystemFee() > 0 {
+		tx.UTXOInputs, tx.Outputs, err = calcUtxoByRpc(signer.ProgramHash, signer.ProgramHash, feeAsset, 0, tx.GetSystemFee(), false)
+		if err != nil {
+			return "", err
+		}
+	}
+	txAttr := transaction.NewTxAttribute(transaction.Nonce, []byte(strconv.FormatInt(rand.Int63(), 10)))
+	tx.Attributes = append(tx.Attributes, &txAttr)
+	if err := signTransaction(signer, tx); err != nil {
+		fmt.Println("sign renew transaction failed")
+		return "", err
+	}
+	var buffer bytes.Buffer
+	if err := tx.Serialize(&buffer); err != nil {
+		fmt.Println("serialization of renew transaction failed")
+		return "", err
+	}
+	return hex.EncodeToString(buffer.Bytes()), nil
+}
+
+// makeAdminChangeTransaction replaces the admin and the issuer of the asset,
+// the signer must be the owner of the asset.
+func makeAdminChangeTransaction(owner *account.Account, assetHashStr, adminStr, issuerStr string) (string, error) {
+	admin, assetHash, err := getUintHash(adminStr, assetHashStr)
+	if err != nil {
+		return "", err
+	}
+	issuer, _, err := getUintHash(issuerStr, assetHashStr)
+	if err != nil {
+		return "", err
+	}
+	reverseHash, _ := Uint256ParseFromBytes(assetHash.ToArrayReverse())
+	tx, _ := transaction.NewAssetAdminChangeTransaction(&payload.AssetAdminChange{AssetID: reverseHash, Admin: admin, Issuer: issuer})
+	txAttr := transaction.NewTxAttribute(transaction.Nonce, []byte(strconv.FormatInt(rand.Int63(), 10)))
+	tx.Attributes = append(tx.Attributes, &txAttr)
+	if err := signTransaction(owner, tx); err != nil {
+		fmt.Println("sign admin change transaction failed")
+		return "", err
+	}
+	var buffer bytes.Buffer
+	if err := tx.Serialize(&buffer); err != nil {
+		fmt.Println("serialization of admin change transaction failed")
+		return "", err
+	}
+	return hex.EncodeToString(buffer.Bytes()), nil
+}
+
 func checkAndAddFees(Spender Uint160, Tx *transaction.Transaction, networkFee Fixed64) (*transaction.Transaction, error) {
 	return Tx, nil
 }
@@ -247,6 +313,9 @@ func calcUtxoByRpc(spender Uint160, toAddr Uint160, assetID Uint256, value Fixed
 	for _, v := range unspend {
 		var unspentUtxo UTXOUnspentInfo
 		temp := v.(map[string]interface{})
+		if locked, ok := temp["Locked"].(bool); ok && locked {
+			continue
+		}
 		if unspentUtxo.Value, err = strconv.ParseInt(temp["Value"].(string), 10, 64); err != nil {
 			return nil, nil, err
 		}
@@ -317,7 +386,13 @@ func assetAction(c *cli.Context) error {
 	if c.Bool("transfer") == true {
 		funcName = "transfer"
 	}
-	if !c.Bool("reg") && !c.Bool("issue") && !c.Bool("transfer") {
+	if c.Bool("renew") == true {
+		funcName = "renew"
+	}
+	if c.Bool("changeadmin") == true {
+		funcName = "changeadmin"
+	}
+	if !c.Bool("reg") && !c.Bool("issue") && !c.Bool("transfer") && !c.Bool("renew") && !c.Bool("changeadmin") {
 		cli.ShowSubcommandHelp(c)
 		return nil
 	}
@@ -357,7 +432,43 @@ func assetAction(c *cli.Context) error {
 			fmt.Println("missing flag [--asset] or [--to]")
 			return nil
 		}
-		txHex, err = makeTransferTransaction(admin, to, asset, Fixed64(value), Fixed64(netWorkFee))
+		lockType, lockValue := utxo.OutputLock_NONE, uint32(0)
+		if c.IsSet("lockheight") && c.IsSet("locktime") {
+			fmt.Println("only one of [--lockheight] and [--locktime] can be given")
+			return nil
+		} else if c.IsSet("lockheight") {
+			lockType, lockValue = utxo.OutputLock_HEIGHT, uint32(c.Uint("lockheight"))
+		} else if c.IsSet("locktime") {
+			lockType, lockValue = utxo.OutputLock_TIME, uint32(c.Uint("locktime"))
+		}
+		txHex, err = makeTransferTransaction(admin, to, asset, Fixed64(value), Fixed64(netWorkFee), lockType, lockValue)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+	case "renew":
+		asset := c.String("asset")
+		if asset == "" {
+			fmt.Println("missing flag [--asset]")
+			return nil
+		}
+		txHex, err = makeRenewTransaction(admin, asset, uint32(c.Uint("years")))
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+	case "changeadmin":
+		asset := c.String("asset")
+		newAdmin := c.String("admin")
+		newIssuer := c.String("issuer")
+		if asset == "" || newAdmin == "" {
+			fmt.Println("missing flag [--asset] or [--admin]")
+			return nil
+		}
+		if newIssuer == "" {
+			newIssuer = newAdmin
+		}
+		txHex, err = makeAdminChangeTransaction(admin, asset, newAdmin, newIssuer)
 		if err != nil {
 			fmt.Println(err)
 			return nil
@@ -379,7 +490,7 @@ func assetAction(c *cli.Context) error {
 func NewCommand() *cli.Command {
 	return &cli.Command{
 		Name:        "asset",
-		Usage:       "asset registration, issuance and transfer",
+		Usage:       "asset registration, issuance, transfer, renewal and admin change",
 		Description: "With nodectl asset, you could control assert through transaction.",
 		ArgsUsage:   "[args]",
 		Flags: []cli.Flag{
@@ -395,6 +506,14 @@ func NewCommand() *cli.Command {
 				Name:  "transfer, t",
 				Usage: "transfer asset",
 			},
+			cli.BoolFlag{
+				Name:  "renew",
+				Usage: "renew asset, the system fee is paid by the wallet",
+			},
+			cli.BoolFlag{
+				Name:  "changeadmin",
+				Usage: "change admin and issuer of asset, signed by the asset owner",
+			},
 			cli.StringFlag{
 				Name:  "wallet, w",
 				Usage: "wallet name",
@@ -432,6 +551,27 @@ func NewCommand() *cli.Command {
 				Name:  "netWorkFee, f",
 				Usage: "netWorkFee ammount",
 			},
+			cli.UintFlag{
+				Name:  "years",
+				Usage: "years to renew asset",
+				Value: 1,
+			},
+			cli.StringFlag{
+				Name:  "admin",
+				Usage: "program hash of new asset admin",
+			},
+			cli.StringFlag{
+				Name:  "issuer",
+				Usage: "program hash of new asset issuer, the admin if not given",
+			},
+			cli.UintFlag{
+				Name:  "lockheight",
+				Usage: "block height before which the transferred output can not be spent",
+			},
+			cli.UintFlag{
+				Name:  "locktime",
+				Usage: "unix time before which the transferred output can not be spent",
+			},
 		},
 		Action: assetAction,
 		OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
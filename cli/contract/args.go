@@ -0,0 +1,170 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract"
+	"github.com/Ontology/vm/neovm"
+	vmtypes "github.com/Ontology/vm/neovm/types"
+)
+
+// parseArgs decodes the JSON array given with --args into stack items of the
+// parameter types the ABI declares for method.
+func parseArgs(method *code.ABIMethod, args string) ([]vmtypes.StackItemInterface, error) {
+	var values []interface{}
+	if strings.TrimSpace(args) != "" {
+		d := json.NewDecoder(strings.NewReader(args))
+		d.UseNumber()
+		if err := d.Decode(&values); err != nil {
+			return nil, fmt.Errorf("args must be a JSON array: %v", err)
+		}
+	}
+	if len(values) != len(method.Parameters) {
+		return nil, fmt.Errorf("method %s takes %d arguments, %d given", method.Name, len(method.Parameters), len(values))
+	}
+	items := make([]vmtypes.StackItemInterface, 0, len(values))
+	for i, p := range method.Parameters {
+		item, err := parseArg(p.Type, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %v", p.Name, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func parseArg(t contract.ContractParameterType, v interface{}) (vmtypes.StackItemInterface, error) {
+	switch t {
+	case contract.Boolean:
+		switch b := v.(type) {
+		case bool:
+			return vmtypes.NewBoolean(b), nil
+		case string:
+			return vmtypes.NewBoolean(b == "true"), nil
+		}
+	case contract.Integer:
+		s := fmt.Sprint(v)
+		i, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %s", s)
+		}
+		return vmtypes.NewInteger(i), nil
+	case contract.String:
+		if s, ok := v.(string); ok {
+			return vmtypes.NewByteArray([]byte(s)), nil
+		}
+	case contract.Hash160:
+		if s, ok := v.(string); ok {
+			// an address or the hash in hex
+			if hash, err := common.ToScriptHash(s); err == nil {
+				return vmtypes.NewByteArray(hash.ToArray()), nil
+			}
+			return parseHex(s, 20)
+		}
+	case contract.Hash256:
+		if s, ok := v.(string); ok {
+			return parseHex(s, 32)
+		}
+	case contract.Signature, contract.ByteArray, contract.PublicKey:
+		if s, ok := v.(string); ok {
+			return parseHex(s, 0)
+		}
+	case contract.Array:
+		if arr, ok := v.([]interface{}); ok {
+			items := make([]vmtypes.StackItemInterface, 0, len(arr))
+			for _, e := range arr {
+				item, err := parseUntyped(e)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			return vmtypes.NewArray(items), nil
+		}
+	default:
+		return parseUntyped(v)
+	}
+	return nil, fmt.Errorf("invalid %s value %v", t.Name(), v)
+}
+
+// parseUntyped converts array elements, which the ABI gives no type for, by
+// their JSON type. Strings are pushed as their bytes.
+func parseUntyped(v interface{}) (vmtypes.StackItemInterface, error) {
+	switch e := v.(type) {
+	case bool:
+		return vmtypes.NewBoolean(e), nil
+	case json.Number:
+		return parseArg(contract.Integer, e)
+	case string:
+		return vmtypes.NewByteArray([]byte(e)), nil
+	case []interface{}:
+		return parseArg(contract.Array, e)
+	}
+	return nil, fmt.Errorf("unsupported value %v", v)
+}
+
+func parseHex(s string, size int) (vmtypes.StackItemInterface, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if size > 0 && len(b) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(b))
+	}
+	return vmtypes.NewByteArray(b), nil
+}
+
+// emitItem pushes item onto the stack, arrays are built with PACK.
+func emitItem(builder *neovm.ParamsBuilder, item vmtypes.StackItemInterface) {
+	switch v := item.(type) {
+	case *vmtypes.Boolean:
+		builder.EmitPushBool(v.GetBoolean())
+	case *vmtypes.Integer:
+		builder.EmitPushInteger(v.GetBigInteger())
+	case *vmtypes.Array:
+		items := v.GetArray()
+		for i := len(items) - 1; i >= 0; i-- {
+			emitItem(builder, items[i])
+		}
+		builder.EmitPushInteger(big.NewInt(int64(len(items))))
+		builder.Emit(neovm.PACK)
+	default:
+		builder.EmitPushByteArray(item.GetByteArray())
+	}
+}
+
+// invocationScript builds the InvokeCode script calling method with args by
+// the usual entry point convention: the argument array, then the method name
+// on top of the stack.
+func invocationScript(method string, args []vmtypes.StackItemInterface) []byte {
+	builder := neovm.NewParamsBuilder(new(bytes.Buffer))
+	emitItem(builder, vmtypes.NewArray(args))
+	builder.EmitPushByteArray([]byte(method))
+	return builder.ToArray()
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract"
+	"github.com/Ontology/vm/neovm"
+	vmtypes "github.com/Ontology/vm/neovm/types"
+)
+
+func TestParseArgs(t *testing.T) {
+	method := &code.ABIMethod{
+		Name: "call",
+		Parameters: []*code.ABIParameter{
+			{Name: "flag", Type: contract.Boolean},
+			{Name: "amount", Type: contract.Integer},
+			{Name: "memo", Type: contract.String},
+			{Name: "account", Type: contract.Hash160},
+			{Name: "tx", Type: contract.Hash256},
+			{Name: "data", Type: contract.ByteArray},
+			{Name: "list", Type: contract.Array},
+		},
+	}
+	account := strings.Repeat("01", 20)
+	tx := strings.Repeat("02", 32)
+	items, err := parseArgs(method, `[true, 123456789012345678901234567890, "hi", "`+account+`", "`+tx+`", "beef", [1, "a", false, [2]]]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	amount, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	if b, ok := items[0].(*vmtypes.Boolean); !ok || !b.GetBoolean() {
+		t.Errorf("flag: got %v", items[0])
+	}
+	if i, ok := items[1].(*vmtypes.Integer); !ok || i.GetBigInteger().Cmp(amount) != 0 {
+		t.Errorf("amount: got %v", items[1])
+	}
+	for i, want := range [][]byte{[]byte("hi"), bytes.Repeat([]byte{1}, 20), bytes.Repeat([]byte{2}, 32), {0xbe, 0xef}} {
+		if b, ok := items[2+i].(*vmtypes.ByteArray); !ok || !bytes.Equal(b.GetByteArray(), want) {
+			t.Errorf("%s: got %v", method.Parameters[2+i].Name, items[2+i])
+		}
+	}
+	list, ok := items[6].(*vmtypes.Array)
+	if !ok || len(list.GetArray()) != 4 {
+		t.Fatalf("list: got %v", items[6])
+	}
+	if _, ok := list.GetArray()[0].(*vmtypes.Integer); !ok {
+		t.Errorf("list number: got %v", list.GetArray()[0])
+	}
+	if b, ok := list.GetArray()[1].(*vmtypes.ByteArray); !ok || string(b.GetByteArray()) != "a" {
+		t.Errorf("list string: got %v", list.GetArray()[1])
+	}
+	if _, ok := list.GetArray()[2].(*vmtypes.Boolean); !ok {
+		t.Errorf("list bool: got %v", list.GetArray()[2])
+	}
+	if _, ok := list.GetArray()[3].(*vmtypes.Array); !ok {
+		t.Errorf("nested list: got %v", list.GetArray()[3])
+	}
+
+	invalid := []string{
+		`[true]`,
+		`[true, 1, "hi", "` + account + `", "` + tx + `", "beef", [], 1]`,
+		`[true, 1.5, "hi", "` + account + `", "` + tx + `", "beef", []]`,
+		`[true, 1, 2, "` + account + `", "` + tx + `", "beef", []]`,
+		`[true, 1, "hi", "0102", "` + tx + `", "beef", []]`,
+		`[true, 1, "hi", "` + account + `", "` + account + `", "beef", []]`,
+		`[true, 1, "hi", "` + account + `", "` + tx + `", "xyz", []]`,
+		`[true, 1, "hi", "` + account + `", "` + tx + `", "beef", [{}]]`,
+		`{"flag": true}`,
+	}
+	for _, args := range invalid {
+		if _, err := parseArgs(method, args); err == nil {
+			t.Errorf("%s: no error", args)
+		}
+	}
+
+	if items, err := parseArgs(&code.ABIMethod{Name: "none"}, ""); err != nil || len(items) != 0 {
+		t.Errorf("no arguments: got %v, %v", items, err)
+	}
+}
+
+func TestInvocationScript(t *testing.T) {
+	method := &code.ABIMethod{
+		Name:       "put",
+		Parameters: []*code.ABIParameter{{Name: "key", Type: contract.String}, {Name: "value", Type: contract.Integer}},
+	}
+	items, err := parseArgs(method, `["k", 5]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the arguments are packed in reverse so that the first one is at index 0
+	want := neovm.NewParamsBuilder(new(bytes.Buffer))
+	want.EmitPushInteger(big.NewInt(5))
+	want.EmitPushByteArray([]byte("k"))
+	want.EmitPushInteger(big.NewInt(2))
+	want.Emit(neovm.PACK)
+	want.EmitPushByteArray([]byte("put"))
+	if script := invocationScript(method.Name, items); !bytes.Equal(script, want.ToArray()) {
+		t.Errorf("got %x, want %x", script, want.ToArray())
+	}
+}
@@ -0,0 +1,356 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Ontology/account"
+	. "github.com/Ontology/cli/common"
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/config"
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract"
+	"github.com/Ontology/core/signature"
+	"github.com/Ontology/core/transaction"
+	"github.com/Ontology/core/transaction/payload"
+	"github.com/Ontology/net/httpjsonrpc"
+	"github.com/Ontology/smartcontract/native"
+	"github.com/Ontology/smartcontract/types"
+
+	"github.com/urfave/cli"
+)
+
+func openWallet(name string, passwd []byte) account.Client {
+	if name == account.WalletFileName {
+		fmt.Println("Using default wallet: ", account.WalletFileName)
+	}
+	wallet := account.Open(name, passwd)
+	if wallet == nil {
+		fmt.Println("Failed to open wallet: ", name)
+		os.Exit(1)
+	}
+	return wallet
+}
+
+func signTransaction(signer *account.Account, tx *transaction.Transaction) error {
+	tx.SetNetworkMagic(uint32(config.Parameters.Magic))
+	sig, err := signature.SignBySigner(tx, signer)
+	if err != nil {
+		return err
+	}
+	transactionContract, err := contract.CreateSignatureContract(signer.PubKey())
+	if err != nil {
+		return err
+	}
+	ctx := &contract.ContractContext{
+		Data:       tx,
+		Codes:      make([][]byte, 1),
+		Parameters: make([][][]byte, 1),
+	}
+	if err := ctx.AddContract(transactionContract, signer.PubKey(), sig); err != nil {
+		return err
+	}
+	tx.SetPrograms(ctx.GetPrograms())
+	return nil
+}
+
+// sendTransaction adds a nonce, signs tx with the default account of the
+// wallet and sends it to the node.
+func sendTransaction(c *cli.Context, tx *transaction.Transaction) error {
+	wallet := openWallet(c.String("wallet"), WalletPassword(c.String("password")))
+	signer, err := wallet.GetDefaultAccount()
+	if err != nil {
+		return err
+	}
+	txAttr := transaction.NewTxAttribute(transaction.Nonce, []byte(strconv.FormatInt(rand.Int63(), 10)))
+	tx.Attributes = append(tx.Attributes, &txAttr)
+	if err := signTransaction(signer, tx); err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	if err := tx.Serialize(&buffer); err != nil {
+		return err
+	}
+	resp, err := httpjsonrpc.Call(Address(), "sendrawtransaction", 0, []interface{}{hex.EncodeToString(buffer.Bytes())})
+	if err != nil {
+		return err
+	}
+	return FormatOutput(resp)
+}
+
+func readCode(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// accept hex encoded files as produced by some compilers
+	if s := strings.TrimSpace(string(data)); len(s) > 0 && len(s)%2 == 0 {
+		if b, err := hex.DecodeString(s); err == nil {
+			return b, nil
+		}
+	}
+	return data, nil
+}
+
+func deployAction(c *cli.Context) error {
+	if c.String("avm") == "" {
+		fmt.Println("missing flag [--avm]")
+		return nil
+	}
+	avm, err := readCode(c.String("avm"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	var abi *code.ABI
+	if c.String("abi") != "" {
+		manifest, err := ioutil.ReadFile(c.String("abi"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+		if abi, err = code.ParseABI(manifest); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+	}
+	fc := &code.FunctionCode{
+		Code:           avm,
+		ParameterTypes: []contract.ContractParameterType{contract.String, contract.Array},
+		ReturnType:     contract.ByteArray,
+	}
+	property := types.NewContractProperty(c.Bool("storage"), c.Bool("dynamic-invoke"), c.Bool("reentrancy-guard"))
+	tx, err := transaction.NewDeployTransaction(fc, common.Uint160{}, c.String("name"), c.String("version"),
+		c.String("author"), c.String("email"), c.String("desc"), types.NEOVM, property)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	if abi != nil {
+		tx.Payload.(*payload.DeployCode).ABI = abi
+		tx.PayloadVersion = payload.DeployCodeABIPayloadVersion
+	}
+	codeHash := fc.CodeHash()
+	fmt.Println("code hash:", common.ToHexString(codeHash.ToArray()))
+	if err := sendTransaction(c, tx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	return nil
+}
+
+// getContract fetches the contract state, including its ABI, from the node.
+func getContract(hash string) (*httpjsonrpc.ContractInfo, error) {
+	resp, err := httpjsonrpc.Call(Address(), "getcontract", 0, []interface{}{hash})
+	if err != nil {
+		return nil, err
+	}
+	r := struct {
+		Result json.RawMessage
+	}{}
+	if err := json.Unmarshal(resp, &r); err != nil {
+		return nil, err
+	}
+	// failures are reported as a string result
+	var msg string
+	if err := json.Unmarshal(r.Result, &msg); err == nil && msg != "" {
+		return nil, errors.New(msg)
+	}
+	info := new(httpjsonrpc.ContractInfo)
+	if err := json.Unmarshal(r.Result, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func invokeAction(c *cli.Context) error {
+	hash, method := c.String("codehash"), c.String("method")
+	if hash == "" || method == "" {
+		fmt.Println("missing flag [--codehash] or [--method]")
+		return nil
+	}
+	b, err := hex.DecodeString(hash)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	var codeHash common.Uint160
+	if err := codeHash.Deserialize(bytes.NewReader(b)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	info, err := getContract(hash)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	if info.ABI == nil {
+		err := errors.New("contract was deployed without an ABI")
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	m := info.ABI.GetMethod(method)
+	if m == nil {
+		err := fmt.Errorf("contract has no method %s", method)
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	args, err := parseArgs(m, c.String("args"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	var script []byte
+	if types.VmType(info.VmType) == types.Native {
+		script, err = native.EncodeInput(method, args...)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+	} else {
+		script = invocationScript(method, args)
+	}
+	if c.Bool("script") {
+		fmt.Println(common.ToHexString(script))
+		return nil
+	}
+	tx, err := transaction.NewInvokeTransaction(script, codeHash)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	if err := sendTransaction(c, tx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	return nil
+}
+
+func NewCommand() *cli.Command {
+	walletFlags := []cli.Flag{
+		cli.StringFlag{
+			Name:  "wallet, w",
+			Usage: "wallet name",
+			Value: account.WalletFileName,
+		},
+		cli.StringFlag{
+			Name:  "password, p",
+			Usage: "wallet password",
+		},
+	}
+	return &cli.Command{
+		Name:        "contract",
+		Usage:       "deploy and invoke smart contracts",
+		Description: "With nodectl contract, you could deploy contracts with an ABI manifest and invoke their methods by name.",
+		ArgsUsage:   "[args]",
+		Subcommands: []cli.Command{
+			{
+				Name:        "deploy",
+				Usage:       "deploy a NeoVM contract",
+				Description: "Deploy an AVM file. The ABI manifest is a JSON file listing methods with their parameter and return types, and the events the contract notifies.",
+				Flags: append([]cli.Flag{
+					cli.StringFlag{
+						Name:  "avm, a",
+						Usage: "contract file, binary or hex encoded",
+					},
+					cli.StringFlag{
+						Name:  "abi",
+						Usage: "ABI manifest file",
+					},
+					cli.StringFlag{
+						Name:  "name, n",
+						Usage: "contract name",
+					},
+					cli.StringFlag{
+						Name:  "version, v",
+						Usage: "contract version",
+					},
+					cli.StringFlag{
+						Name:  "author",
+						Usage: "contract author",
+					},
+					cli.StringFlag{
+						Name:  "email",
+						Usage: "author email",
+					},
+					cli.StringFlag{
+						Name:  "desc",
+						Usage: "contract description",
+					},
+					cli.BoolFlag{
+						Name:  "storage",
+						Usage: "the contract uses storage",
+					},
+					cli.BoolFlag{
+						Name:  "dynamic-invoke",
+						Usage: "the contract calls code hashes taken from the stack",
+					},
+					cli.BoolFlag{
+						Name:  "reentrancy-guard",
+						Usage: "fault calls into the contract while it is running",
+					},
+				}, walletFlags...),
+				Action: deployAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "deploy")
+					return cli.NewExitError("", 1)
+				},
+			},
+			{
+				Name:        "invoke",
+				Usage:       "invoke a contract method",
+				Description: "Encode the arguments with the parameter types declared in the ABI of the contract and send an invoke transaction.",
+				Flags: append([]cli.Flag{
+					cli.StringFlag{
+						Name:  "codehash, c",
+						Usage: "contract code hash in hex",
+					},
+					cli.StringFlag{
+						Name:  "method, m",
+						Usage: "method name",
+					},
+					cli.StringFlag{
+						Name:  "args",
+						Usage: "arguments as a JSON array, e.g. '[\"AddressOrHash\", 100]'",
+					},
+					cli.BoolFlag{
+						Name:  "script",
+						Usage: "print the invocation script instead of sending a transaction",
+					},
+				}, walletFlags...),
+				Action: invokeAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "invoke")
+					return cli.NewExitError("", 1)
+				},
+			},
+		},
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"github.com/Ontology/account"
 	. "github.com/Ontology/cli/common"
+	"github.com/Ontology/common/config"
 	"github.com/Ontology/core/contract"
 	"github.com/Ontology/core/signature"
 	"github.com/Ontology/core/transaction"
@@ -54,6 +55,7 @@ func newContractContextWithoutProgramHashes(data signature.SignableData) *contra
 	}
 }
 func signTransaction(signer *account.Account, tx *transaction.Transaction) error {
+	tx.SetNetworkMagic(uint32(config.Parameters.Magic))
 	signature, err := signature.SignBySigner(tx, signer)
 	if err != nil {
 		fmt.Println("SignBySigner failed")
@@ -0,0 +1,234 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package db
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/Ontology/cli/common"
+	. "github.com/Ontology/common"
+	"github.com/Ontology/core/store"
+	"github.com/Ontology/core/store/ChainStore"
+
+	"github.com/urfave/cli"
+)
+
+const MerkleTreeFile = "merkle_tree.db"
+
+func newDataDirFlag() cli.Flag {
+	return cli.StringFlag{
+		Name:  "datadir, d",
+		Usage: "data directory of a stopped node",
+		Value: ChainStore.DBDir,
+	}
+}
+
+func openStore(dir string) (store.IStore, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	st, err := ChainStore.NewStore(dir)
+	if err != nil {
+		return nil, fmt.Errorf("open database failed: %v", err)
+	}
+	return st, nil
+}
+
+func printJSON(v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return FormatOutput(buf)
+}
+
+func scanAction(c *cli.Context) error {
+	var prefix []byte
+	if name := c.String("prefix"); name != "" {
+		p, err := ChainStore.ParsePrefix(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+		prefix = append(prefix, byte(p))
+	}
+	if key := c.String("key"); key != "" {
+		k, err := hex.DecodeString(key)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid key:", err)
+			return err
+		}
+		prefix = append(prefix, k...)
+	}
+	st, err := openStore(c.String("datadir"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	defer st.Close()
+
+	asJSON := c.Bool("json")
+	entries := make([]*ChainStore.Entry, 0)
+	ChainStore.ScanEntries(st, prefix, c.Int("limit"), func(e *ChainStore.Entry) bool {
+		if asJSON {
+			entries = append(entries, e)
+			return true
+		}
+		fmt.Printf("%s %s (%d bytes)\n", e.Prefix, e.Key, e.Size)
+		if e.Error != "" {
+			fmt.Printf("  error: %s\n", e.Error)
+		}
+		fmt.Printf("  %+v\n", e.Value)
+		return true
+	})
+	if asJSON {
+		return printJSON(entries)
+	}
+	return nil
+}
+
+func statsAction(c *cli.Context) error {
+	st, err := openStore(c.String("datadir"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	defer st.Close()
+
+	stats := ChainStore.CollectStats(st)
+	if c.Bool("json") {
+		return printJSON(stats)
+	}
+	fmt.Printf("%-22s %10s %14s %14s\n", "PREFIX", "COUNT", "KEY BYTES", "VALUE BYTES")
+	for _, s := range stats {
+		fmt.Printf("%-22s %10d %14d %14d\n", s.Prefix, s.Count, s.KeySize, s.ValueSize)
+	}
+	return nil
+}
+
+func verifyAction(c *cli.Context) error {
+	dir := c.String("datadir")
+	repair := c.Bool("repair")
+	st, err := openStore(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	defer st.Close()
+
+	result, err := ChainStore.VerifyStore(st, filepath.Join(dir, MerkleTreeFile), repair)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "verify database failed:", err)
+		return err
+	}
+	fmt.Println("current block height:", result.CurrentHeight)
+	fmt.Println("current block hash:  ", ToHexString(result.CurrentHash.ToArray()))
+	fmt.Println("verified blocks:     ", result.Blocks)
+	fmt.Println("verified txs:        ", result.Transactions)
+	for _, r := range result.Repaired {
+		fmt.Println("repaired:            ", r)
+	}
+	if result.Divergence != nil {
+		fmt.Println("first divergence:    ", result.Divergence.Error())
+		return cli.NewExitError("", 1)
+	}
+	fmt.Println("database is consistent")
+	return nil
+}
+
+func newJSONFlag() cli.Flag {
+	return cli.BoolFlag{
+		Name:  "json, j",
+		Usage: "print output as JSON",
+	}
+}
+
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "db",
+		Usage:       "inspect or verify the database of a stopped node",
+		Description: "With nodectl db, you could check a stopped node's chain database.",
+		ArgsUsage:   "[args]",
+		Subcommands: []cli.Command{
+			{
+				Name:        "scan",
+				Usage:       "list and decode records by key prefix",
+				Description: "Range scan the records whose key starts with the given data entry prefix and key bytes.",
+				Flags: []cli.Flag{
+					newDataDirFlag(),
+					cli.StringFlag{
+						Name:  "prefix, p",
+						Usage: "data entry prefix name (e.g. ST_Contract) or number",
+					},
+					cli.StringFlag{
+						Name:  "key, k",
+						Usage: "hex encoded key bytes following the prefix",
+					},
+					cli.IntFlag{
+						Name:  "limit, l",
+						Usage: "maximum number of records, 0 for no limit",
+						Value: 100,
+					},
+					newJSONFlag(),
+				},
+				Action: scanAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "scan")
+					return cli.NewExitError("", 1)
+				},
+			},
+			{
+				Name:        "stats",
+				Usage:       "count records and sizes per prefix",
+				Description: "Walk the whole database and report record counts and key/value sizes per data entry prefix.",
+				Flags: []cli.Flag{
+					newDataDirFlag(),
+					newJSONFlag(),
+				},
+				Action: statsAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "stats")
+					return cli.NewExitError("", 1)
+				},
+			},
+			{
+				Name:        "verify",
+				Usage:       "verify database integrity from genesis",
+				Description: "Walk blocks from genesis and check header linkage, transactions roots, block roots, unspent coins and the state trie against the state entries.",
+				Flags: []cli.Flag{
+					newDataDirFlag(),
+					cli.BoolFlag{
+						Name:  "repair, r",
+						Usage: "rebuild derived indexes that do not match the header chain",
+					},
+				},
+				Action: verifyAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "verify")
+					return cli.NewExitError("", 1)
+				},
+			},
+		},
+	}
+}
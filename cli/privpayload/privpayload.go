@@ -27,6 +27,7 @@ import (
 	"fmt"
 	"github.com/Ontology/account"
 	. "github.com/Ontology/cli/common"
+	"github.com/Ontology/common/config"
 	"github.com/Ontology/core/contract"
 	"github.com/Ontology/core/signature"
 	"github.com/Ontology/core/transaction"
@@ -64,6 +65,7 @@ func makePrivacyTx(admin *account.Account, toPubkeyStr string, pload string) (st
 }
 
 func signTransaction(signer *account.Account, tx *transaction.Transaction) error {
+	tx.SetNetworkMagic(uint32(config.Parameters.Magic))
 	signature, err := signature.SignBySigner(tx, signer)
 	if err != nil {
 		fmt.Println("SignBySigner failed")
@@ -0,0 +1,415 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package tx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/Ontology/account"
+	. "github.com/Ontology/cli/common"
+	"github.com/Ontology/common"
+	"github.com/Ontology/core/contract"
+	"github.com/Ontology/core/states"
+	"github.com/Ontology/core/transaction"
+	"github.com/Ontology/core/transaction/utxo"
+	"github.com/Ontology/crypto"
+	"github.com/Ontology/net/httpjsonrpc"
+
+	"github.com/urfave/cli"
+)
+
+// nodeTxStore looks up the transactions referenced by the inputs of a
+// transaction on the node, for the program hashes it is verified with.
+type nodeTxStore struct{}
+
+func (nodeTxStore) GetTransaction(hash common.Uint256) (*transaction.Transaction, error) {
+	resp, err := httpjsonrpc.Call(Address(), "getrawtransaction", 0, []interface{}{common.ToHexString(hash.ToArray())})
+	if err != nil {
+		return nil, err
+	}
+	var r struct {
+		Result struct {
+			Outputs []httpjsonrpc.TxoutputInfo
+		}
+	}
+	if err := json.Unmarshal(resp, &r); err != nil {
+		return nil, err
+	}
+	if r.Result.Outputs == nil {
+		return nil, fmt.Errorf("unknown transaction %x", hash.ToArray())
+	}
+	tx := new(transaction.Transaction)
+	for _, o := range r.Result.Outputs {
+		assetID, err := common.HexToBytes(o.AssetID)
+		if err != nil {
+			return nil, err
+		}
+		programHash, err := common.HexToBytes(o.ProgramHash)
+		if err != nil {
+			return nil, err
+		}
+		output := &utxo.TxOutput{Value: o.Value}
+		if output.AssetID, err = common.Uint256ParseFromBytes(assetID); err != nil {
+			return nil, err
+		}
+		if output.ProgramHash, err = common.Uint160ParseFromBytes(programHash); err != nil {
+			return nil, err
+		}
+		tx.Outputs = append(tx.Outputs, output)
+	}
+	return tx, nil
+}
+
+func (nodeTxStore) GetQuantityIssued(assetID common.Uint256) (common.Fixed64, error) {
+	return 0, errors.New("the quantity issued of an asset is not available from the node")
+}
+
+func (nodeTxStore) GetAsset(assetID common.Uint256) (*states.AssetState, error) {
+	return nil, errors.New("the asset state is not available from the node")
+}
+
+// readPartial reads a partially signed transaction file, hex encoded.
+func readPartial(path string) (*transaction.PartialTransaction, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, err
+	}
+	partial := new(transaction.PartialTransaction)
+	if err := partial.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	return partial, nil
+}
+
+func writePartial(path string, partial *transaction.PartialTransaction) error {
+	var buffer bytes.Buffer
+	if err := partial.Serialize(&buffer); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, []byte(hex.EncodeToString(buffer.Bytes())), 0644); err != nil {
+		return err
+	}
+	printStatus(partial)
+	return nil
+}
+
+func printStatus(partial *transaction.PartialTransaction) {
+	hash := partial.Tx.Hash()
+	fmt.Printf("transaction: %x\n", hash.ToArray())
+	if partial.Tx.HasNetworkMagic() {
+		fmt.Printf("network magic: %d\n", partial.Tx.NetworkMagic)
+	} else {
+		fmt.Println("network magic: none, the transaction is valid on any network")
+	}
+	for i, hash := range partial.Context.ProgramHashes {
+		address, _ := hash.ToAddress()
+		c, err := contract.ParseMultiSigContract(partial.Context.Codes[i])
+		if err != nil {
+			continue
+		}
+		fmt.Printf("%s: %d of %d signatures\n", address, len(partial.Context.MultiPubkeyPara[i]), len(c.Parameters))
+	}
+	if partial.IsCompleted() {
+		fmt.Println("completed")
+	}
+}
+
+// newPartial starts a partially signed transaction from an unsigned one,
+// spending from the m of n contract of the public keys. A magic binds it to
+// that network.
+func newPartial(rawHex string, m int, pubkeysHex string, magic uint) (*transaction.PartialTransaction, error) {
+	raw, err := hex.DecodeString(rawHex)
+	if err != nil {
+		return nil, err
+	}
+	tx := new(transaction.Transaction)
+	if err := tx.DeserializeUnsigned(bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	if magic != 0 {
+		tx.SetNetworkMagic(uint32(magic))
+	}
+	var pubkeys []*crypto.PubKey
+	for _, s := range strings.Split(pubkeysHex, ",") {
+		b, err := hex.DecodeString(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		pk, err := crypto.DecodePoint(b)
+		if err != nil {
+			return nil, err
+		}
+		pubkeys = append(pubkeys, pk)
+	}
+	if m < 1 || m > len(pubkeys) {
+		return nil, errors.New("invalid number of signatures [--m]")
+	}
+	multisig, err := contract.CreateMultiSigContract(common.Uint160{}, m, pubkeys)
+	if err != nil {
+		return nil, err
+	}
+	transaction.TxStore = nodeTxStore{}
+	return transaction.NewPartialTransaction(tx, []*contract.Contract{multisig})
+}
+
+func signAction(c *cli.Context) error {
+	file := c.String("file")
+	if file == "" {
+		fmt.Println("missing flag [--file]")
+		return nil
+	}
+	var partial *transaction.PartialTransaction
+	var err error
+	if raw := c.String("raw"); raw != "" {
+		partial, err = newPartial(raw, c.Int("m"), c.String("pubkeys"), c.Uint("magic"))
+	} else {
+		partial, err = readPartial(file)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	if !c.Bool("nosign") {
+		wallet := account.Open(c.String("wallet"), WalletPassword(c.String("password")))
+		if wallet == nil {
+			fmt.Println("Failed to open wallet: ", c.String("wallet"))
+			os.Exit(1)
+		}
+		signer, err := wallet.GetDefaultAccount()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+		if err := partial.Sign(signer); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+	}
+	out := c.String("out")
+	if out == "" {
+		out = file
+	}
+	return writePartial(out, partial)
+}
+
+func combineAction(c *cli.Context) error {
+	files := c.StringSlice("file")
+	if len(files) < 2 || c.String("out") == "" {
+		fmt.Println("missing flag [--file] (at least two) or [--out]")
+		return nil
+	}
+	var partial *transaction.PartialTransaction
+	for _, file := range files {
+		p, err := readPartial(file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, file, err)
+			return err
+		}
+		if partial == nil {
+			partial = p
+		} else if err := partial.Combine(p); err != nil {
+			fmt.Fprintln(os.Stderr, file, err)
+			return err
+		}
+	}
+	return writePartial(c.String("out"), partial)
+}
+
+func broadcastAction(c *cli.Context) error {
+	if c.String("file") == "" {
+		fmt.Println("missing flag [--file]")
+		return nil
+	}
+	partial, err := readPartial(c.String("file"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	tx, err := partial.Complete()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	var buffer bytes.Buffer
+	if err := tx.Serialize(&buffer); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	resp, err := httpjsonrpc.Call(Address(), "sendrawtransaction", 0, []interface{}{hex.EncodeToString(buffer.Bytes())})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	return FormatOutput(resp)
+}
+
+func decodeAction(c *cli.Context) error {
+	if c.String("raw") == "" {
+		fmt.Println("missing flag [--raw]")
+		return nil
+	}
+	raw, err := hex.DecodeString(c.String("raw"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	var tx transaction.Transaction
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	obj, err := tx.ToJson()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	data, err := json.MarshalIndent(obj, "", "\t")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	hash := tx.Hash()
+	fmt.Println(string(data))
+	fmt.Println("Hash:", common.ToHexString(hash.ToArray()))
+	return nil
+}
+
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "tx",
+		Usage:       "sign multi-signature transactions and decode transactions",
+		Description: "With nodectl tx, the co-signers of a multi-signature contract pass a partially signed transaction file around, each adding a signature, then broadcast it.",
+		ArgsUsage:   "[args]",
+		Subcommands: []cli.Command{
+			{
+				Name:        "sign",
+				Usage:       "add the signature of the wallet to a partially signed transaction",
+				Description: "Sign the file in place, or start it from an unsigned transaction with [--raw], [--m] and [--pubkeys]. The contract must be the only one the transaction is verified with, starting the file looks up the spent outputs on the node. Signing an existing file needs no node.",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "partially signed transaction file",
+					},
+					cli.StringFlag{
+						Name:  "out, o",
+						Usage: "output file, the input file if not given",
+					},
+					cli.StringFlag{
+						Name:  "raw",
+						Usage: "unsigned transaction in hex to start the file from",
+					},
+					cli.IntFlag{
+						Name:  "m",
+						Usage: "number of signatures the contract requires",
+					},
+					cli.StringFlag{
+						Name:  "pubkeys",
+						Usage: "public keys of the contract in hex, separated by commas",
+					},
+					cli.UintFlag{
+						Name:  "magic",
+						Usage: "magic of the network the transaction started with [--raw] is valid on",
+					},
+					cli.BoolFlag{
+						Name:  "nosign",
+						Usage: "only start the file, without signing it",
+					},
+					cli.StringFlag{
+						Name:  "wallet, w",
+						Usage: "wallet name",
+						Value: account.WalletFileName,
+					},
+					cli.StringFlag{
+						Name:  "password, p",
+						Usage: "wallet password",
+					},
+				},
+				Action: signAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "sign")
+					return cli.NewExitError("", 1)
+				},
+			},
+			{
+				Name:        "combine",
+				Usage:       "combine the signatures of partially signed transaction files",
+				Description: "Merge the signatures the co-signers added to their copies of the same transaction.",
+				Flags: []cli.Flag{
+					cli.StringSliceFlag{
+						Name:  "file, f",
+						Usage: "partially signed transaction file, given once per file",
+					},
+					cli.StringFlag{
+						Name:  "out, o",
+						Usage: "output file",
+					},
+				},
+				Action: combineAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "combine")
+					return cli.NewExitError("", 1)
+				},
+			},
+			{
+				Name:        "broadcast",
+				Usage:       "send a completely signed transaction",
+				Description: "Set the programs of a partially signed transaction with enough signatures and send it to the node.",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "partially signed transaction file",
+					},
+				},
+				Action: broadcastAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "broadcast")
+					return cli.NewExitError("", 1)
+				},
+			},
+			{
+				Name:        "decode",
+				Usage:       "print a raw transaction as JSON",
+				Description: "Print the canonical JSON form of a transaction, which the buildtransaction RPC turns back into the same bytes.",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "raw",
+						Usage: "transaction in hex",
+					},
+				},
+				Action: decodeAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "decode")
+					return cli.NewExitError("", 1)
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vm
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/vm/neovm/asm"
+
+	"github.com/urfave/cli"
+)
+
+func disasmAction(c *cli.Context) error {
+	var code []byte
+	var err error
+	switch {
+	case c.String("avm") != "":
+		code, err = readAVM(c.String("avm"))
+	case c.String("code") != "":
+		code, err = hex.DecodeString(strings.TrimPrefix(c.String("code"), "0x"))
+	default:
+		err = errors.New("missing contract file or code")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	fmt.Print(asm.DisassembleString(code))
+	return nil
+}
+
+func asmAction(c *cli.Context) error {
+	var src []byte
+	var err error
+	if file := c.String("file"); file != "" {
+		src, err = ioutil.ReadFile(file)
+	} else {
+		src, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	code, err := asm.Assemble(string(src))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	if out := c.String("output"); out != "" {
+		if err := ioutil.WriteFile(out, code, 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+		return nil
+	}
+	fmt.Println(ToHexString(code))
+	return nil
+}
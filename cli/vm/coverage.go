@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	. "github.com/Ontology/cli/common"
+	. "github.com/Ontology/common"
+	"github.com/Ontology/smartcontract/trace"
+	"github.com/Ontology/vm/neovm"
+	"github.com/Ontology/vm/neovm/asm"
+
+	"github.com/urfave/cli"
+)
+
+// runTraced executes one invocation script and returns its recorded steps.
+func runTraced(c *cli.Context, input string) (*session, *trace.Recorder, error) {
+	s, err := newSession(c, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	recorder := trace.NewRecorder(trace.DefaultMaxSteps)
+	s.engine.SetTracer(recorder)
+	err = s.engine.Execute()
+	if err == nil && s.engine.GetState()&neovm.FAULT != 0 {
+		err = errors.New("FAULT")
+	}
+	return s, recorder, err
+}
+
+func coverageAction(c *cli.Context) error {
+	inputs := c.StringSlice("input")
+	if len(inputs) == 0 {
+		err := errors.New("missing invocation script")
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	avm, err := readAVM(c.String("avm"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	codeHash, err := ToCodeHash(avm)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+
+	coverage := trace.NewCoverage()
+	for i, input := range inputs {
+		s, recorder, err := runTraced(c, input)
+		if s == nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+		s.Close()
+		if c.Bool("trace") {
+			for _, step := range recorder.Steps() {
+				fmt.Printf("%s %04x %-12s stack=%d depth=%d\n", ToHexString(step.CodeHash.ToArray()),
+					step.IP, asm.OpName(step.OpCode), step.StackDepth, step.Depth)
+			}
+		}
+		result := "HALT"
+		if err != nil {
+			result = err.Error()
+		}
+		fmt.Printf("input %d: %s, %d steps\n", i, result, len(recorder.Steps()))
+		coverage.Add(recorder.Steps())
+	}
+
+	report := coverage.Report(codeHash, avm)
+	if c.Bool("json") {
+		buf, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		if err := FormatOutput(buf); err != nil {
+			return err
+		}
+	} else {
+		fmt.Print(report.Format())
+	}
+	if min := c.Float64("min-branch"); report.BranchRate() < min {
+		fmt.Fprintf(os.Stderr, "branch coverage %.1f%% below %.1f%%\n", report.BranchRate(), min)
+		return cli.NewExitError("", 1)
+	}
+	return nil
+}
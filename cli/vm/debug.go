@@ -0,0 +1,221 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/vm/neovm"
+	"github.com/Ontology/vm/neovm/asm"
+
+	"github.com/urfave/cli"
+)
+
+const debugHelp = `commands:
+  s, step              execute the next instruction, entering calls
+  n, next              execute the next instruction, running calls to completion
+  o, out               run until the current context returns
+  c, continue          run until a breakpoint, halt or fault
+  b, break [[hash:]offset]
+                       add a breakpoint, or list them without argument
+  d, delete [hash:]offset
+                       remove a breakpoint
+  st, stack            show the evaluation stack
+  alt                  show the alt stack
+  bt, calls            show the invocation stack
+  h, help              show this help
+  q, quit              stop debugging`
+
+// parseBreakPoint accepts "offset" in the debugged contract or "codehash:offset".
+func (s *session) parseBreakPoint(arg string) (Uint160, uint, error) {
+	codeHash := s.codeHash
+	if i := strings.Index(arg, ":"); i >= 0 {
+		b, err := HexToBytes(arg[:i])
+		if err != nil {
+			return Uint160{}, 0, err
+		}
+		if codeHash, err = Uint160ParseFromBytes(b); err != nil {
+			return Uint160{}, 0, err
+		}
+		arg = arg[i+1:]
+	}
+	offset, err := strconv.ParseUint(arg, 0, 32)
+	if err != nil {
+		return Uint160{}, 0, fmt.Errorf("invalid offset %q", arg)
+	}
+	return codeHash, uint(offset), nil
+}
+
+func (s *session) printPosition() {
+	engine := s.engine
+	if engine.GetState()&neovm.HALT != 0 {
+		fmt.Println("HALT")
+		return
+	}
+	if engine.GetState()&neovm.FAULT != 0 {
+		fmt.Println("FAULT")
+		return
+	}
+	context, err := engine.CurrentContext()
+	if err != nil {
+		fmt.Println("no context")
+		return
+	}
+	codeHash, _ := context.GetCodeHash()
+	ip := context.GetInstructionPointer()
+	op := "RET"
+	if ip < len(context.Code) {
+		op = asm.OpName(context.NextInstruction())
+	}
+	fmt.Printf("%s:%d  %s\n", ToHexString(codeHash.ToArray()), ip, op)
+}
+
+func (s *session) printInvocationStack() {
+	stack := s.engine.GetInvocationStack()
+	for i := 0; i < stack.Count(); i++ {
+		context := stack.Peek(i).GetExecutionContext()
+		codeHash, _ := context.GetCodeHash()
+		fmt.Printf("%3d  %s:%d\n", i, ToHexString(codeHash.ToArray()), context.GetInstructionPointer())
+	}
+}
+
+func printLines(lines []string) {
+	if len(lines) == 0 {
+		fmt.Println("  (empty)")
+	}
+	for _, l := range lines {
+		fmt.Println(l)
+	}
+}
+
+func (s *session) finished() bool {
+	return s.engine.GetState()&(neovm.HALT|neovm.FAULT) != 0
+}
+
+// command runs one debugger command and reports whether the session should end.
+func (s *session) command(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false, nil
+	}
+	engine := s.engine
+	var err error
+	switch fields[0] {
+	case "s", "step":
+		if s.finished() {
+			break
+		}
+		err = engine.StepInto()
+		s.printPosition()
+	case "n", "next":
+		err = engine.StepOver()
+		s.printPosition()
+	case "o", "out":
+		err = engine.StepOut()
+		s.printPosition()
+	case "c", "continue":
+		err = engine.Execute()
+		s.printPosition()
+	case "b", "break":
+		if len(fields) < 2 {
+			for _, bp := range engine.GetCodeBreakPoints() {
+				fmt.Printf("%s:%d\n", ToHexString(bp.CodeHash.ToArray()), bp.Offset)
+			}
+			break
+		}
+		codeHash, offset, err := s.parseBreakPoint(fields[1])
+		if err != nil {
+			return false, err
+		}
+		engine.AddCodeBreakPoint(codeHash, offset)
+	case "d", "delete":
+		if len(fields) < 2 {
+			return false, errors.New("missing breakpoint")
+		}
+		codeHash, offset, err := s.parseBreakPoint(fields[1])
+		if err != nil {
+			return false, err
+		}
+		if !engine.RemoveCodeBreakPoint(codeHash, offset) {
+			return false, errors.New("no such breakpoint")
+		}
+	case "st", "stack":
+		printLines(formatStack(engine.GetEvaluationStack()))
+	case "alt":
+		printLines(formatStack(engine.GetAltStack()))
+	case "bt", "calls":
+		s.printInvocationStack()
+	case "h", "help":
+		fmt.Println(debugHelp)
+	case "q", "quit":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q, type help for a list", fields[0])
+	}
+	return false, err
+}
+
+func debugAction(c *cli.Context) error {
+	s, err := newSession(c, c.String("input"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	defer s.Close()
+
+	for _, arg := range c.StringSlice("break") {
+		codeHash, offset, err := s.parseBreakPoint(arg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+		s.engine.AddCodeBreakPoint(codeHash, offset)
+	}
+	fmt.Println("contract:", ToHexString(s.codeHash.ToArray()))
+	s.printPosition()
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("(vm) ")
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		quit, cmdErr := s.command(line)
+		if cmdErr != nil {
+			fmt.Println("error:", cmdErr)
+		}
+		if quit || err == io.EOF {
+			break
+		}
+	}
+	if s.finished() {
+		fmt.Println("evaluation stack:")
+		printLines(formatStack(s.engine.GetEvaluationStack()))
+	}
+	return nil
+}
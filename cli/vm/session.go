@@ -0,0 +1,195 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vm
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/config"
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract"
+	"github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/states"
+	"github.com/Ontology/core/store"
+	"github.com/Ontology/core/store/ChainStore"
+	"github.com/Ontology/core/transaction"
+	"github.com/Ontology/smartcontract/native"
+	"github.com/Ontology/smartcontract/service"
+	"github.com/Ontology/smartcontract/types"
+	"github.com/Ontology/vm/neovm"
+	vmtypes "github.com/Ontology/vm/neovm/types"
+
+	"github.com/urfave/cli"
+)
+
+// session holds an execution engine loaded with a contract and its invocation
+// script, running against a state that is never committed.
+type session struct {
+	engine   *neovm.ExecutionEngine
+	codeHash common.Uint160
+	chain    *ChainStore.ChainStore
+}
+
+func readAVM(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// accept hex encoded files as produced by some compilers
+	if s := strings.TrimSpace(string(data)); len(s) > 0 && len(s)%2 == 0 {
+		if b, err := hex.DecodeString(s); err == nil {
+			return b, nil
+		}
+	}
+	return data, nil
+}
+
+// newSession loads the contract given by the avm flag and the hex encoded
+// invocation script into a fresh engine.
+func newSession(c *cli.Context, script string) (*session, error) {
+	if c.String("avm") == "" {
+		return nil, errors.New("missing contract file")
+	}
+	avm, err := readAVM(c.String("avm"))
+	if err != nil {
+		return nil, err
+	}
+	input, err := hex.DecodeString(script)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invocation script: %v", err)
+	}
+
+	var st store.IStore
+	if dir := c.String("datadir"); dir != "" {
+		st, err = ChainStore.NewStore(dir)
+	} else {
+		st, err = ChainStore.NewMemStore()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open database failed: %v", err)
+	}
+	chain, err := ChainStore.NewSandboxChainStore(st)
+	if err != nil {
+		st.Close()
+		return nil, err
+	}
+	if ledger.DefaultLedger == nil {
+		ledger.DefaultLedger = &ledger.Ledger{Store: chain, Blockchain: ledger.NewBlockchain(chain.GetHeight())}
+	}
+
+	stateStore := chain.NewSandboxStateStore()
+	codeHash, err := common.ToCodeHash(avm)
+	if err != nil {
+		chain.Close()
+		return nil, err
+	}
+	contractState := &states.ContractState{
+		Code:        &code.FunctionCode{Code: avm, ReturnType: contract.Void},
+		VmType:      types.NEOVM,
+		NeedStorage: true,
+		Name:        c.String("avm"),
+	}
+	if err := stateStore.TryGetOrAdd(store.ST_Contract, codeHash.ToArray(), contractState, false); err != nil {
+		chain.Close()
+		return nil, err
+	}
+	tx, err := transaction.NewInvokeTransaction(input, codeHash)
+	if err != nil {
+		chain.Close()
+		return nil, err
+	}
+	block := &ledger.Block{
+		Header: &ledger.Header{
+			Height:    chain.GetHeight() + 1,
+			Timestamp: uint32(time.Now().Unix()),
+		},
+	}
+
+	stateMachine := service.NewStateMachine(stateStore, types.Application, block)
+	services := stateMachine.GetServiceMap()
+	runtimeLog := services["Neo.Runtime.Log"]
+	stateMachine.Register("Neo.Runtime.Log", func(e *neovm.ExecutionEngine) (bool, error) {
+		if neovm.EvaluationStackCount(e) > 0 {
+			fmt.Printf("log: %s\n", neovm.PeekStackItem(e).GetByteArray())
+		}
+		return runtimeLog(e)
+	})
+	runtimeNotify := services["Neo.Runtime.Notify"]
+	stateMachine.Register("Neo.Runtime.Notify", func(e *neovm.ExecutionEngine) (bool, error) {
+		if neovm.EvaluationStackCount(e) > 0 {
+			fmt.Printf("notify: %s\n", formatItem(neovm.PeekStackItem(e)))
+		}
+		return runtimeNotify(e)
+	})
+
+	engine := neovm.NewExecutionEngine(tx, new(neovm.ECDsaCrypto), ChainStore.NewCacheCodeTable(stateStore), stateMachine)
+	engine.SetMaxCallDepth(config.Parameters.MaxCallDepth)
+	engine.SetNativeInvoker(native.NewInvoker(stateMachine, tx))
+	engine.LoadCode(avm, false)
+	engine.LoadCode(input, false)
+	return &session{engine: engine, codeHash: codeHash, chain: chain}, nil
+}
+
+func (s *session) Close() {
+	s.chain.Close()
+}
+
+func formatItem(item vmtypes.StackItemInterface) string {
+	switch v := item.(type) {
+	case nil:
+		return "null"
+	case *vmtypes.ByteArray:
+		return fmt.Sprintf("0x%x", v.GetByteArray())
+	case *vmtypes.Integer:
+		return v.GetBigInteger().String()
+	case *vmtypes.Boolean:
+		return fmt.Sprintf("%v", v.GetBoolean())
+	case *vmtypes.Array, *vmtypes.Struct:
+		var items []string
+		for _, e := range v.GetArray() {
+			items = append(items, formatItem(e))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case *vmtypes.Map:
+		var items []string
+		for _, k := range v.GetKeys() {
+			value, _ := v.TryGetValue(k)
+			items = append(items, formatItem(k)+": "+formatItem(value))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	case *vmtypes.InteropInterface:
+		return fmt.Sprintf("interop(%T)", v.GetInterface())
+	}
+	return fmt.Sprintf("%T", item)
+}
+
+func formatStack(stack *neovm.RandomAccessStack) []string {
+	var lines []string
+	for i := 0; i < stack.Count(); i++ {
+		lines = append(lines, fmt.Sprintf("%3d  %s", i, formatItem(stack.Peek(i).GetStackItem())))
+	}
+	return lines
+}
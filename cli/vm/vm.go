@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vm
+
+import (
+	. "github.com/Ontology/cli/common"
+
+	"github.com/urfave/cli"
+)
+
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "vm",
+		Usage:       "run and debug NeoVM contracts locally",
+		Description: "With nodectl vm, you could run contracts without a running node.",
+		ArgsUsage:   "[args]",
+		Subcommands: []cli.Command{
+			{
+				Name:        "debug",
+				Usage:       "step through a contract interactively",
+				Description: "Load an AVM file and an invocation script, then step, set breakpoints and inspect the stacks. Runtime.Log and Runtime.Notify output is printed inline.",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "avm, a",
+						Usage: "contract file, binary or hex encoded",
+					},
+					cli.StringFlag{
+						Name:  "input, i",
+						Usage: "hex encoded invocation script pushing the parameters",
+					},
+					cli.StringFlag{
+						Name:  "datadir, d",
+						Usage: "data directory of a stopped node to read state from, in-memory state if empty",
+					},
+					cli.StringSliceFlag{
+						Name:  "break, b",
+						Usage: "breakpoint as offset or codehash:offset, may be repeated",
+					},
+				},
+				Action: debugAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "debug")
+					return cli.NewExitError("", 1)
+				},
+			},
+			{
+				Name:        "coverage",
+				Usage:       "run invocation scripts and report contract coverage",
+				Description: "Run the contract once per invocation script with tracing on, then print the disassembly annotated with hit counts and conditional jump outcomes.",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "avm, a",
+						Usage: "contract file, binary or hex encoded",
+					},
+					cli.StringSliceFlag{
+						Name:  "input, i",
+						Usage: "hex encoded invocation script, may be repeated",
+					},
+					cli.StringFlag{
+						Name:  "datadir, d",
+						Usage: "data directory of a stopped node to read state from, in-memory state if empty",
+					},
+					cli.BoolFlag{
+						Name:  "trace, t",
+						Usage: "print every executed instruction",
+					},
+					cli.BoolFlag{
+						Name:  "json, j",
+						Usage: "print the report as JSON",
+					},
+					cli.Float64Flag{
+						Name:  "min-branch",
+						Usage: "exit with an error if branch coverage is below this percentage",
+					},
+				},
+				Action: coverageAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "coverage")
+					return cli.NewExitError("", 1)
+				},
+			},
+			{
+				Name:        "disasm",
+				Usage:       "disassemble contract bytecode",
+				Description: "Print the opcodes of an AVM file or hex encoded script in the text form accepted by nodectl vm asm.",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "avm, a",
+						Usage: "contract file, binary or hex encoded",
+					},
+					cli.StringFlag{
+						Name:  "code, c",
+						Usage: "hex encoded bytecode",
+					},
+				},
+				Action: disasmAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "disasm")
+					return cli.NewExitError("", 1)
+				},
+			},
+			{
+				Name:        "asm",
+				Usage:       "assemble opcodes into contract bytecode",
+				Description: "Assemble the text form printed by nodectl vm disasm, with labels as jump targets, and print the bytecode as hex.",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "file, f",
+						Usage: "assembly source file, read from stdin if empty",
+					},
+					cli.StringFlag{
+						Name:  "output, o",
+						Usage: "write the binary AVM to this file instead of printing hex",
+					},
+				},
+				Action: asmAction,
+				OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
+					PrintError(c, err, "asm")
+					return cli.NewExitError("", 1)
+				},
+			},
+		},
+	}
+}
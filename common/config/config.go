@@ -21,7 +21,10 @@ package config
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
@@ -67,6 +70,13 @@ type Configuration struct {
 	MaxTxInBlock    int      `json:"MaxTransactionInBlock"`
 	MaxHdrSyncReqs  int      `json:"MaxConcurrentSyncHeaderReqs"`
 	ConsensusType   string           `json:"ConsensusType"`
+	MaxCallDepth    int              `json:"MaxCallDepth"`
+	MagicHeight     uint32           `json:"MagicHeight"`    // transactions in blocks from this height on must carry the network magic, none have to if zero
+	EnableTrace     bool             `json:"EnableTrace"`
+	TraceMaxSteps   int              `json:"TraceMaxSteps"`  // steps kept over all traces, trace.DefaultMaxSteps if zero
+	TraceContracts  []string         `json:"TraceContracts"` // code hashes traced, all contracts if empty
+	SystemFee       map[string]int64 `json:"SystemFee"`      // system fee by transaction type name, RegisterAsset and IssueAsset pay none
+	SystemFeeAsset  string           `json:"SystemFeeAsset"` // asset id paying the system fee, no fee is charged if empty
 }
 
 type ConfigFile struct {
@@ -90,5 +100,41 @@ func init() {
 		log.Fatalf("Unmarshal json file erro %v", e)
 		os.Exit(1)
 	}
+	if e = checkSystemFee(&config.ConfigFile); e != nil {
+		log.Fatalf("Invalid system fee configuration: %v", e)
+		os.Exit(1)
+	}
 	Parameters = &(config.ConfigFile)
 }
+
+// noSystemFee lists the transaction types paying no system fee, they
+// reference no inputs to pay it with.
+var noSystemFee = map[string]bool{
+	"RegisterAsset": true,
+	"IssueAsset":    true,
+}
+
+// checkSystemFee rejects a malformed SystemFeeAsset or negative fee and warns
+// about the fees which are not charged.
+func checkSystemFee(c *Configuration) error {
+	if c.SystemFeeAsset != "" {
+		b, err := hex.DecodeString(c.SystemFeeAsset)
+		if err != nil || len(b) != 32 {
+			return errors.New(fmt.Sprintf("SystemFeeAsset %q is not an asset id", c.SystemFeeAsset))
+		}
+	}
+	for name, fee := range c.SystemFee {
+		if fee < 0 {
+			return errors.New(fmt.Sprintf("negative SystemFee of %s", name))
+		}
+		if fee == 0 {
+			continue
+		}
+		if noSystemFee[name] {
+			log.Printf("SystemFee of %s is ignored, it pays no system fee", name)
+		} else if c.SystemFeeAsset == "" {
+			log.Printf("SystemFee of %s is not charged, no SystemFeeAsset is configured", name)
+		}
+	}
+	return nil
+}
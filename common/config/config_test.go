@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import "testing"
+
+func TestCheckSystemFee(t *testing.T) {
+	asset := "f4d9a3e7c3b3b6d0a5e2a1d1c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9"
+	cases := []struct {
+		name string
+		c    Configuration
+		ok   bool
+	}{
+		{"no fee asset", Configuration{SystemFee: map[string]int64{"AssetRenew": 1}}, true},
+		{"fee asset", Configuration{SystemFee: map[string]int64{"AssetRenew": 1}, SystemFeeAsset: asset}, true},
+		{"malformed fee asset", Configuration{SystemFeeAsset: asset[1:]}, false},
+		{"short fee asset", Configuration{SystemFeeAsset: asset[2:]}, false},
+		{"negative fee", Configuration{SystemFee: map[string]int64{"AssetRenew": -1}, SystemFeeAsset: asset}, false},
+	}
+	for _, c := range cases {
+		if err := checkSystemFee(&c.c); (err == nil) != c.ok {
+			t.Errorf("%s: got %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package code
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Ontology/common/serialization"
+	. "github.com/Ontology/core/contract"
+	. "github.com/Ontology/errors"
+)
+
+// MaxABIEntries bounds the number of methods, events and parameters of each.
+const MaxABIEntries = 1024
+
+type ABIParameter struct {
+	Name string                `json:"name"`
+	Type ContractParameterType `json:"type"`
+}
+
+type ABIMethod struct {
+	Name       string                `json:"name"`
+	Parameters []*ABIParameter       `json:"parameters"`
+	ReturnType ContractParameterType `json:"returntype"`
+}
+
+// ABIEvent describes a Runtime.Notify whose state is an array of the event
+// name followed by the parameters.
+type ABIEvent struct {
+	Name       string          `json:"name"`
+	Parameters []*ABIParameter `json:"parameters"`
+}
+
+// ABI is the manifest of the methods and events of a contract. It is given
+// at deploy time and stored with the contract.
+type ABI struct {
+	Methods []*ABIMethod `json:"methods"`
+	Events  []*ABIEvent  `json:"events"`
+}
+
+// ParseABI reads a JSON manifest.
+func ParseABI(data []byte) (*ABI, error) {
+	abi := new(ABI)
+	if err := json.Unmarshal(data, abi); err != nil {
+		return nil, NewDetailErr(err, ErrNoCode, "[ParseABI] Invalid manifest.")
+	}
+	if err := abi.Check(); err != nil {
+		return nil, err
+	}
+	return abi, nil
+}
+
+// Check rejects unnamed or duplicate methods and events.
+func (abi *ABI) Check() error {
+	if len(abi.Methods) > MaxABIEntries || len(abi.Events) > MaxABIEntries {
+		return NewErr("[ABI] Too many entries.")
+	}
+	methods := make(map[string]bool)
+	for _, m := range abi.Methods {
+		if m.Name == "" || methods[m.Name] {
+			return NewErr(fmt.Sprintf("[ABI] Invalid or duplicate method name %q.", m.Name))
+		}
+		methods[m.Name] = true
+		if len(m.Parameters) > MaxABIEntries {
+			return NewErr(fmt.Sprintf("[ABI] Method %s has too many parameters.", m.Name))
+		}
+	}
+	events := make(map[string]bool)
+	for _, e := range abi.Events {
+		if e.Name == "" || events[e.Name] {
+			return NewErr(fmt.Sprintf("[ABI] Invalid or duplicate event name %q.", e.Name))
+		}
+		events[e.Name] = true
+		if len(e.Parameters) > MaxABIEntries {
+			return NewErr(fmt.Sprintf("[ABI] Event %s has too many parameters.", e.Name))
+		}
+	}
+	return nil
+}
+
+func (abi *ABI) GetMethod(name string) *ABIMethod {
+	for _, m := range abi.Methods {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+func (abi *ABI) GetEvent(name string) *ABIEvent {
+	for _, e := range abi.Events {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+func serializeParameters(w io.Writer, params []*ABIParameter) error {
+	if err := serialization.WriteVarUint(w, uint64(len(params))); err != nil {
+		return err
+	}
+	for _, p := range params {
+		if err := serialization.WriteVarString(w, p.Name); err != nil {
+			return err
+		}
+		if err := serialization.WriteByte(w, byte(p.Type)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deserializeParameters(r io.Reader) ([]*ABIParameter, error) {
+	n, err := serialization.ReadVarUint(r, MaxABIEntries)
+	if err != nil {
+		return nil, err
+	}
+	params := make([]*ABIParameter, 0, n)
+	for i := uint64(0); i < n; i++ {
+		p := new(ABIParameter)
+		if p.Name, err = serialization.ReadVarString(r); err != nil {
+			return nil, err
+		}
+		t, err := serialization.ReadByte(r)
+		if err != nil {
+			return nil, err
+		}
+		p.Type = ContractParameterType(t)
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+func (abi *ABI) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarUint(w, uint64(len(abi.Methods))); err != nil {
+		return err
+	}
+	for _, m := range abi.Methods {
+		if err := serialization.WriteVarString(w, m.Name); err != nil {
+			return err
+		}
+		if err := serializeParameters(w, m.Parameters); err != nil {
+			return err
+		}
+		if err := serialization.WriteByte(w, byte(m.ReturnType)); err != nil {
+			return err
+		}
+	}
+	if err := serialization.WriteVarUint(w, uint64(len(abi.Events))); err != nil {
+		return err
+	}
+	for _, e := range abi.Events {
+		if err := serialization.WriteVarString(w, e.Name); err != nil {
+			return err
+		}
+		if err := serializeParameters(w, e.Parameters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (abi *ABI) Deserialize(r io.Reader) error {
+	n, err := serialization.ReadVarUint(r, MaxABIEntries)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "ABI Methods Deserialize failed.")
+	}
+	abi.Methods = make([]*ABIMethod, 0, n)
+	for i := uint64(0); i < n; i++ {
+		m := new(ABIMethod)
+		if m.Name, err = serialization.ReadVarString(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "ABI Method Name Deserialize failed.")
+		}
+		if m.Parameters, err = deserializeParameters(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "ABI Method Parameters Deserialize failed.")
+		}
+		t, err := serialization.ReadByte(r)
+		if err != nil {
+			return NewDetailErr(err, ErrNoCode, "ABI Method ReturnType Deserialize failed.")
+		}
+		m.ReturnType = ContractParameterType(t)
+		abi.Methods = append(abi.Methods, m)
+	}
+	n, err = serialization.ReadVarUint(r, MaxABIEntries)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "ABI Events Deserialize failed.")
+	}
+	abi.Events = make([]*ABIEvent, 0, n)
+	for i := uint64(0); i < n; i++ {
+		e := new(ABIEvent)
+		if e.Name, err = serialization.ReadVarString(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "ABI Event Name Deserialize failed.")
+		}
+		if e.Parameters, err = deserializeParameters(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "ABI Event Parameters Deserialize failed.")
+		}
+		abi.Events = append(abi.Events, e)
+	}
+	return nil
+}
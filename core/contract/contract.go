@@ -140,6 +140,33 @@ func (c *Contract) IsMultiSigContract() bool {
 	return true
 }
 
+// ParseMultiSigContract returns the multi-signature contract of code.
+func ParseMultiSigContract(code []byte) (*Contract, error) {
+	c := &Contract{Code: code}
+	if !c.IsMultiSigContract() {
+		return nil, errors.New("[Contract], Not a multi-signature contract.")
+	}
+	var m int
+	switch code[0] {
+	case 1:
+		m = int(code[1])
+	case 2:
+		m = int(BytesToInt16(code[1:]))
+	default:
+		m = int(code[0]) - 80
+	}
+	c.Parameters = make([]ContractParameterType, m)
+	for i := range c.Parameters {
+		c.Parameters[i] = Signature
+	}
+	hash, err := ToCodeHash(code)
+	if err != nil {
+		return nil, NewDetailErr(err, ErrNoCode, "[Contract], ParseMultiSigContract failed.")
+	}
+	c.ProgramHash = hash
+	return c, nil
+}
+
 func (c *Contract) GetType() ContractType {
 	if c.IsStandard() {
 		return SignatureContract
@@ -59,6 +59,19 @@ func NewContractContext(data sig.SignableData) *ContractContext {
 	}
 }
 
+// NewContractContextWithProgramHashes creates the context of data signed by
+// the given program hashes, for data whose hashes can not be looked up.
+func NewContractContextWithProgramHashes(data sig.SignableData, programHashes []Uint160) *ContractContext {
+	hashLen := len(programHashes)
+	return &ContractContext{
+		Data:            data,
+		ProgramHashes:   programHashes,
+		Codes:           make([][]byte, hashLen),
+		Parameters:      make([][][]byte, hashLen),
+		MultiPubkeyPara: make([][]PubkeyParameter, hashLen),
+	}
+}
+
 func (cxt *ContractContext) Add(contract *Contract, index int, parameter []byte) error {
 	log.Debug()
 	i := cxt.GetIndex(contract.ProgramHash)
@@ -214,6 +227,8 @@ func (cxt *ContractContext) ParseContractPubKeys(contract *Contract) (map[string
 	case 2:
 		i += 3
 		break
+	default:
+		i++
 	}
 	for contract.Code[i] == 33 {
 		i++
@@ -223,7 +238,7 @@ func (cxt *ContractContext) ParseContractPubKeys(contract *Contract) (map[string
 		//}
 
 		//add to parameter index
-		pubkeyIndex[ToHexString(contract.Code[i:33])] = Index
+		pubkeyIndex[ToHexString(contract.Code[i:i+33])] = Index
 
 		i += 33
 		Index++
@@ -232,6 +247,66 @@ func (cxt *ContractContext) ParseContractPubKeys(contract *Contract) (map[string
 	return pubkeyIndex, nil
 }
 
+// AddSignature adds the signature of pubkey to the multi-signature contract.
+// Every signature collected is kept in MultiPubkeyPara, the parameters are the
+// first of them in the order of the contract public keys, as CHECKMULTISIG
+// expects.
+func (cxt *ContractContext) AddSignature(contract *Contract, pubkey *crypto.PubKey, signature []byte) error {
+	index := cxt.GetIndex(contract.ProgramHash)
+	if index < 0 {
+		return errors.New("The program hash is not exist.")
+	}
+	pkIndexs, err := cxt.ParseContractPubKeys(contract)
+	if err != nil {
+		return err
+	}
+	pk, err := pubkey.EncodePoint(true)
+	if err != nil {
+		return err
+	}
+	key := ToHexString(pk)
+	if _, ok := pkIndexs[key]; !ok {
+		return errors.New("The public key is not in the contract.")
+	}
+	cxt.Codes[index] = contract.Code
+	for _, pubkeyPara := range cxt.MultiPubkeyPara[index] {
+		if pubkeyPara.PubKey == key {
+			return nil
+		}
+	}
+	cxt.MultiPubkeyPara[index] = append(cxt.MultiPubkeyPara[index], PubkeyParameter{
+		PubKey:    key,
+		Parameter: ToHexString(signature),
+	})
+
+	paraIndexs := []ParameterIndex{}
+	for _, pubkeyPara := range cxt.MultiPubkeyPara[index] {
+		parameter, err := HexToBytes(pubkeyPara.Parameter)
+		if err != nil {
+			return err
+		}
+		paraIndexs = append(paraIndexs, ParameterIndex{Parameter: parameter, Index: pkIndexs[pubkeyPara.PubKey]})
+	}
+	sort.Sort(ParameterIndexSlice(paraIndexs))
+	cxt.Parameters[index] = make([][]byte, len(contract.Parameters))
+	for i := 0; i < len(contract.Parameters) && i < len(paraIndexs); i++ {
+		cxt.Parameters[index][i] = paraIndexs[i].Parameter
+	}
+	return nil
+}
+
+// AddContractAccount adds the witness spending from the contract deployed at
+// programHash. It carries no code, the deployed contract verifies parameters.
+func (cxt *ContractContext) AddContractAccount(programHash Uint160, parameters [][]byte) error {
+	i := cxt.GetIndex(programHash)
+	if i < 0 {
+		return errors.New("The program hash is not exist.")
+	}
+	cxt.Codes[i] = []byte{}
+	cxt.Parameters[i] = parameters
+	return nil
+}
+
 func (cxt *ContractContext) GetIndex(programHash Uint160) int {
 	for i := 0; i < len(cxt.ProgramHashes); i++ {
 		if cxt.ProgramHashes[i] == programHash {
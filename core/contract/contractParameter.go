@@ -19,6 +19,12 @@
 
 package contract
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 //parameter defined type.
 type ContractParameterType byte
 
@@ -32,6 +38,63 @@ const (
 	PublicKey
 	String
 	Array = 0x10
+	Map = 0x12
 	InteropInterface = 0xf0
 	Void = 0xff
 )
+
+var parameterTypeNames = map[ContractParameterType]string{
+	Signature:        "Signature",
+	Boolean:          "Boolean",
+	Integer:          "Integer",
+	Hash160:          "Hash160",
+	Hash256:          "Hash256",
+	ByteArray:        "ByteArray",
+	PublicKey:        "PublicKey",
+	String:           "String",
+	Array:            "Array",
+	Map:              "Map",
+	InteropInterface: "InteropInterface",
+	Void:             "Void",
+}
+
+// Name returns the name used for the type in ABI manifests.
+func (t ContractParameterType) Name() string {
+	if name, ok := parameterTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%02x", byte(t))
+}
+
+// ParseContractParameterType returns the type with the given name, case insensitive.
+func ParseContractParameterType(name string) (ContractParameterType, error) {
+	for t, n := range parameterTypeNames {
+		if strings.EqualFold(n, name) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown parameter type %s", name)
+}
+
+func (t ContractParameterType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Name())
+}
+
+// UnmarshalJSON accepts a type name or its numeric value.
+func (t *ContractParameterType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var v byte
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*t = ContractParameterType(v)
+		return nil
+	}
+	p, err := ParseContractParameterType(name)
+	if err != nil {
+		return err
+	}
+	*t = p
+	return nil
+}
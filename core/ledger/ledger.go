@@ -20,11 +20,13 @@
 package ledger
 
 import (
+	"bytes"
 	"errors"
 	"github.com/Ontology/common"
 	. "github.com/Ontology/common"
 	"github.com/Ontology/core/contract"
 	tx "github.com/Ontology/core/transaction"
+	"github.com/Ontology/core/transaction/utxo"
 	"github.com/Ontology/crypto"
 	. "github.com/Ontology/errors"
 	"github.com/Ontology/core/states"
@@ -90,6 +92,22 @@ func (l *Ledger) GetAsset(assetId Uint256) (*states.AssetState, error) {
 	return asset, nil
 }
 
+// GetIdentity returns the DDO of an ONT ID, nil if it is not registered.
+func (l *Ledger) GetIdentity(id []byte) (*states.IdentityState, error) {
+	data, err := l.Store.GetIdentity(id)
+	if err != nil {
+		return nil, NewDetailErr(err, ErrNoCode, "[Ledger],GetIdentity failed with id =" + string(id))
+	}
+	if data == nil {
+		return nil, nil
+	}
+	identity := new(states.IdentityState)
+	if err := identity.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, NewDetailErr(err, ErrNoCode, "[Ledger],GetIdentity deserialize failed.")
+	}
+	return identity, nil
+}
+
 //Get Block With Height.
 func (l *Ledger) GetBlockWithHeight(height uint32) (*Block, error) {
 	temp, err := l.Store.GetBlockHash(height)
@@ -126,6 +144,45 @@ func (l *Ledger) GetTransactionWithHash(hash Uint256) (*tx.Transaction, error) {
 	return tx, nil
 }
 
+// GetCurrentBlockTime returns the timestamp of the current block, time locked
+// outputs are released against it.
+func (l *Ledger) GetCurrentBlockTime() (uint32, error) {
+	header, err := l.Store.GetHeader(l.Store.GetCurrentBlockHash())
+	if err != nil {
+		return 0, NewDetailErr(err, ErrNoCode, "[Ledger],GetCurrentBlockTime failed.")
+	}
+	return header.Timestamp, nil
+}
+
+// IsLocked reports if an output with the given lock can not be spent in the
+// next block.
+func (l *Ledger) IsLocked(lockType utxo.OutputLockType, lockValue uint32) (bool, error) {
+	if lockType == utxo.OutputLock_NONE {
+		return false, nil
+	}
+	var time uint32
+	if lockType == utxo.OutputLock_TIME {
+		var err error
+		if time, err = l.GetCurrentBlockTime(); err != nil {
+			return false, err
+		}
+	}
+	return !utxo.IsLockReleased(lockType, lockValue, l.Store.GetHeight()+1, time), nil
+}
+
+// GetUnspentLock returns the output an unspent coin refers to, which holds its
+// lock. Locks are not stored with the unspent coins of a program.
+func (l *Ledger) GetUnspentLock(unspent *utxo.UTXOUnspent) (*utxo.TxOutput, error) {
+	tx, err := l.GetTransactionWithHash(unspent.Txid)
+	if err != nil {
+		return nil, err
+	}
+	if int(unspent.Index) >= len(tx.Outputs) {
+		return nil, NewDetailErr(errors.New("output index out of range"), ErrNoCode, "[Ledger],GetUnspentLock failed.")
+	}
+	return tx.Outputs[unspent.Index], nil
+}
+
 //Get local block chain height.
 func (l *Ledger) GetLocalBlockChainHeight() uint32 {
 	return l.Blockchain.BlockHeight
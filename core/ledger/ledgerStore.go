@@ -71,6 +71,25 @@ type ILedgerStore interface {
 	GetUnclaimed(hash Uint256) (map[uint16]*utxo.SpentCoin, error)
 	GetCurrentStateRoot() Uint256
 	GetIdentity(ontId []byte) ([]byte, error)
+	GetClaim(claimHash Uint256) (*states.ClaimState, error)
+	GetClaimProof(claimHash Uint256) (Uint256, [][]byte, error)
+	GetFrozenAccount(assetId Uint256, programHash Uint160) (*states.FrozenAccountState, error)
 
 	GetStorageItem(key *states.StorageKey) (*states.StorageItem, error)
+	GetNotifications(filter *NotifyFilter) ([]*states.NotifyState, error)
+}
+
+// MaxNotifyQueryLimit bounds the number of notifications returned by one query.
+const MaxNotifyQueryLimit = 1000
+
+// NotifyFilter selects persisted notifications. A nil CodeHash or empty
+// EventName matches any, ToHeight 0 means the current height. Offset and
+// Limit page through the matches in block order.
+type NotifyFilter struct {
+	CodeHash   *Uint160
+	EventName  string
+	FromHeight uint32
+	ToHeight   uint32
+	Offset     int
+	Limit      int
 }
@@ -30,6 +30,9 @@ import (
 	"fmt"
 )
 
+// BlocksPerYear is the number of blocks an asset is renewed by per year.
+const BlocksPerYear uint32 = 2000000
+
 type AssetState struct {
 	StateBase
 	AssetId    Uint256
@@ -43,6 +46,31 @@ type AssetState struct {
 	Issuer     Uint160
 	Expiration uint32
 	IsFrozen   bool
+	Burned     Fixed64 // amount destroyed after it was issued
+}
+
+// Issued returns the amount of the asset issued so far, destroyed or not.
+func (this *AssetState) Issued() Fixed64 {
+	return this.Amount - this.Available
+}
+
+// Circulating returns the amount of the asset issued and not destroyed.
+func (this *AssetState) Circulating() Fixed64 {
+	return this.Issued() - this.Burned
+}
+
+// IsExpired tells whether the asset expired before the block at height.
+func (this *AssetState) IsExpired(height uint32) bool {
+	return this.Expiration < height
+}
+
+// Renew extends the expiration of the asset by years from the block at height,
+// an expired asset is renewed from height.
+func (this *AssetState) Renew(height uint32, years uint32) {
+	if this.Expiration < height {
+		this.Expiration = height
+	}
+	this.Expiration += years * BlocksPerYear
 }
 
 func (this *AssetState) Serialize(w io.Writer) error {
@@ -59,6 +87,7 @@ func (this *AssetState) Serialize(w io.Writer) error {
 	this.Issuer.Serialize(w)
 	WriteUint32(w, this.Expiration)
 	WriteBool(w, this.IsFrozen)
+	this.Burned.Serialize(w)
 	return nil
 }
 
@@ -135,6 +164,17 @@ func (this *AssetState) Deserialize(r io.Reader) error {
 		return NewDetailErr(err, ErrNoCode, "AssetState IsFrozen Deserialize failed.")
 	}
 	this.IsFrozen = fr
+	// assets stored before burning was added end here
+	burned := new(Fixed64)
+	err = burned.Deserialize(r)
+	if err == io.EOF {
+		this.Burned = 0
+		return nil
+	}
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "AssetState Burned Deserialize failed.")
+	}
+	this.Burned = *burned
 	return nil
 }
 
@@ -0,0 +1,67 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/crypto"
+)
+
+func TestAssetStateWithoutBurned(t *testing.T) {
+	crypto.SetAlg("P256R1")
+	_, owner, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	asset := &AssetState{
+		AssetId:    common.Uint256{1},
+		Name:       "token",
+		Amount:     100,
+		Available:  40,
+		Precision:  8,
+		Owner:      &owner,
+		Expiration: 10,
+		Burned:     5,
+	}
+	var buf bytes.Buffer
+	if err := asset.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := new(AssetState)
+	if err := decoded.Deserialize(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Burned != 5 {
+		t.Errorf("burned: got %v", decoded.Burned)
+	}
+
+	// a record stored before Burned was added
+	old := new(AssetState)
+	if err := old.Deserialize(bytes.NewReader(buf.Bytes()[:buf.Len()-8])); err != nil {
+		t.Fatal(err)
+	}
+	if old.Burned != 0 || old.Available != 40 || old.Expiration != 10 {
+		t.Errorf("old record: got %+v", old)
+	}
+}
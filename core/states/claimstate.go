@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+import (
+	"io"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	. "github.com/Ontology/errors"
+)
+
+const (
+	ClaimStatusValid   = "valid"
+	ClaimStatusExpired = "expired"
+	ClaimStatusRevoked = "revoked"
+)
+
+// ClaimState is an attested claim, keyed by its hash.
+type ClaimState struct {
+	StateBase
+	ClaimHash    common.Uint256
+	IssuerID     []byte
+	SubjectID    []byte
+	Expiry       uint32 // last valid height, 0 if the claim does not expire
+	CommitHeight uint32
+	Revoked      bool
+	RevokeHeight uint32
+}
+
+// Status returns the status of the claim at the given height.
+func (this *ClaimState) Status(height uint32) string {
+	if this.Revoked {
+		return ClaimStatusRevoked
+	}
+	if this.Expiry != 0 && height > this.Expiry {
+		return ClaimStatusExpired
+	}
+	return ClaimStatusValid
+}
+
+func (this *ClaimState) Serialize(w io.Writer) error {
+	this.StateBase.Serialize(w)
+	if _, err := this.ClaimHash.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState ClaimHash Serialize failed.")
+	}
+	if err := serialization.WriteVarBytes(w, this.IssuerID); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState IssuerID Serialize failed.")
+	}
+	if err := serialization.WriteVarBytes(w, this.SubjectID); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState SubjectID Serialize failed.")
+	}
+	if err := serialization.WriteUint32(w, this.Expiry); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState Expiry Serialize failed.")
+	}
+	if err := serialization.WriteUint32(w, this.CommitHeight); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState CommitHeight Serialize failed.")
+	}
+	if err := serialization.WriteBool(w, this.Revoked); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState Revoked Serialize failed.")
+	}
+	if err := serialization.WriteUint32(w, this.RevokeHeight); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState RevokeHeight Serialize failed.")
+	}
+	return nil
+}
+
+func (this *ClaimState) Deserialize(r io.Reader) error {
+	err := this.StateBase.Deserialize(r)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState StateBase Deserialize failed.")
+	}
+	if err = this.ClaimHash.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState ClaimHash Deserialize failed.")
+	}
+	if this.IssuerID, err = serialization.ReadVarBytes(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState IssuerID Deserialize failed.")
+	}
+	if this.SubjectID, err = serialization.ReadVarBytes(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState SubjectID Deserialize failed.")
+	}
+	if this.Expiry, err = serialization.ReadUint32(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState Expiry Deserialize failed.")
+	}
+	if this.CommitHeight, err = serialization.ReadUint32(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState CommitHeight Deserialize failed.")
+	}
+	if this.Revoked, err = serialization.ReadBool(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState Revoked Deserialize failed.")
+	}
+	if this.RevokeHeight, err = serialization.ReadUint32(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "ClaimState RevokeHeight Deserialize failed.")
+	}
+	return nil
+}
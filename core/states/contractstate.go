@@ -30,14 +30,17 @@ import (
 
 type ContractState struct {
 	StateBase
-	Code        *code.FunctionCode
-	VmType      types.VmType
-	NeedStorage bool
-	Name        string
-	Version     string
-	Author      string
-	Email       string
-	Description string
+	Code            *code.FunctionCode
+	VmType          types.VmType
+	NeedStorage     bool
+	DynamicInvoke   bool
+	ReentrancyGuard bool
+	Name            string
+	Version         string
+	Author          string
+	Email           string
+	Description     string
+	ABI             *code.ABI
 }
 
 func (this *ContractState) Serialize(w io.Writer) error {
@@ -51,7 +54,7 @@ func (this *ContractState) Serialize(w io.Writer) error {
 		return NewDetailErr(err, ErrNoCode, "ContractState VmType Serialize failed.")
 	}
 
-	err = WriteBool(w, this.NeedStorage)
+	err = WriteByte(w, byte(types.NewContractProperty(this.NeedStorage, this.DynamicInvoke, this.ReentrancyGuard)))
 	if err != nil {
 		return NewDetailErr(err, ErrNoCode, "ContractState NeedStorage Serialize failed.")
 	}
@@ -76,6 +79,16 @@ func (this *ContractState) Serialize(w io.Writer) error {
 	if err != nil {
 		return NewDetailErr(err, ErrNoCode, "ContractState Description Serialize failed.")
 	}
+	err = WriteBool(w, this.ABI != nil)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "ContractState ABI Serialize failed.")
+	}
+	if this.ABI != nil {
+		err = this.ABI.Serialize(w)
+		if err != nil {
+			return NewDetailErr(err, ErrNoCode, "ContractState ABI Serialize failed.")
+		}
+	}
 	return nil
 }
 
@@ -101,10 +114,13 @@ func (this *ContractState) Deserialize(r io.Reader) error {
 	}
 	this.VmType = types.VmType(vmType)
 
-	this.NeedStorage, err = ReadBool(r)
+	property, err := ReadByte(r)
 	if err != nil {
 		return NewDetailErr(err, ErrNoCode, "ContractState NeedStorage Deserialize failed.")
 	}
+	this.NeedStorage = types.ContractProperty(property).Has(types.HasStorage)
+	this.DynamicInvoke = types.ContractProperty(property).Has(types.HasDynamicInvoke)
+	this.ReentrancyGuard = types.ContractProperty(property).Has(types.HasReentrancyGuard)
 
 	this.Name, err = ReadVarString(r)
 	if err != nil {
@@ -126,6 +142,21 @@ func (this *ContractState) Deserialize(r io.Reader) error {
 	if err != nil {
 		return NewDetailErr(err, ErrNoCode, "ContractState Description Deserialize failed.")
 	}
+	// contracts stored before the ABI was added end here
+	hasABI, err := ReadByte(r)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "ContractState ABI Deserialize failed.")
+	}
+	if hasABI != 0 {
+		this.ABI = new(code.ABI)
+		err = this.ABI.Deserialize(r)
+		if err != nil {
+			return NewDetailErr(err, ErrNoCode, "ContractState ABI Deserialize failed.")
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,78 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract"
+)
+
+func TestContractStateABI(t *testing.T) {
+	abi := &code.ABI{
+		Methods: []*code.ABIMethod{{
+			Name:       "transfer",
+			Parameters: []*code.ABIParameter{{Name: "from", Type: contract.Hash160}, {Name: "to", Type: contract.Hash160}, {Name: "amount", Type: contract.Integer}},
+			ReturnType: contract.Boolean,
+		}},
+		Events: []*code.ABIEvent{{
+			Name:       "transfer",
+			Parameters: []*code.ABIParameter{{Name: "from", Type: contract.Hash160}, {Name: "to", Type: contract.Hash160}, {Name: "amount", Type: contract.Integer}},
+		}},
+	}
+	for _, abi := range []*code.ABI{abi, nil} {
+		state := &ContractState{
+			Code:        &code.FunctionCode{Code: []byte{0x51}, ParameterTypes: []contract.ContractParameterType{}},
+			NeedStorage: true,
+			Name:        "token",
+			Version:     "1",
+			Description: "a token",
+			ABI:         abi,
+		}
+		var buf bytes.Buffer
+		if err := state.Serialize(&buf); err != nil {
+			t.Fatal(err)
+		}
+		decoded := new(ContractState)
+		if err := decoded.Deserialize(bytes.NewReader(buf.Bytes())); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded.ABI, abi) {
+			t.Errorf("ABI: got %+v, want %+v", decoded.ABI, abi)
+		}
+		if decoded.Name != "token" || decoded.Description != "a token" || !decoded.NeedStorage {
+			t.Errorf("got %+v", decoded)
+		}
+
+		if abi == nil {
+			// a record stored before the ABI was added
+			old := new(ContractState)
+			if err := old.Deserialize(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err != nil {
+				t.Fatal(err)
+			}
+			if old.ABI != nil || old.Name != "token" || old.Description != "a token" {
+				t.Errorf("old record: got %+v", old)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+import (
+	"io"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	. "github.com/Ontology/errors"
+)
+
+// FrozenAccountState records whether an address is frozen within an asset,
+// keyed by the asset id followed by the program hash.
+type FrozenAccountState struct {
+	StateBase
+	AssetId     common.Uint256
+	ProgramHash common.Uint160
+	IsFrozen    bool
+}
+
+func (this *FrozenAccountState) Serialize(w io.Writer) error {
+	this.StateBase.Serialize(w)
+	if _, err := this.AssetId.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "FrozenAccountState AssetId Serialize failed.")
+	}
+	if _, err := this.ProgramHash.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "FrozenAccountState ProgramHash Serialize failed.")
+	}
+	if err := serialization.WriteBool(w, this.IsFrozen); err != nil {
+		return NewDetailErr(err, ErrNoCode, "FrozenAccountState IsFrozen Serialize failed.")
+	}
+	return nil
+}
+
+func (this *FrozenAccountState) Deserialize(r io.Reader) error {
+	err := this.StateBase.Deserialize(r)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "FrozenAccountState StateBase Deserialize failed.")
+	}
+	if err = this.AssetId.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "FrozenAccountState AssetId Deserialize failed.")
+	}
+	if err = this.ProgramHash.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "FrozenAccountState ProgramHash Deserialize failed.")
+	}
+	if this.IsFrozen, err = serialization.ReadBool(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "FrozenAccountState IsFrozen Deserialize failed.")
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	"github.com/Ontology/core/transaction/payload"
+	"github.com/Ontology/crypto"
+	. "github.com/Ontology/errors"
+)
+
+const (
+	MaxIdentityKeys       = 64
+	MaxIdentityAttributes = 256
+)
+
+// IdentityState is the DDO of an ONT ID: the keys allowed to change it, its
+// attributes and the optional recovery address which may replace the keys.
+type IdentityState struct {
+	StateBase
+	ID          []byte
+	PubKeys     []*crypto.PubKey
+	Attributes  []*payload.IdentityAttribute
+	HasRecovery bool
+	Recovery    common.Uint160
+}
+
+// NewIdentityState creates the DDO registered by p.
+func NewIdentityState(p *payload.Identity) *IdentityState {
+	return &IdentityState{
+		ID:      p.ID,
+		PubKeys: []*crypto.PubKey{p.PubKey},
+	}
+}
+
+func (this *IdentityState) KeyIndex(pk *crypto.PubKey) int {
+	for i, k := range this.PubKeys {
+		if crypto.Equal(k, pk) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (this *IdentityState) AttributeIndex(key []byte) int {
+	for i, a := range this.Attributes {
+		if bytes.Equal(a.Key, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Apply changes the DDO by p, which has been validated against it.
+func (this *IdentityState) Apply(p *payload.Identity) {
+	switch p.Action {
+	case payload.IdentityAction_ADD_KEY:
+		if this.KeyIndex(p.PubKey) < 0 {
+			this.PubKeys = append(this.PubKeys, p.PubKey)
+		}
+	case payload.IdentityAction_REMOVE_KEY:
+		if i := this.KeyIndex(p.PubKey); i >= 0 {
+			this.PubKeys = append(this.PubKeys[:i], this.PubKeys[i+1:]...)
+		}
+	case payload.IdentityAction_ADD_ATTRIBUTE, payload.IdentityAction_UPDATE_ATTRIBUTE:
+		if i := this.AttributeIndex(p.Attribute.Key); i >= 0 {
+			this.Attributes[i] = p.Attribute
+		} else {
+			this.Attributes = append(this.Attributes, p.Attribute)
+		}
+	case payload.IdentityAction_REMOVE_ATTRIBUTE:
+		if i := this.AttributeIndex(p.Attribute.Key); i >= 0 {
+			this.Attributes = append(this.Attributes[:i], this.Attributes[i+1:]...)
+		}
+	case payload.IdentityAction_SET_RECOVERY:
+		this.HasRecovery = true
+		this.Recovery = p.Recovery
+	}
+}
+
+func (this *IdentityState) Serialize(w io.Writer) error {
+	this.StateBase.Serialize(w)
+	if err := serialization.WriteVarBytes(w, this.ID); err != nil {
+		return NewDetailErr(err, ErrNoCode, "IdentityState ID Serialize failed.")
+	}
+	if err := serialization.WriteVarUint(w, uint64(len(this.PubKeys))); err != nil {
+		return NewDetailErr(err, ErrNoCode, "IdentityState PubKeys Serialize failed.")
+	}
+	for _, k := range this.PubKeys {
+		if err := k.Serialize(w); err != nil {
+			return NewDetailErr(err, ErrNoCode, "IdentityState PubKey Serialize failed.")
+		}
+	}
+	if err := serialization.WriteVarUint(w, uint64(len(this.Attributes))); err != nil {
+		return NewDetailErr(err, ErrNoCode, "IdentityState Attributes Serialize failed.")
+	}
+	for _, a := range this.Attributes {
+		if err := a.Serialize(w); err != nil {
+			return NewDetailErr(err, ErrNoCode, "IdentityState Attribute Serialize failed.")
+		}
+	}
+	if err := serialization.WriteBool(w, this.HasRecovery); err != nil {
+		return NewDetailErr(err, ErrNoCode, "IdentityState HasRecovery Serialize failed.")
+	}
+	if this.HasRecovery {
+		if _, err := this.Recovery.Serialize(w); err != nil {
+			return NewDetailErr(err, ErrNoCode, "IdentityState Recovery Serialize failed.")
+		}
+	}
+	return nil
+}
+
+func (this *IdentityState) Deserialize(r io.Reader) error {
+	err := this.StateBase.Deserialize(r)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "IdentityState StateBase Deserialize failed.")
+	}
+	if this.ID, err = serialization.ReadVarBytes(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "IdentityState ID Deserialize failed.")
+	}
+	n, err := serialization.ReadVarUint(r, MaxIdentityKeys)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "IdentityState PubKeys Deserialize failed.")
+	}
+	this.PubKeys = make([]*crypto.PubKey, 0, n)
+	for i := uint64(0); i < n; i++ {
+		k := new(crypto.PubKey)
+		if err := k.DeSerialize(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "IdentityState PubKey Deserialize failed.")
+		}
+		this.PubKeys = append(this.PubKeys, k)
+	}
+	n, err = serialization.ReadVarUint(r, MaxIdentityAttributes)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "IdentityState Attributes Deserialize failed.")
+	}
+	this.Attributes = make([]*payload.IdentityAttribute, 0, n)
+	for i := uint64(0); i < n; i++ {
+		a := new(payload.IdentityAttribute)
+		if err := a.Deserialize(r); err != nil {
+			return err
+		}
+		this.Attributes = append(this.Attributes, a)
+	}
+	if this.HasRecovery, err = serialization.ReadBool(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "IdentityState HasRecovery Deserialize failed.")
+	}
+	if this.HasRecovery {
+		if err := this.Recovery.Deserialize(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "IdentityState Recovery Deserialize failed.")
+		}
+	}
+	return nil
+}
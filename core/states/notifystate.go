@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	. "github.com/Ontology/errors"
+)
+
+// NotifyState is a Runtime.Notify of a transaction that executed successfully.
+type NotifyState struct {
+	StateBase
+	Height    uint32
+	TxHash    common.Uint256
+	Index     uint32 // position among the notifications of the transaction
+	CodeHash  common.Uint160
+	EventName string
+	State     []byte // the notified item, see vm/neovm/types.SerializeStackItem
+}
+
+func (this *NotifyState) Serialize(w io.Writer) error {
+	this.StateBase.Serialize(w)
+	if err := serialization.WriteUint32(w, this.Height); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState Height Serialize failed.")
+	}
+	if _, err := this.TxHash.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState TxHash Serialize failed.")
+	}
+	if err := serialization.WriteUint32(w, this.Index); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState Index Serialize failed.")
+	}
+	if _, err := this.CodeHash.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState CodeHash Serialize failed.")
+	}
+	if err := serialization.WriteVarString(w, this.EventName); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState EventName Serialize failed.")
+	}
+	if err := serialization.WriteVarBytes(w, this.State); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState State Serialize failed.")
+	}
+	return nil
+}
+
+func (this *NotifyState) Deserialize(r io.Reader) error {
+	err := this.StateBase.Deserialize(r)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState StateBase Deserialize failed.")
+	}
+	if this.Height, err = serialization.ReadUint32(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState Height Deserialize failed.")
+	}
+	if err = this.TxHash.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState TxHash Deserialize failed.")
+	}
+	if this.Index, err = serialization.ReadUint32(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState Index Deserialize failed.")
+	}
+	if err = this.CodeHash.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState CodeHash Deserialize failed.")
+	}
+	if this.EventName, err = serialization.ReadVarString(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState EventName Deserialize failed.")
+	}
+	if this.State, err = serialization.ReadVarBytes(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "NotifyState State Deserialize failed.")
+	}
+	return nil
+}
+
+func (this *NotifyState) ToArray() []byte {
+	b := new(bytes.Buffer)
+	this.Serialize(b)
+	return b.Bytes()
+}
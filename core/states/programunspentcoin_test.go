@@ -0,0 +1,65 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	"github.com/Ontology/core/transaction/utxo"
+)
+
+func TestProgramUnspentCoinStoredFormat(t *testing.T) {
+	unspents := []*utxo.UTXOUnspent{
+		{Txid: common.Uint256{1}, Index: 0, Value: 10},
+		{Txid: common.Uint256{2}, Index: 3, Value: 20},
+	}
+	// the record as written by nodes before output locks were added
+	var buf bytes.Buffer
+	(&StateBase{}).Serialize(&buf)
+	serialization.WriteUint32(&buf, uint32(len(unspents)))
+	for _, u := range unspents {
+		u.Txid.Serialize(&buf)
+		serialization.WriteUint32(&buf, u.Index)
+		u.Value.Serialize(&buf)
+	}
+	stored := buf.Bytes()
+
+	coin := new(ProgramUnspentCoin)
+	if err := coin.Deserialize(bytes.NewReader(stored)); err != nil {
+		t.Fatal(err)
+	}
+	if len(coin.Unspents) != len(unspents) {
+		t.Fatalf("got %d unspents", len(coin.Unspents))
+	}
+	for i, u := range coin.Unspents {
+		if *u != *unspents[i] {
+			t.Errorf("unspent %d: got %+v", i, u)
+		}
+	}
+
+	var encoded bytes.Buffer
+	coin.Serialize(&encoded)
+	if !bytes.Equal(encoded.Bytes(), stored) {
+		t.Errorf("serialized as %x, stored as %x", encoded.Bytes(), stored)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"errors"
 	"fmt"
 	. "github.com/Ontology/common"
+	"github.com/Ontology/common/config"
 	"github.com/Ontology/common/log"
 	"github.com/Ontology/common/serialization"
 	"github.com/Ontology/core/contract/program"
@@ -43,9 +44,13 @@ import (
 	httprestful "github.com/Ontology/net/httprestful/error"
 	sc "github.com/Ontology/smartcontract"
 	"github.com/Ontology/smartcontract/event"
+	"github.com/Ontology/smartcontract/native"
 	"github.com/Ontology/smartcontract/service"
+	"github.com/Ontology/smartcontract/trace"
 	"github.com/Ontology/smartcontract/types"
+	"github.com/Ontology/trie"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -109,6 +114,11 @@ func NewLedgerStore() (ILedgerStore, error) {
 	return cs, nil
 }
 
+// NewMemStore returns an empty store kept in memory.
+func NewMemStore() (IStore, error) {
+	return NewMemLevelDBStore()
+}
+
 func NewChainStore(file string) (*ChainStore, error) {
 
 	st, err := NewStore(file)
@@ -116,6 +126,10 @@ func NewChainStore(file string) (*ChainStore, error) {
 		return nil, err
 	}
 
+	return newChainStore(st), nil
+}
+
+func newChainStore(st IStore) *ChainStore {
 	chain := &ChainStore{
 		st:                 st,
 		headerIndex:        map[uint32]Uint256{},
@@ -129,7 +143,7 @@ func NewChainStore(file string) (*ChainStore, error) {
 
 	go chain.loop()
 
-	return chain, nil
+	return chain
 }
 
 func (self *ChainStore) Close() {
@@ -689,6 +703,7 @@ func (bd *ChainStore) persist(b *Block) error {
 	}
 	bookKeeper := state.Value.(*states.BookKeeperState)
 	handleBookKeeper(stateStore, bookKeeper)
+	var notifySeq uint32
 	for _, t := range b.Transactions {
 		bd.SaveTransaction(t, b.Header.Height)
 		tx_id := t.Hash()
@@ -714,7 +729,7 @@ func (bd *ChainStore) persist(b *Block) error {
 				Owner:      p.Issuer,
 				Admin:      p.Controller,
 				Issuer:     p.Controller,
-				Expiration: b.Header.Height + 2*2000000,
+				Expiration: b.Header.Height + 2*states.BlocksPerYear,
 				IsFrozen:   false,
 			}, false); err != nil {
 				log.Error("[persist] TryAdd ST_Asset error:", err)
@@ -751,14 +766,17 @@ func (bd *ChainStore) persist(b *Block) error {
 			deploy := t.Payload.(*payload.DeployCode)
 			codeHash := deploy.Code.CodeHash()
 			if err := stateStore.TryGetOrAdd(ST_Contract, codeHash.ToArray(), &states.ContractState{
-				Code:        deploy.Code,
-				VmType:      deploy.VmType,
-				NeedStorage: deploy.NeedStorage,
-				Name:        deploy.Name,
-				Version:     deploy.CodeVersion,
-				Author:      deploy.Author,
-				Email:       deploy.Email,
-				Description: deploy.Description,
+				Code:            deploy.Code,
+				VmType:          deploy.VmType,
+				NeedStorage:     deploy.NeedStorage,
+				DynamicInvoke:   deploy.DynamicInvoke,
+				ReentrancyGuard: deploy.ReentrancyGuard,
+				Name:            deploy.Name,
+				Version:         deploy.CodeVersion,
+				Author:          deploy.Author,
+				Email:           deploy.Email,
+				Description:     deploy.Description,
+				ABI:             deploy.ABI,
 			}, false); err != nil {
 				log.Error("[persist] TryAdd ST_Contract error:", err)
 				return err
@@ -770,13 +788,18 @@ func (bd *ChainStore) persist(b *Block) error {
 				log.Error("[persist] TryGet ST_Contract error:", err)
 				return err
 			}
-			if cs == nil {
+			var contract *states.ContractState
+			if cs != nil {
+				contract = cs.Value.(*states.ContractState)
+			} else if nc := native.GetContract(invoke.CodeHash); nc != nil {
+				contract = native.NewContractState(nc)
+			} else {
 				event.PushSmartCodeEvent(t.Hash(), 0, INVOKE_TRANSACTION, "Contract not found!")
 				continue
 			}
-			contract := cs.Value.(*states.ContractState)
 			stateMachine := service.NewStateMachine(stateStore, types.Application, b)
-			smc, err := sc.NewSmartContract(&sc.Context{
+			var recorder *trace.Recorder
+			ctx := &sc.Context{
 				VmType:         contract.VmType,
 				StateMachine:   stateMachine,
 				SignableData:   t,
@@ -784,12 +807,20 @@ func (bd *ChainStore) persist(b *Block) error {
 				Input:          invoke.Code,
 				Code:           contract.Code.Code,
 				ReturnType:     contract.Code.ReturnType,
-			})
+			}
+			if config.Parameters.EnableTrace && trace.Traced(invoke.CodeHash) {
+				recorder = trace.DefaultStore.NewRecorder()
+				ctx.Tracer = recorder
+			}
+			smc, err := sc.NewSmartContract(ctx)
 			if err != nil {
 				log.Error("[persist] NewSmartContract error:", err)
 				return err
 			}
 			ret, err := smc.InvokeContract()
+			if recorder != nil {
+				trace.DefaultStore.Put(t.Hash(), recorder)
+			}
 			if err != nil {
 				log.Error("[persist] InvokeContract error:", err)
 				event.PushSmartCodeEvent(t.Hash(), httprestful.SMARTCODE_ERROR, INVOKE_TRANSACTION, err)
@@ -797,7 +828,55 @@ func (bd *ChainStore) persist(b *Block) error {
 			}
 			log.Error("result:", ret)
 			stateMachine.CloneCache.Commit()
+			notifySeq = bd.persistNotifications(b.Header.Height, notifySeq, t.Hash(), stateMachine.Notifications())
 			event.PushSmartCodeEvent(t.Hash(), 0, INVOKE_TRANSACTION, ret)
+		case tx.Identity:
+			if err := handleIdentity(stateStore, t.Payload.(*payload.Identity)); err != nil {
+				log.Error("[persist] handleIdentity error:", err)
+				return err
+			}
+		case tx.Attestation:
+			if err := handleAttestation(stateStore, t.Payload.(*payload.Attestation), b.Header.Height); err != nil {
+				log.Error("[persist] handleAttestation error:", err)
+				return err
+			}
+		case tx.Freeze:
+			if err := handleFreeze(stateStore, t.Payload.(*payload.Freeze)); err != nil {
+				log.Error("[persist] handleFreeze error:", err)
+				return err
+			}
+		case tx.AssetRenew:
+			p := t.Payload.(*payload.AssetRenew)
+			state, err := stateStore.TryGetAndChange(ST_Asset, p.AssetID.ToArray(), false)
+			if err != nil {
+				log.Error("[persist] TryGetAndChange ST_Asset error:", err)
+				return err
+			}
+			if state != nil {
+				state.(*states.AssetState).Renew(b.Header.Height, p.Years)
+			}
+		case tx.Destroy:
+			p := t.Payload.(*payload.Destroy)
+			state, err := stateStore.TryGetAndChange(ST_Asset, p.AssetID.ToArray(), false)
+			if err != nil {
+				log.Error("[persist] TryGetAndChange ST_Asset error:", err)
+				return err
+			}
+			if state != nil {
+				state.(*states.AssetState).Burned += p.Amount
+			}
+		case tx.AssetAdminChange:
+			p := t.Payload.(*payload.AssetAdminChange)
+			state, err := stateStore.TryGetAndChange(ST_Asset, p.AssetID.ToArray(), false)
+			if err != nil {
+				log.Error("[persist] TryGetAndChange ST_Asset error:", err)
+				return err
+			}
+			if state != nil {
+				asset := state.(*states.AssetState)
+				asset.Admin = p.Admin
+				asset.Issuer = p.Issuer
+			}
 		}
 	}
 	if err := stateStore.CommitTo(); err != nil {
@@ -988,7 +1067,7 @@ func (bd *ChainStore) GetQuantityIssued(assetId Uint256) (Fixed64, error) {
 	if err := asset.Deserialize(r); err != nil {
 		return Fixed64(0), err
 	}
-	return asset.Amount - asset.Available, nil
+	return asset.Issued(), nil
 }
 
 func (bd *ChainStore) GetUnspent(txid Uint256, index uint16) (*utxo.TxOutput, error) {
@@ -1185,16 +1264,68 @@ func (bd *ChainStore) GetCurrentStateRoot() Uint256 {
 	return *u256
 }
 
+// GetIdentity returns the serialized DDO of ontId, nil if it is not registered.
 func (bd *ChainStore) GetIdentity(ontId []byte) ([]byte, error) {
 	idPrefix := []byte{byte(ST_Identity)}
 	idKey := append(idPrefix, ontId...)
-	return bd.st.Get(idKey)
+	data, err := bd.st.Get(idKey)
+	if err != nil {
+		if strings.EqualFold(err.Error(), ErrDBNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return data, nil
 }
 
-func (bd *ChainStore) SetIdentity(ontId, ddo []byte) error {
-	idPrefix := []byte{byte(ST_Identity)}
-	idKey := append(idPrefix, ontId...)
-	return bd.st.BatchPut(idKey, ddo)
+// GetClaim returns the attested claim, nil if it was never committed.
+func (bd *ChainStore) GetClaim(claimHash Uint256) (*states.ClaimState, error) {
+	data, err := bd.st.Get(append([]byte{byte(ST_Claim)}, claimHash.ToArray()...))
+	if err != nil {
+		if strings.EqualFold(err.Error(), ErrDBNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	claim := new(states.ClaimState)
+	if err := claim.Deserialize(bytes.NewBuffer(data)); err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+// GetFrozenAccount returns whether the address is frozen within the asset, nil
+// if it was never frozen.
+func (bd *ChainStore) GetFrozenAccount(assetId Uint256, programHash Uint160) (*states.FrozenAccountState, error) {
+	key := append([]byte{byte(ST_FrozenAccount)}, assetId.ToArray()...)
+	data, err := bd.st.Get(append(key, programHash.ToArray()...))
+	if err != nil {
+		if strings.EqualFold(err.Error(), ErrDBNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	frozen := new(states.FrozenAccountState)
+	if err := frozen.Deserialize(bytes.NewBuffer(data)); err != nil {
+		return nil, err
+	}
+	return frozen, nil
+}
+
+// GetClaimProof proves the claim against the current state root. The leaf is
+// the sha256 of the serialized ClaimState, keyed by ST_Claim and the hash.
+func (bd *ChainStore) GetClaimProof(claimHash Uint256) (Uint256, [][]byte, error) {
+	root := bd.GetCurrentStateRoot()
+	tr, err := trie.NewSecure(root, bd.st)
+	if err != nil {
+		return Uint256{}, nil, err
+	}
+	nodes := tr.Prove(append([]byte{byte(ST_Claim)}, claimHash.ToArray()...))
+	proof := make([][]byte, 0, len(nodes))
+	for _, n := range nodes {
+		proof = append(proof, n)
+	}
+	return root, proof, nil
 }
 
 func (bd *ChainStore) GetStorageItem(key *states.StorageKey) (*states.StorageItem, error) {
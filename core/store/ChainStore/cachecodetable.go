@@ -31,6 +31,10 @@ type CacheCodeTable struct {
 	store store.IStateStore
 }
 
+func NewCacheCodeTable(store store.IStateStore) *CacheCodeTable {
+	return &CacheCodeTable{store: store}
+}
+
 func (table *CacheCodeTable) GetCode(codeHash []byte) ([]byte, error) {
 	value, _ := table.store.TryGet(store.ST_Contract, codeHash)
 	if value == nil {
@@ -39,3 +43,21 @@ func (table *CacheCodeTable) GetCode(codeHash []byte) ([]byte, error) {
 
 	return value.Value.(*states.ContractState).Code.Code, nil
 }
+
+func (table *CacheCodeTable) getContract(codeHash []byte) *states.ContractState {
+	value, _ := table.store.TryGet(store.ST_Contract, codeHash)
+	if value == nil {
+		return nil
+	}
+	return value.Value.(*states.ContractState)
+}
+
+func (table *CacheCodeTable) CanDynamicInvoke(codeHash []byte) bool {
+	contract := table.getContract(codeHash)
+	return contract != nil && contract.DynamicInvoke
+}
+
+func (table *CacheCodeTable) HasReentrancyGuard(codeHash []byte) bool {
+	contract := table.getContract(codeHash)
+	return contract != nil && contract.ReentrancyGuard
+}
@@ -29,6 +29,7 @@ import (
 	. "github.com/Ontology/core/states"
 	. "github.com/Ontology/core/store"
 	tx "github.com/Ontology/core/transaction"
+	"github.com/Ontology/core/transaction/payload"
 	"github.com/Ontology/core/transaction/utxo"
 	"github.com/Ontology/crypto"
 	"math/big"
@@ -236,3 +237,79 @@ func groupInputs(inputs []*utxo.UTXOTxInput) map[Uint256][]*utxo.UTXOTxInput {
 func remove(items []*Item, index int) []*Item {
 	return append(items[:index], items[index+1:]...)
 }
+
+// handleIdentity registers or changes a DDO. The DDO is a trie leaf, so it is
+// covered by the state root.
+func handleIdentity(stateStore *StateStore, p *payload.Identity) error {
+	if p.Action == payload.IdentityAction_REGISTER {
+		return stateStore.TryGetOrAdd(ST_Identity, p.ID, NewIdentityState(p), true)
+	}
+	state, err := stateStore.TryGetAndChange(ST_Identity, p.ID, true)
+	if err != nil {
+		log.Errorf("[handleIdentity] TryGetAndChange ST_Identity error: %v", err)
+		return err
+	}
+	if state == nil {
+		log.Warnf("[handleIdentity] identity %s not registered", string(p.ID))
+		return nil
+	}
+	state.(*IdentityState).Apply(p)
+	return nil
+}
+
+// handleAttestation commits or revokes a claim, the claim is a trie leaf.
+func handleAttestation(stateStore *StateStore, p *payload.Attestation, height uint32) error {
+	if p.Action == payload.AttestationAction_COMMIT {
+		return stateStore.TryGetOrAdd(ST_Claim, p.ClaimHash.ToArray(), &ClaimState{
+			ClaimHash:    p.ClaimHash,
+			IssuerID:     p.IssuerID,
+			SubjectID:    p.SubjectID,
+			Expiry:       p.Expiry,
+			CommitHeight: height,
+		}, true)
+	}
+	state, err := stateStore.TryGetAndChange(ST_Claim, p.ClaimHash.ToArray(), true)
+	if err != nil {
+		log.Errorf("[handleAttestation] TryGetAndChange ST_Claim error: %v", err)
+		return err
+	}
+	if state == nil {
+		log.Warnf("[handleAttestation] claim %x not committed", p.ClaimHash.ToArray())
+		return nil
+	}
+	claim := state.(*ClaimState)
+	if !claim.Revoked {
+		claim.Revoked = true
+		claim.RevokeHeight = height
+	}
+	return nil
+}
+
+// handleFreeze freezes or unfreezes an asset or an address within it.
+func handleFreeze(stateStore *StateStore, p *payload.Freeze) error {
+	if p.Target == payload.FreezeTarget_ASSET {
+		state, err := stateStore.TryGetAndChange(ST_Asset, p.AssetID.ToArray(), false)
+		if err != nil {
+			log.Errorf("[handleFreeze] TryGetAndChange ST_Asset error: %v", err)
+			return err
+		}
+		if state == nil {
+			log.Warnf("[handleFreeze] asset %x not registered", p.AssetID.ToArray())
+			return nil
+		}
+		state.(*AssetState).IsFrozen = p.Frozen
+		return nil
+	}
+	key := append(p.AssetID.ToArray(), p.Account.ToArray()...)
+	state, err := stateStore.TryGetAndChange(ST_FrozenAccount, key, false)
+	if err != nil {
+		log.Errorf("[handleFreeze] TryGetAndChange ST_FrozenAccount error: %v", err)
+		return err
+	}
+	if state == nil {
+		stateStore.TryAdd(ST_FrozenAccount, key, &FrozenAccountState{AssetId: p.AssetID, ProgramHash: p.Account, IsFrozen: p.Frozen}, false)
+		return nil
+	}
+	state.(*FrozenAccountState).IsFrozen = p.Frozen
+	return nil
+}
@@ -0,0 +1,459 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ChainStore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	"github.com/Ontology/core/states"
+	. "github.com/Ontology/core/store"
+	tx "github.com/Ontology/core/transaction"
+)
+
+const (
+	// trie nodes share the database with the chain data and are stored under their bare hash
+	TrieNodeName     = "TRIE_Node"
+	TriePreimageName = "TRIE_Preimage"
+	UnknownName      = "UNKNOWN"
+)
+
+var trieSecureKeyPrefix = []byte("secure-key-")
+
+var prefixNames = map[DataEntryPrefix]string{
+	DATA_Block:           "DATA_Block",
+	DATA_Header:          "DATA_Header",
+	DATA_Transaction:     "DATA_Transaction",
+	ST_Account:           "ST_Account",
+	ST_Coin:              "ST_Coin",
+	ST_SpentCoin:         "ST_SpentCoin",
+	ST_BookKeeper:        "ST_BookKeeper",
+	ST_Asset:             "ST_Asset",
+	ST_Contract:          "ST_Contract",
+	ST_Storage:           "ST_Storage",
+	ST_Identity:          "ST_Identity",
+	ST_Program_Coin:      "ST_Program_Coin",
+	ST_Validator:         "ST_Validator",
+	ST_Vote:              "ST_Vote",
+	IX_HeaderHashList:    "IX_HeaderHashList",
+	SYS_CurrentBlock:     "SYS_CurrentBlock",
+	SYS_Version:          "SYS_Version",
+	Sys_CurrentStateRoot: "Sys_CurrentStateRoot",
+	SYS_BlockMerkleTree:  "SYS_BlockMerkleTree",
+	ST_Claim:             "ST_Claim",
+	ST_FrozenAccount:     "ST_FrozenAccount",
+	DATA_Notify:          "DATA_Notify",
+	IX_NotifyContract:    "IX_NotifyContract",
+	IX_NotifyEvent:       "IX_NotifyEvent",
+}
+
+// PrefixName returns the name of a DataEntryPrefix.
+func PrefixName(prefix DataEntryPrefix) string {
+	if name, ok := prefixNames[prefix]; ok {
+		return name
+	}
+	return UnknownName
+}
+
+// ParsePrefix accepts a DataEntryPrefix name (case insensitive) or its numeric value.
+func ParsePrefix(s string) (DataEntryPrefix, error) {
+	for prefix, name := range prefixNames {
+		if strings.EqualFold(name, s) {
+			return prefix, nil
+		}
+	}
+	n, err := strconv.ParseUint(s, 0, 8)
+	if err != nil {
+		return 0, fmt.Errorf("unknown data entry prefix %q", s)
+	}
+	return DataEntryPrefix(n), nil
+}
+
+// Prefixes returns all known prefixes in ascending order.
+func Prefixes() []DataEntryPrefix {
+	prefixes := make([]DataEntryPrefix, 0, len(prefixNames))
+	for prefix := range prefixNames {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return prefixes[i] < prefixes[j] })
+	return prefixes
+}
+
+// ClassifyKey returns the name of the entry type a raw database key belongs to.
+// Keys are classified by their prefix, the 32 byte keys left are trie nodes.
+// A trie node whose hash starts with a known prefix is only told apart when
+// its value does not decode, see DecodeEntry.
+func ClassifyKey(key []byte) string {
+	if bytes.HasPrefix(key, trieSecureKeyPrefix) {
+		return TriePreimageName
+	}
+	if len(key) == 0 {
+		return UnknownName
+	}
+	if name := PrefixName(DataEntryPrefix(key[0])); name != UnknownName {
+		return name
+	}
+	if len(key) == UINT256SIZE {
+		return TrieNodeName
+	}
+	return UnknownName
+}
+
+// Entry is a decoded database record.
+type Entry struct {
+	Prefix string
+	Key    string
+	Size   int
+	Value  interface{}
+	Error  string `json:",omitempty"`
+}
+
+type blockIndexValue struct {
+	Height uint32
+	Hash   string
+}
+
+type headerValue struct {
+	SysFee           Fixed64
+	Version          uint32
+	PrevBlockHash    string
+	TransactionsRoot string
+	StateRoot        string
+	BlockRoot        string
+	Timestamp        uint32
+	Height           uint32
+	ConsensusData    uint64
+	NextBookKeeper   string
+	Transactions     []string
+	Hash             string
+}
+
+type transactionValue struct {
+	Height     uint32
+	Hash       string
+	TxType     tx.TransactionType
+	Attributes int
+	Inputs     int
+	Outputs    int
+	Programs   int
+}
+
+type notifyValue struct {
+	Height    uint32
+	Seq       uint32
+	TxHash    string
+	Index     uint32
+	CodeHash  string
+	EventName string
+	State     string
+}
+
+type notifyIndexValue struct {
+	Height    uint32
+	Seq       uint32
+	CodeHash  string `json:",omitempty"`
+	EventName string `json:",omitempty"`
+}
+
+type hashListValue struct {
+	Start  uint32
+	Hashes []string
+}
+
+type merkleTreeValue struct {
+	TreeSize uint32
+	Hashes   []string
+}
+
+// DecodeEntry decodes a raw key/value pair according to its DataEntryPrefix.
+// State prefixes are decoded through the same path the state store uses.
+func DecodeEntry(key, value []byte) *Entry {
+	entry := &Entry{
+		Prefix: ClassifyKey(key),
+		Key:    ToHexString(key),
+		Size:   len(value),
+	}
+	if entry.Prefix == UnknownName || entry.Prefix == TrieNodeName || entry.Prefix == TriePreimageName {
+		entry.Value = ToHexString(value)
+		return entry
+	}
+	entry.Key = ToHexString(key[1:])
+	v, err := decodeValue(DataEntryPrefix(key[0]), key[1:], value)
+	if err != nil && len(key) == UINT256SIZE {
+		// a trie node whose hash starts with a known prefix
+		entry.Prefix = TrieNodeName
+		entry.Key = ToHexString(key)
+		entry.Value = ToHexString(value)
+		return entry
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		entry.Value = ToHexString(value)
+		return entry
+	}
+	entry.Value = v
+	return entry
+}
+
+func decodeValue(prefix DataEntryPrefix, key, value []byte) (interface{}, error) {
+	r := bytes.NewReader(value)
+	switch prefix {
+	case DATA_Block:
+		if len(key) != 4 {
+			return nil, errors.New("invalid block index key")
+		}
+		hash, err := Uint256ParseFromBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return &blockIndexValue{Height: binary.LittleEndian.Uint32(key), Hash: ToHexString(hash.ToArray())}, nil
+	case DATA_Header:
+		return decodeHeader(r)
+	case DATA_Transaction:
+		height, err := serialization.ReadUint32(r)
+		if err != nil {
+			return nil, err
+		}
+		t := new(tx.Transaction)
+		if err := t.Deserialize(r); err != nil {
+			return nil, err
+		}
+		hash := t.Hash()
+		return &transactionValue{
+			Height:     height,
+			Hash:       ToHexString(hash.ToArray()),
+			TxType:     t.TxType,
+			Attributes: len(t.Attributes),
+			Inputs:     len(t.UTXOInputs),
+			Outputs:    len(t.Outputs),
+			Programs:   len(t.Programs),
+		}, nil
+	case ST_Account, ST_Coin, ST_SpentCoin, ST_BookKeeper, ST_Asset, ST_Contract, ST_Storage,
+		ST_Program_Coin, ST_Validator, ST_Vote, ST_Identity, ST_Claim, ST_FrozenAccount:
+		return getStateObject(prefix, value)
+	case DATA_Notify:
+		if len(key) != 8 {
+			return nil, errors.New("invalid notify key")
+		}
+		notify := new(states.NotifyState)
+		if err := notify.Deserialize(r); err != nil {
+			return nil, err
+		}
+		return &notifyValue{
+			Height:    binary.BigEndian.Uint32(key),
+			Seq:       binary.BigEndian.Uint32(key[4:]),
+			TxHash:    ToHexString(notify.TxHash.ToArray()),
+			Index:     notify.Index,
+			CodeHash:  ToHexString(notify.CodeHash.ToArray()),
+			EventName: notify.EventName,
+			State:     ToHexString(notify.State),
+		}, nil
+	case IX_NotifyContract:
+		if len(key) != UINT160SIZE+8 {
+			return nil, errors.New("invalid notify contract index key")
+		}
+		pos := key[UINT160SIZE:]
+		return &notifyIndexValue{
+			Height:   binary.BigEndian.Uint32(pos),
+			Seq:      binary.BigEndian.Uint32(pos[4:]),
+			CodeHash: ToHexString(key[:UINT160SIZE]),
+		}, nil
+	case IX_NotifyEvent:
+		kr := bytes.NewReader(key)
+		name, err := serialization.ReadVarString(kr)
+		if err != nil {
+			return nil, err
+		}
+		if kr.Len() != 8 {
+			return nil, errors.New("invalid notify event index key")
+		}
+		pos := key[len(key)-8:]
+		return &notifyIndexValue{
+			Height:    binary.BigEndian.Uint32(pos),
+			Seq:       binary.BigEndian.Uint32(pos[4:]),
+			EventName: name,
+		}, nil
+	case IX_HeaderHashList:
+		if len(key) != 4 {
+			return nil, errors.New("invalid header hash list key")
+		}
+		hashes, err := readHashList(r)
+		if err != nil {
+			return nil, err
+		}
+		return &hashListValue{Start: binary.LittleEndian.Uint32(key), Hashes: hashes}, nil
+	case SYS_CurrentBlock, SYS_Version, SYS_BlockMerkleTree:
+		if len(key) != 0 {
+			return nil, errors.New("invalid system key")
+		}
+		return decodeSystemValue(prefix, r, value)
+	case Sys_CurrentStateRoot:
+		if !bytes.Equal(key, CurrentStateRoot) {
+			return nil, errors.New("invalid state root key")
+		}
+		return decodeSystemValue(prefix, r, value)
+	}
+	return nil, fmt.Errorf("unknown data entry prefix %d", prefix)
+}
+
+func decodeSystemValue(prefix DataEntryPrefix, r *bytes.Reader, value []byte) (interface{}, error) {
+	switch prefix {
+	case SYS_CurrentBlock:
+		var hash Uint256
+		if err := hash.Deserialize(r); err != nil {
+			return nil, err
+		}
+		height, err := serialization.ReadUint32(r)
+		if err != nil {
+			return nil, err
+		}
+		return &blockIndexValue{Height: height, Hash: ToHexString(hash.ToArray())}, nil
+	case SYS_Version:
+		return ToHexString(value), nil
+	case Sys_CurrentStateRoot:
+		root, err := Uint256ParseFromBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return ToHexString(root.ToArray()), nil
+	case SYS_BlockMerkleTree:
+		if len(value) < 4 || (len(value)-4)%UINT256SIZE != 0 {
+			return nil, errors.New("invalid block merkle tree")
+		}
+		tree := &merkleTreeValue{TreeSize: binary.BigEndian.Uint32(value[0:4])}
+		for i := 4; i < len(value); i += UINT256SIZE {
+			tree.Hashes = append(tree.Hashes, ToHexString(value[i:i+UINT256SIZE]))
+		}
+		return tree, nil
+	}
+	return nil, fmt.Errorf("unknown data entry prefix %d", prefix)
+}
+
+func decodeHeader(r *bytes.Reader) (*headerValue, error) {
+	sysfee, err := serialization.ReadUint64(r)
+	if err != nil {
+		return nil, err
+	}
+	header, txHashes, err := parseTrimmedBlock(r)
+	if err != nil {
+		return nil, err
+	}
+	hash := header.Hash()
+	value := &headerValue{
+		SysFee:           Fixed64(sysfee),
+		Version:          header.Version,
+		PrevBlockHash:    ToHexString(header.PrevBlockHash.ToArray()),
+		TransactionsRoot: ToHexString(header.TransactionsRoot.ToArray()),
+		StateRoot:        ToHexString(header.StateRoot.ToArray()),
+		BlockRoot:        ToHexString(header.BlockRoot.ToArray()),
+		Timestamp:        header.Timestamp,
+		Height:           header.Height,
+		ConsensusData:    header.ConsensusData,
+		NextBookKeeper:   ToHexString(header.NextBookKeeper.ToArray()),
+		Hash:             ToHexString(hash.ToArray()),
+	}
+	for _, h := range txHashes {
+		value.Transactions = append(value.Transactions, ToHexString(h.ToArray()))
+	}
+	return value, nil
+}
+
+func readHashList(r *bytes.Reader) ([]string, error) {
+	n, err := serialization.ReadVarUint(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	var hashes []string
+	for i := uint64(0); i < n; i++ {
+		var h Uint256
+		if err := h.Deserialize(r); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, ToHexString(h.ToArray()))
+	}
+	return hashes, nil
+}
+
+// ScanEntries iterates the records whose key starts with prefix and calls fn with
+// each decoded entry until fn returns false or limit entries were visited.
+// A limit of zero means no limit.
+func ScanEntries(st IStore, prefix []byte, limit int, fn func(*Entry) bool) {
+	iter := st.NewIterator(prefix)
+	defer iter.Release()
+	for n := 0; iter.Next(); n++ {
+		if limit > 0 && n >= limit {
+			return
+		}
+		if !fn(DecodeEntry(iter.Key(), iter.Value())) {
+			return
+		}
+	}
+}
+
+// PrefixStat holds the number and total size of records of one entry type.
+type PrefixStat struct {
+	Prefix    string
+	Count     int
+	KeySize   int64
+	ValueSize int64
+}
+
+// CollectStats counts records and their sizes per entry type over the whole database.
+func CollectStats(st IStore) []*PrefixStat {
+	stats := make(map[string]*PrefixStat)
+	iter := st.NewIterator(nil)
+	for iter.Next() {
+		name := ClassifyKey(iter.Key())
+		if len(iter.Key()) == UINT256SIZE && name != TrieNodeName && name != TriePreimageName {
+			name = DecodeEntry(iter.Key(), iter.Value()).Prefix
+		}
+		stat, ok := stats[name]
+		if !ok {
+			stat = &PrefixStat{Prefix: name}
+			stats[name] = stat
+		}
+		stat.Count++
+		stat.KeySize += int64(len(iter.Key()))
+		stat.ValueSize += int64(len(iter.Value()))
+	}
+	iter.Release()
+
+	order := make(map[string]int)
+	for i, prefix := range Prefixes() {
+		order[PrefixName(prefix)] = i
+	}
+	order[TrieNodeName] = len(order)
+	order[TriePreimageName] = len(order)
+	order[UnknownName] = len(order)
+
+	result := make([]*PrefixStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, stat)
+	}
+	sort.Slice(result, func(i, j int) bool { return order[result[i].Prefix] < order[result[j].Prefix] })
+	return result
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ChainStore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	"github.com/Ontology/core/states"
+	. "github.com/Ontology/core/store"
+	"github.com/Ontology/core/store/LevelDBStore"
+)
+
+func fillKey(prefix DataEntryPrefix, size int, fill byte) []byte {
+	return append([]byte{byte(prefix)}, bytes.Repeat([]byte{fill}, size-1)...)
+}
+
+func TestInspectorEntries(t *testing.T) {
+	db, err := LevelDBStore.NewMemLevelDBStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	serialize := func(state states.IStateValue) []byte {
+		var buf bytes.Buffer
+		if err := state.Serialize(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	var current bytes.Buffer
+	blockHash := common.Uint256{1}
+	blockHash.Serialize(&current)
+	serialization.WriteUint32(&current, 7)
+
+	// a 10 byte storage key and a 31 byte ONT ID make 32 byte keys
+	storageKey := fillKey(ST_Storage, 32, 1)
+	identityKey := fillKey(ST_Identity, 32, 2)
+	// trie nodes are stored under their hash, which may start with a known prefix
+	trieNode := fillKey(0xee, 32, 3)
+	prefixedTrieNode := fillKey(DATA_Block, 32, 4)
+	preimage := append([]byte("secure-key-"), bytes.Repeat([]byte{5}, 32)...)
+	records := []struct {
+		key, value []byte
+		prefix     string
+	}{
+		{storageKey, serialize(&states.StorageItem{Value: []byte("value")}), "ST_Storage"},
+		{identityKey, serialize(&states.IdentityState{ID: identityKey[1:]}), "ST_Identity"},
+		{trieNode, []byte{0xc2, 0x80, 0x80}, TrieNodeName},
+		{prefixedTrieNode, []byte{0xc2, 0x80, 0x80}, TrieNodeName},
+		{preimage, bytes.Repeat([]byte{6}, 20), TriePreimageName},
+		{[]byte{byte(SYS_CurrentBlock)}, current.Bytes(), "SYS_CurrentBlock"},
+		{fillKey(0xef, 5, 7), []byte{1}, UnknownName},
+	}
+	for _, r := range records {
+		db.Put(r.key, r.value)
+	}
+
+	entries := make(map[string]*Entry)
+	ScanEntries(db, nil, 0, func(entry *Entry) bool {
+		entries[entry.Prefix+" "+entry.Key] = entry
+		return true
+	})
+	if len(entries) != len(records) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(records))
+	}
+	for _, r := range records {
+		k := r.key
+		if r.prefix != TrieNodeName && r.prefix != TriePreimageName && r.prefix != UnknownName {
+			k = k[1:]
+		}
+		entry, ok := entries[r.prefix+" "+common.ToHexString(k)]
+		if !ok {
+			t.Errorf("%x: not found as %s", r.key, r.prefix)
+			continue
+		}
+		if entry.Error != "" {
+			t.Errorf("%x: %s", r.key, entry.Error)
+		}
+	}
+	if item, ok := entries["ST_Storage "+common.ToHexString(storageKey[1:])].Value.(*states.StorageItem); !ok || string(item.Value) != "value" {
+		t.Errorf("storage item not decoded")
+	}
+	if identity, ok := entries["ST_Identity "+common.ToHexString(identityKey[1:])].Value.(*states.IdentityState); !ok || !bytes.Equal(identity.ID, identityKey[1:]) {
+		t.Errorf("identity not decoded")
+	}
+	if block, ok := entries["SYS_CurrentBlock "].Value.(*blockIndexValue); !ok || block.Height != 7 {
+		t.Errorf("current block not decoded")
+	}
+
+	// scanning from a prefix only visits its records
+	var scanned []*Entry
+	ScanEntries(db, []byte{byte(ST_Storage)}, 0, func(entry *Entry) bool {
+		scanned = append(scanned, entry)
+		return true
+	})
+	if len(scanned) != 1 || scanned[0].Prefix != "ST_Storage" {
+		t.Errorf("storage scan: got %d entries", len(scanned))
+	}
+
+	counts := make(map[string]int)
+	for _, stat := range CollectStats(db) {
+		counts[stat.Prefix] = stat.Count
+	}
+	want := map[string]int{"ST_Storage": 1, "ST_Identity": 1, TrieNodeName: 2, TriePreimageName: 1, "SYS_CurrentBlock": 1, UnknownName: 1}
+	for prefix, n := range want {
+		if counts[prefix] != n {
+			t.Errorf("%s: counted %d, want %d", prefix, counts[prefix], n)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ChainStore
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/common/log"
+	"github.com/Ontology/common/serialization"
+	. "github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/states"
+	. "github.com/Ontology/core/store"
+	"github.com/Ontology/smartcontract/event"
+	"github.com/Ontology/vm/neovm"
+	"github.com/Ontology/vm/neovm/types"
+)
+
+// notifyPosition is the suffix of all notify keys: the block height and the
+// sequence number of the notification in the block, big endian so that keys
+// sort in block order.
+func notifyPosition(height, seq uint32) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b, height)
+	binary.BigEndian.PutUint32(b[4:], seq)
+	return b
+}
+
+func notifyEventPrefix(name string) []byte {
+	w := bytes.NewBuffer([]byte{byte(IX_NotifyEvent)})
+	serialization.WriteVarString(w, name)
+	return w.Bytes()
+}
+
+func notifyContractPrefix(codeHash Uint160) []byte {
+	return append([]byte{byte(IX_NotifyContract)}, codeHash.ToArray()...)
+}
+
+// persistNotifications writes the notifications of one transaction to the
+// batch, numbering them from seq. It returns the next sequence number.
+func (bd *ChainStore) persistNotifications(height, seq uint32, txHash Uint256, notifications []*event.NotifyEventArgs) uint32 {
+	for i, n := range notifications {
+		state, err := types.SerializeStackItem(n.State, int(neovm.MaxItemSize))
+		if err != nil {
+			log.Warnf("[persistNotifications] tx %x notification %d: %v", txHash.ToArray(), i, err)
+		}
+		notify := &states.NotifyState{
+			Height:    height,
+			TxHash:    txHash,
+			Index:     uint32(i),
+			CodeHash:  n.CodeHash,
+			EventName: n.EventName(),
+			State:     state,
+		}
+		pos := notifyPosition(height, seq)
+		bd.st.BatchPut(append([]byte{byte(DATA_Notify)}, pos...), notify.ToArray())
+		bd.st.BatchPut(append(notifyContractPrefix(n.CodeHash), pos...), []byte{})
+		if notify.EventName != "" {
+			bd.st.BatchPut(append(notifyEventPrefix(notify.EventName), pos...), []byte{})
+		}
+		seq++
+	}
+	return seq
+}
+
+func (bd *ChainStore) GetNotifications(filter *NotifyFilter) ([]*states.NotifyState, error) {
+	toHeight := filter.ToHeight
+	if toHeight == 0 || toHeight > bd.GetHeight() {
+		toHeight = bd.GetHeight()
+	}
+	limit := filter.Limit
+	if limit <= 0 || limit > MaxNotifyQueryLimit {
+		limit = MaxNotifyQueryLimit
+	}
+
+	// walk the narrowest index, the other filters are checked on the records
+	var prefix []byte
+	if filter.CodeHash != nil {
+		prefix = notifyContractPrefix(*filter.CodeHash)
+	} else if filter.EventName != "" {
+		prefix = notifyEventPrefix(filter.EventName)
+	} else {
+		prefix = []byte{byte(DATA_Notify)}
+	}
+	iter := bd.st.NewIterator(prefix)
+	defer iter.Release()
+
+	var results []*states.NotifyState
+	skip := filter.Offset
+	for ok := iter.Seek(append(prefix, notifyPosition(filter.FromHeight, 0)...)); ok; ok = iter.Next() {
+		pos := iter.Key()[len(prefix):]
+		if len(pos) != 8 {
+			continue
+		}
+		if binary.BigEndian.Uint32(pos) > toHeight {
+			break
+		}
+		value := iter.Value()
+		if prefix[0] != byte(DATA_Notify) {
+			var err error
+			if value, err = bd.st.Get(append([]byte{byte(DATA_Notify)}, pos...)); err != nil {
+				return nil, err
+			}
+		}
+		notify := new(states.NotifyState)
+		if err := notify.Deserialize(bytes.NewReader(value)); err != nil {
+			return nil, err
+		}
+		if filter.EventName != "" && notify.EventName != filter.EventName {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		results = append(results, notify)
+		if len(results) == limit {
+			break
+		}
+	}
+	return results, nil
+}
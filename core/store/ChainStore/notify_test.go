@@ -0,0 +1,166 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ChainStore
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	. "github.com/Ontology/common"
+	. "github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/states"
+	"github.com/Ontology/core/store/LevelDBStore"
+	"github.com/Ontology/smartcontract/event"
+	"github.com/Ontology/vm/neovm/types"
+)
+
+func notifyArgs(codeHash Uint160, name string) *event.NotifyEventArgs {
+	if name == "" {
+		return &event.NotifyEventArgs{CodeHash: codeHash, State: types.NewInteger(big.NewInt(1))}
+	}
+	state := types.NewArray([]types.StackItemInterface{types.NewByteArray([]byte(name)), types.NewInteger(big.NewInt(7))})
+	return &event.NotifyEventArgs{CodeHash: codeHash, State: state}
+}
+
+func notifyIDs(notifications []*states.NotifyState) []string {
+	ids := make([]string, 0, len(notifications))
+	for _, n := range notifications {
+		ids = append(ids, fmt.Sprintf("%d/%x/%d", n.Height, n.TxHash.ToArray()[:1], n.Index))
+	}
+	return ids
+}
+
+func TestGetNotifications(t *testing.T) {
+	db, err := LevelDBStore.NewMemLevelDBStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bd := newChainStore(db)
+	defer bd.Close()
+
+	a, b := Uint160{0xa}, Uint160{0xb}
+	// every block has two transactions, the sequence numbers run across both
+	var all []*states.NotifyState
+	bd.st.NewBatch()
+	for height := uint32(1); height <= 4; height++ {
+		txs := []struct {
+			hash          Uint256
+			notifications []*event.NotifyEventArgs
+		}{
+			{Uint256{byte(height), 1}, []*event.NotifyEventArgs{notifyArgs(a, "transfer"), notifyArgs(b, "approve"), notifyArgs(a, "transfer")}},
+			{Uint256{byte(height), 2}, []*event.NotifyEventArgs{notifyArgs(b, "transfer"), notifyArgs(a, "")}},
+		}
+		var seq uint32
+		for _, tx := range txs {
+			seq = bd.persistNotifications(height, seq, tx.hash, tx.notifications)
+			for i, n := range tx.notifications {
+				if height <= 3 {
+					all = append(all, &states.NotifyState{Height: height, TxHash: tx.hash, Index: uint32(i), CodeHash: n.CodeHash, EventName: n.EventName()})
+				}
+			}
+		}
+	}
+	if err := bd.st.BatchCommit(); err != nil {
+		t.Fatal(err)
+	}
+	// notifications above the current height are not returned
+	bd.currentBlockHeight = 3
+
+	match := func(filter *NotifyFilter) []*states.NotifyState {
+		var matched []*states.NotifyState
+		for _, n := range all {
+			if filter.CodeHash != nil && n.CodeHash != *filter.CodeHash ||
+				filter.EventName != "" && n.EventName != filter.EventName ||
+				n.Height < filter.FromHeight ||
+				filter.ToHeight != 0 && n.Height > filter.ToHeight {
+				continue
+			}
+			matched = append(matched, n)
+		}
+		return matched
+	}
+	filters := []*NotifyFilter{
+		{},
+		{CodeHash: &a},
+		{CodeHash: &b},
+		{EventName: "transfer"},
+		{EventName: "approve"},
+		{EventName: "unknown"},
+		{CodeHash: &b, EventName: "transfer"},
+		{FromHeight: 2},
+		{FromHeight: 2, ToHeight: 2},
+		{ToHeight: 10},
+		{CodeHash: &a, FromHeight: 3, ToHeight: 3},
+		{EventName: "transfer", FromHeight: 2, ToHeight: 3},
+	}
+	for _, filter := range filters {
+		got, err := bd.GetNotifications(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := match(filter)
+		if !reflect.DeepEqual(notifyIDs(got), notifyIDs(want)) {
+			t.Errorf("%+v: got %v, want %v", filter, notifyIDs(got), notifyIDs(want))
+		}
+		for i := range got {
+			if i < len(want) && (got[i].CodeHash != want[i].CodeHash || got[i].EventName != want[i].EventName) {
+				t.Errorf("%+v: notification %d is %x %q", filter, i, got[i].CodeHash.ToArray(), got[i].EventName)
+			}
+		}
+	}
+
+	got, err := bd.GetNotifications(&NotifyFilter{EventName: "approve", Limit: 1})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("approve: got %v, %v", got, err)
+	}
+	state, err := types.DeserializeStackItem(got[0].State, 16, 1024)
+	if err != nil || len(state.GetArray()) != 2 || string(state.GetArray()[0].GetByteArray()) != "approve" {
+		t.Errorf("state: got %v, %v", state, err)
+	}
+
+	// pages of two split the three transfers of each block
+	for _, filter := range []NotifyFilter{{EventName: "transfer"}, {CodeHash: &a}, {}} {
+		want := notifyIDs(match(&filter))
+		var pages []string
+		for offset := 0; ; offset += 2 {
+			page := filter
+			page.Offset, page.Limit = offset, 2
+			got, err := bd.GetNotifications(&page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) > 2 {
+				t.Fatalf("%+v: page of %d", page, len(got))
+			}
+			if len(got) == 0 {
+				break
+			}
+			pages = append(pages, notifyIDs(got)...)
+		}
+		if !reflect.DeepEqual(pages, want) {
+			t.Errorf("%+v: pages %v, want %v", filter, pages, want)
+		}
+	}
+	if got, _ := bd.GetNotifications(&NotifyFilter{Offset: len(all)}); len(got) != 0 {
+		t.Errorf("offset past the end: got %v", notifyIDs(got))
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ChainStore
+
+import (
+	"bytes"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	. "github.com/Ontology/core/store"
+	"github.com/Ontology/core/store/statestore"
+)
+
+// NewSandboxChainStore wraps an opened store, e.g. the database of a stopped node
+// or a memory store, so contracts can be run against its current state.
+func NewSandboxChainStore(st IStore) (*ChainStore, error) {
+	chain := newChainStore(st)
+	data, err := st.Get([]byte{byte(SYS_CurrentBlock)})
+	if isNotFound(err) {
+		return chain, nil
+	} else if err != nil {
+		return nil, err
+	}
+	r := bytes.NewReader(data)
+	var hash Uint256
+	if err := hash.Deserialize(r); err != nil {
+		return nil, err
+	}
+	if chain.currentBlockHeight, err = serialization.ReadUint32(r); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
+// NewSandboxStateStore returns a state store over the current state root. Its
+// changes stay in memory unless CommitTo is called.
+func (bd *ChainStore) NewSandboxStateStore() *StateStore {
+	return NewStateStore(statestore.NewMemDatabase(), bd, statestore.NewTrieStore(bd.st), bd.GetCurrentStateRoot())
+}
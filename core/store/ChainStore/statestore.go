@@ -21,11 +21,13 @@ package ChainStore
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"github.com/Ontology/common"
 	"github.com/Ontology/common/log"
 	. "github.com/Ontology/core/states"
 	. "github.com/Ontology/core/store"
 	"github.com/Ontology/core/store/statestore"
+	"sort"
 	"strings"
 )
 
@@ -49,18 +51,38 @@ func NewStateStore(memoryStore IMemoryStore, db *ChainStore, trieStore statestor
 	}
 }
 
+// Find returns the items whose key starts with key, merging the uncommitted
+// changes of the memory store over the persisted ones, ordered by key.
 func (self *StateStore) Find(prefix DataEntryPrefix, key []byte) ([]*StateItem, error) {
-	var states []*StateItem
+	items := make(map[string]*StateItem)
 	iter := self.db.st.NewIterator(append([]byte{byte(prefix)}, key...))
 	for iter.Next() {
 		key := iter.Key()
 		value := iter.Value()
 		state, err := getStateObject(prefix, value)
 		if err != nil {
+			iter.Release()
 			return nil, err
 		}
-		states = append(states, &StateItem{Key: string(key[1:]), Value: state})
+		items[string(key[1:])] = &StateItem{Key: string(key[1:]), Value: state}
 	}
+	iter.Release()
+	seek := string(append([]byte{byte(prefix)}, key...))
+	for k, v := range self.memoryStore.GetChangeSet() {
+		if !strings.HasPrefix(k, seek) {
+			continue
+		}
+		if v.State == Deleted {
+			delete(items, k[1:])
+		} else {
+			items[k[1:]] = &StateItem{Key: k[1:], Value: v.Value}
+		}
+	}
+	var states []*StateItem
+	for _, v := range items {
+		states = append(states, v)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Key < states[j].Key })
 	return states, nil
 }
 
@@ -163,7 +185,7 @@ func (self *StateStore) CommitTo() error {
 				return err
 			}
 			if v.Trie {
-				value, _ := common.Uint256ParseFromBytes(data.Bytes())
+				value := trieLeafValue(DataEntryPrefix(k[0]), data.Bytes())
 				if err := self.trie.TryUpdate([]byte(k), value.ToArray()); err != nil {
 					return err
 				}
@@ -176,6 +198,16 @@ func (self *StateStore) CommitTo() error {
 	return nil
 }
 
+// trieLeafValue returns the value the state trie holds for a state entry.
+func trieLeafValue(prefix DataEntryPrefix, data []byte) common.Uint256 {
+	// identity and claim leaves commit to the whole state
+	if prefix == ST_Identity || prefix == ST_Claim {
+		return common.Uint256(sha256.Sum256(data))
+	}
+	value, _ := common.Uint256ParseFromBytes(data)
+	return value
+}
+
 func (self *StateStore) setStateObject(prefix byte, key []byte, value IStateValue, state ItemState, trie bool) {
 	self.memoryStore.Put(prefix, key, value, state, trie)
 }
@@ -231,6 +263,36 @@ func getStateObject(prefix DataEntryPrefix, enc []byte) (IStateValue, error) {
 			return nil, err
 		}
 		return programCoin, nil
+	case ST_Validator:
+		validator := new(ValidatorState)
+		if err := validator.Deserialize(reader); err != nil {
+			return nil, err
+		}
+		return validator, nil
+	case ST_Vote:
+		vote := new(VoteState)
+		if err := vote.Deserialize(reader); err != nil {
+			return nil, err
+		}
+		return vote, nil
+	case ST_Identity:
+		identity := new(IdentityState)
+		if err := identity.Deserialize(reader); err != nil {
+			return nil, err
+		}
+		return identity, nil
+	case ST_Claim:
+		claim := new(ClaimState)
+		if err := claim.Deserialize(reader); err != nil {
+			return nil, err
+		}
+		return claim, nil
+	case ST_FrozenAccount:
+		frozen := new(FrozenAccountState)
+		if err := frozen.Deserialize(reader); err != nil {
+			return nil, err
+		}
+		return frozen, nil
 	default:
 		panic("[getStateObject] invalid state type!")
 	}
@@ -252,6 +314,12 @@ func newStateObject(prefix DataEntryPrefix) IStateValue {
 		return new(ContractState)
 	case ST_Storage:
 		return new(StorageItem)
+	case ST_Identity:
+		return new(IdentityState)
+	case ST_Claim:
+		return new(ClaimState)
+	case ST_FrozenAccount:
+		return new(FrozenAccountState)
 	default:
 		panic("[newStateObject] invalid state type!")
 	}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ChainStore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Ontology/core/states"
+	. "github.com/Ontology/core/store"
+	"github.com/Ontology/core/store/LevelDBStore"
+	"github.com/Ontology/core/store/statestore"
+)
+
+func TestStateStoreFind(t *testing.T) {
+	db, err := LevelDBStore.NewMemLevelDBStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	persisted := map[string]string{"a1": "x", "a2": "y", "a3": "z", "b1": "w"}
+	for k, v := range persisted {
+		buf := new(bytes.Buffer)
+		if err := (&states.StorageItem{Value: []byte(v)}).Serialize(buf); err != nil {
+			t.Fatal(err)
+		}
+		db.Put(append([]byte{byte(ST_Storage)}, k...), buf.Bytes())
+	}
+
+	memory := statestore.NewMemDatabase()
+	memory.Put(byte(ST_Storage), []byte("a2"), &states.StorageItem{Value: []byte("Y")}, Changed, true)
+	memory.Put(byte(ST_Storage), []byte("a0"), &states.StorageItem{Value: []byte("v")}, Changed, true)
+	memory.Put(byte(ST_Storage), []byte("b2"), &states.StorageItem{Value: []byte("u")}, Changed, true)
+	memory.Put(byte(ST_Contract), []byte("a5"), &states.StorageItem{Value: []byte("t")}, Changed, true)
+	memory.Delete(byte(ST_Storage), []byte("a3"))
+
+	stateStore := &StateStore{db: &ChainStore{st: db}, memoryStore: memory}
+	items, err := stateStore.Find(ST_Storage, []byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"a0", "v"}, {"a1", "x"}, {"a2", "Y"}}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for i, item := range items {
+		value := string(item.Value.(*states.StorageItem).Value)
+		if item.Key != want[i][0] || value != want[i][1] {
+			t.Errorf("item %d: got %s=%s, want %s=%s", i, item.Key, value, want[i][0], want[i][1])
+		}
+	}
+}
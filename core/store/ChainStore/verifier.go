@@ -0,0 +1,589 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ChainStore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	. "github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/states"
+	. "github.com/Ontology/core/store"
+	tx "github.com/Ontology/core/transaction"
+	"github.com/Ontology/crypto"
+	"github.com/Ontology/merkle"
+	"github.com/Ontology/trie"
+)
+
+const (
+	CheckHeader           = "header"
+	CheckHeaderLink       = "header hash linkage"
+	CheckBlockIndex       = "block height index"
+	CheckHeaderHashList   = "header hash list"
+	CheckTransaction      = "transaction"
+	CheckTransactionsRoot = "transactions root"
+	CheckBlockRoot        = "block root"
+	CheckBlockMerkleTree  = "block merkle tree"
+	CheckMerkleHashStore  = "merkle hash store"
+	CheckStateRoot        = "state root"
+	CheckCoin             = "unspent coin"
+)
+
+// Divergence describes the first inconsistency found while verifying a chain database.
+type Divergence struct {
+	Height uint32
+	Check  string
+	Detail string
+}
+
+func (d *Divergence) Error() string {
+	return fmt.Sprintf("height %d: %s: %s", d.Height, d.Check, d.Detail)
+}
+
+// VerifyResult is the outcome of VerifyStore.
+type VerifyResult struct {
+	CurrentHeight uint32
+	CurrentHash   Uint256
+	Blocks        uint32
+	Transactions  int
+	Divergence    *Divergence
+	Repaired      []string
+}
+
+type coinRecord struct {
+	height uint32
+	items  []states.CoinState
+}
+
+type storeVerifier struct {
+	st         IStore
+	merklePath string
+	repair     bool
+
+	result *VerifyResult
+	hashes []Uint256
+	coins  map[Uint256]*coinRecord
+	tree   *merkle.CompactMerkleTree
+	mem    *merkle.MemHashStore
+}
+
+// VerifyStore walks every block from genesis to the current block of a stopped
+// node's database and checks header linkage, transactions roots, block roots,
+// the persisted block merkle tree, the ST_Coin states and the state trie of the
+// stored state root against the header and the state entries.
+// Derived indexes (block height index, header hash list and block merkle tree)
+// are rebuilt when repair is set; any other inconsistency is reported as the
+// result's Divergence.
+func VerifyStore(st IStore, merklePath string, repair bool) (*VerifyResult, error) {
+	mem := new(merkle.MemHashStore)
+	v := &storeVerifier{
+		st:         st,
+		merklePath: merklePath,
+		repair:     repair,
+		result:     new(VerifyResult),
+		coins:      make(map[Uint256]*coinRecord),
+		tree:       merkle.NewTree(0, nil, mem),
+		mem:        mem,
+	}
+	steps := []func() (*Divergence, error){
+		v.walkHeaders,
+		v.verifyBlocks,
+		v.verifyHeaderHashList,
+		v.verifyBlockMerkleTree,
+		v.verifyMerkleHashStore,
+		v.verifyCoins,
+		v.verifyStateRoot,
+	}
+	for _, step := range steps {
+		d, err := step()
+		if err != nil {
+			return nil, err
+		}
+		if d != nil {
+			v.result.Divergence = d
+			break
+		}
+	}
+	return v.result, nil
+}
+
+func isNotFound(err error) bool {
+	return err != nil && strings.EqualFold(err.Error(), ErrDBNotFound)
+}
+
+// walkHeaders follows PrevBlockHash from the current block back to genesis so
+// that the remaining checks do not depend on any derived index.
+func (v *storeVerifier) walkHeaders() (*Divergence, error) {
+	data, err := v.st.Get([]byte{byte(SYS_CurrentBlock)})
+	if err != nil {
+		return nil, fmt.Errorf("read current block: %v", err)
+	}
+	r := bytes.NewReader(data)
+	var current Uint256
+	if err := current.Deserialize(r); err != nil {
+		return nil, err
+	}
+	height, err := serialization.ReadUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	v.result.CurrentHash = current
+	v.result.CurrentHeight = height
+
+	v.hashes = make([]Uint256, height+1)
+	hash := current
+	for h := int64(height); h >= 0; h-- {
+		header, _, err := v.readTrimmedBlock(hash)
+		if isNotFound(err) {
+			return &Divergence{uint32(h), CheckHeader, fmt.Sprintf("header %x not found", hash.ToArray())}, nil
+		} else if err != nil {
+			return &Divergence{uint32(h), CheckHeader, err.Error()}, nil
+		}
+		if header.Height != uint32(h) {
+			return &Divergence{uint32(h), CheckHeaderLink, fmt.Sprintf("header %x has height %d", hash.ToArray(), header.Height)}, nil
+		}
+		if header.Hash() != hash {
+			return &Divergence{uint32(h), CheckHeader, fmt.Sprintf("header stored under %x hashes to %x", hash.ToArray(), header.Hash())}, nil
+		}
+		v.hashes[h] = hash
+		hash = header.PrevBlockHash
+	}
+	if hash != (Uint256{}) {
+		return &Divergence{0, CheckHeaderLink, fmt.Sprintf("genesis header links to %x", hash.ToArray())}, nil
+	}
+	return nil, nil
+}
+
+func (v *storeVerifier) verifyBlocks() (*Divergence, error) {
+	fixed := false
+	if v.repair {
+		v.st.NewBatch()
+	}
+	for i, hash := range v.hashes {
+		height := uint32(i)
+		index, err := v.getBlockIndex(height)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		if err != nil || index != hash {
+			if !v.repair {
+				return &Divergence{height, CheckBlockIndex, fmt.Sprintf("index points to %x, header chain has %x", index.ToArray(), hash.ToArray())}, nil
+			}
+			key := bytes.NewBuffer([]byte{byte(DATA_Block)})
+			serialization.WriteUint32(key, height)
+			v.st.BatchPut(key.Bytes(), hash.ToArray())
+			fixed = true
+		}
+
+		header, txHashes, err := v.readTrimmedBlock(hash)
+		if err != nil {
+			return nil, err
+		}
+		for _, txHash := range txHashes {
+			if d, err := v.verifyTransaction(height, txHash); d != nil || err != nil {
+				return d, err
+			}
+		}
+		v.result.Transactions += len(txHashes)
+
+		txRoot, err := crypto.ComputeRoot(txHashes)
+		if err != nil {
+			return &Divergence{height, CheckTransactionsRoot, err.Error()}, nil
+		}
+		if txRoot != header.TransactionsRoot {
+			return &Divergence{height, CheckTransactionsRoot, fmt.Sprintf("header has %x, transactions give %x", header.TransactionsRoot.ToArray(), txRoot.ToArray())}, nil
+		}
+		blockRoot := v.tree.GetRootWithNewLeaf(txRoot)
+		if blockRoot != header.BlockRoot {
+			return &Divergence{height, CheckBlockRoot, fmt.Sprintf("header has %x, merkle tree gives %x", header.BlockRoot.ToArray(), blockRoot.ToArray())}, nil
+		}
+		v.tree.AppendHash(txRoot)
+		v.result.Blocks++
+	}
+	if fixed {
+		if err := v.st.BatchCommit(); err != nil {
+			return nil, err
+		}
+		v.result.Repaired = append(v.result.Repaired, CheckBlockIndex)
+	}
+	return nil, nil
+}
+
+func (v *storeVerifier) verifyTransaction(height uint32, txHash Uint256) (*Divergence, error) {
+	data, err := v.st.Get(append([]byte{byte(DATA_Transaction)}, txHash.ToArray()...))
+	if isNotFound(err) {
+		return &Divergence{height, CheckTransaction, fmt.Sprintf("transaction %x not found", txHash.ToArray())}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	r := bytes.NewReader(data)
+	txHeight, err := serialization.ReadUint32(r)
+	if err != nil {
+		return &Divergence{height, CheckTransaction, err.Error()}, nil
+	}
+	t := new(tx.Transaction)
+	if err := t.Deserialize(r); err != nil {
+		return &Divergence{height, CheckTransaction, fmt.Sprintf("transaction %x: %v", txHash.ToArray(), err)}, nil
+	}
+	if t.Hash() != txHash {
+		return &Divergence{height, CheckTransaction, fmt.Sprintf("transaction stored under %x hashes to %x", txHash.ToArray(), t.Hash())}, nil
+	}
+	if txHeight != height {
+		return &Divergence{height, CheckTransaction, fmt.Sprintf("transaction %x recorded at height %d", txHash.ToArray(), txHeight)}, nil
+	}
+
+	if len(t.Outputs) > 0 {
+		v.coins[txHash] = &coinRecord{height: height, items: repeat(len(t.Outputs))}
+	}
+	for _, input := range t.UTXOInputs {
+		coin, ok := v.coins[input.ReferTxID]
+		if !ok || int(input.ReferTxOutputIndex) >= len(coin.items) {
+			return &Divergence{height, CheckCoin, fmt.Sprintf("transaction %x spends unknown output %x:%d", txHash.ToArray(), input.ReferTxID.ToArray(), input.ReferTxOutputIndex)}, nil
+		}
+		coin.items[input.ReferTxOutputIndex] = states.Spent
+	}
+	return nil, nil
+}
+
+func (v *storeVerifier) verifyHeaderHashList() (*Divergence, error) {
+	height := v.result.CurrentHeight
+	stored := make(map[uint32][]byte)
+	iter := v.st.NewIterator([]byte{byte(IX_HeaderHashList)})
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != 5 {
+			continue
+		}
+		stored[binary.LittleEndian.Uint32(key[1:])] = append([]byte{}, iter.Value()...)
+	}
+	iter.Release()
+
+	expected := make(map[uint32][]byte)
+	for start := uint32(0); height-start >= HeaderHashListCount; start += HeaderHashListCount {
+		w := new(bytes.Buffer)
+		serialization.WriteVarUint(w, uint64(HeaderHashListCount))
+		for i := uint32(0); i < HeaderHashListCount; i++ {
+			w.Write(v.hashes[start+i].ToArray())
+		}
+		expected[start] = w.Bytes()
+	}
+
+	var diverged *Divergence
+	for start, value := range stored {
+		if !bytes.Equal(expected[start], value) && (diverged == nil || start < diverged.Height) {
+			diverged = &Divergence{start, CheckHeaderHashList, fmt.Sprintf("list starting at %d does not match the header chain", start)}
+		}
+	}
+	for start := range expected {
+		if _, ok := stored[start]; !ok && (diverged == nil || start < diverged.Height) {
+			diverged = &Divergence{start, CheckHeaderHashList, fmt.Sprintf("list starting at %d is missing", start)}
+		}
+	}
+	if diverged == nil {
+		return nil, nil
+	}
+	if !v.repair {
+		return diverged, nil
+	}
+
+	v.st.NewBatch()
+	for start := range stored {
+		key := bytes.NewBuffer([]byte{byte(IX_HeaderHashList)})
+		serialization.WriteUint32(key, start)
+		v.st.BatchDelete(key.Bytes())
+	}
+	for start, value := range expected {
+		key := bytes.NewBuffer([]byte{byte(IX_HeaderHashList)})
+		serialization.WriteUint32(key, start)
+		v.st.BatchPut(key.Bytes(), value)
+	}
+	if err := v.st.BatchCommit(); err != nil {
+		return nil, err
+	}
+	v.result.Repaired = append(v.result.Repaired, CheckHeaderHashList)
+	return nil, nil
+}
+
+func (v *storeVerifier) verifyBlockMerkleTree() (*Divergence, error) {
+	hashes := v.tree.Hashes()
+	expected := make([]byte, 4, 4+len(hashes)*UINT256SIZE)
+	binary.BigEndian.PutUint32(expected[0:], v.tree.TreeSize())
+	for _, h := range hashes {
+		expected = append(expected, h[:]...)
+	}
+
+	buf, err := v.st.Get([]byte{byte(SYS_BlockMerkleTree)})
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	if bytes.Equal(buf, expected) {
+		return nil, nil
+	}
+	if !v.repair {
+		detail := "stored tree does not match recomputed tree"
+		if len(buf) >= 4 {
+			detail = fmt.Sprintf("stored tree size %d, recomputed tree size %d", binary.BigEndian.Uint32(buf[0:4]), v.tree.TreeSize())
+		}
+		return &Divergence{v.result.CurrentHeight, CheckBlockMerkleTree, detail}, nil
+	}
+	if err := v.st.Put([]byte{byte(SYS_BlockMerkleTree)}, expected); err != nil {
+		return nil, err
+	}
+	v.result.Repaired = append(v.result.Repaired, CheckBlockMerkleTree)
+	return nil, nil
+}
+
+func (v *storeVerifier) verifyMerkleHashStore() (*Divergence, error) {
+	count := v.mem.Len()
+	diverged := ""
+	if _, err := os.Stat(v.merklePath); err != nil {
+		diverged = err.Error()
+	} else {
+		fs, err := merkle.NewFileHashStore(v.merklePath, v.tree.TreeSize())
+		if err != nil {
+			diverged = err.Error()
+		} else {
+			for i := uint32(0); i < count; i++ {
+				stored, err := fs.GetHash(i)
+				if err != nil {
+					fs.Close()
+					return nil, err
+				}
+				expected, _ := v.mem.GetHash(i)
+				if stored != expected {
+					diverged = fmt.Sprintf("hash %d is %x, expected %x", i, stored.ToArray(), expected.ToArray())
+					break
+				}
+			}
+			fs.Close()
+		}
+	}
+	if diverged == "" {
+		return nil, nil
+	}
+	if !v.repair {
+		return &Divergence{v.result.CurrentHeight, CheckMerkleHashStore, diverged}, nil
+	}
+
+	if err := os.Remove(v.merklePath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	fs, err := merkle.NewFileHashStore(v.merklePath, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Close()
+	hashes := make([]Uint256, count)
+	for i := uint32(0); i < count; i++ {
+		hashes[i], _ = v.mem.GetHash(i)
+	}
+	if err := fs.Append(hashes); err != nil {
+		return nil, err
+	}
+	if err := fs.Flush(); err != nil {
+		return nil, err
+	}
+	v.result.Repaired = append(v.result.Repaired, CheckMerkleHashStore)
+	return nil, nil
+}
+
+func (v *storeVerifier) verifyCoins() (*Divergence, error) {
+	var diverged *Divergence
+	report := func(d *Divergence) {
+		if diverged == nil || d.Height < diverged.Height {
+			diverged = d
+		}
+	}
+
+	seen := make(map[Uint256]bool)
+	iter := v.st.NewIterator([]byte{byte(ST_Coin)})
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != 1+UINT256SIZE {
+			continue
+		}
+		txid, _ := Uint256ParseFromBytes(key[1:])
+		seen[txid] = true
+		coin, ok := v.coins[txid]
+		if !ok {
+			report(&Divergence{v.result.CurrentHeight, CheckCoin, fmt.Sprintf("coin state %x has no stored transaction", txid.ToArray())})
+			continue
+		}
+		state, err := getStateObject(ST_Coin, iter.Value())
+		if err != nil {
+			report(&Divergence{coin.height, CheckCoin, fmt.Sprintf("coin state %x: %v", txid.ToArray(), err)})
+			continue
+		}
+		items := state.(*states.UnspentCoinState).Item
+		if len(items) != len(coin.items) {
+			report(&Divergence{coin.height, CheckCoin, fmt.Sprintf("coin state %x has %d outputs, transaction has %d", txid.ToArray(), len(items), len(coin.items))})
+			continue
+		}
+		for i := range items {
+			if items[i] != coin.items[i] {
+				report(&Divergence{coin.height, CheckCoin, fmt.Sprintf("coin state %x output %d is %d, expected %d", txid.ToArray(), i, items[i], coin.items[i])})
+				break
+			}
+		}
+	}
+	iter.Release()
+
+	for txid, coin := range v.coins {
+		if !seen[txid] {
+			report(&Divergence{coin.height, CheckCoin, fmt.Sprintf("coin state %x is missing", txid.ToArray())})
+		}
+	}
+	return diverged, nil
+}
+
+// triePrefixes are the state entries committed to the state trie, see StateStore.CommitTo.
+var triePrefixes = []DataEntryPrefix{ST_Account, ST_Storage, ST_Identity, ST_Claim}
+
+func (v *storeVerifier) verifyStateRoot() (*Divergence, error) {
+	height := v.result.CurrentHeight
+	data, err := v.st.Get(append([]byte{byte(Sys_CurrentStateRoot)}, CurrentStateRoot...))
+	if isNotFound(err) {
+		return &Divergence{height, CheckStateRoot, "current state root not found"}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	root, err := Uint256ParseFromBytes(data)
+	if err != nil {
+		return &Divergence{height, CheckStateRoot, err.Error()}, nil
+	}
+	header, _, err := v.readTrimmedBlock(v.result.CurrentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves, err := v.walkStateTrie(root)
+	if err != nil {
+		return &Divergence{height, CheckStateRoot, fmt.Sprintf("state trie %x: %v", root.ToArray(), err)}, nil
+	}
+	// headers without a state root leave a trie of the changes of the last
+	// block only, the state entries are all in the trie otherwise
+	if header.StateRoot == (Uint256{}) {
+		return nil, nil
+	}
+	// the header commits to the state its block was applied to, which the
+	// stored root was built on
+	if header.StateRoot != root {
+		if _, err := v.walkStateTrie(header.StateRoot); err != nil {
+			return &Divergence{height, CheckStateRoot, fmt.Sprintf("state trie %x of the current header: %v", header.StateRoot.ToArray(), err)}, nil
+		}
+	}
+	for _, prefix := range triePrefixes {
+		iter := v.st.NewIterator([]byte{byte(prefix)})
+		for iter.Next() {
+			if !leaves[string(iter.Key())] {
+				key := ToHexString(iter.Key())
+				iter.Release()
+				return &Divergence{height, CheckStateRoot, fmt.Sprintf("state entry %s is not in the state trie", key)}, nil
+			}
+		}
+		iter.Release()
+	}
+	return nil, nil
+}
+
+// walkStateTrie resolves every node of the state trie of root and checks each
+// leaf against the state entry it was made of. It returns the keys of the
+// state entries found in the trie.
+func (v *storeVerifier) walkStateTrie(root Uint256) (map[string]bool, error) {
+	tr, err := trie.NewSecure(root, v.st)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool)
+	err = tr.Walk(func(hashedKey, value []byte) error {
+		key, err := tr.GetKey(hashedKey)
+		if err != nil || len(key) == 0 {
+			return fmt.Errorf("leaf %x has no key", hashedKey)
+		}
+		data, err := v.st.Get(key)
+		if isNotFound(err) {
+			return fmt.Errorf("leaf of state entry %x has no state entry", key)
+		} else if err != nil {
+			return err
+		}
+		expected := trieLeafValue(DataEntryPrefix(key[0]), data)
+		if !bytes.Equal(value, expected.ToArray()) {
+			return fmt.Errorf("leaf of state entry %x is %x, expected %x", key, value, expected.ToArray())
+		}
+		keys[string(key)] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (v *storeVerifier) getBlockIndex(height uint32) (Uint256, error) {
+	key := bytes.NewBuffer([]byte{byte(DATA_Block)})
+	serialization.WriteUint32(key, height)
+	data, err := v.st.Get(key.Bytes())
+	if err != nil {
+		return Uint256{}, err
+	}
+	return Uint256ParseFromBytes(data)
+}
+
+// readTrimmedBlock reads the header and transaction hashes stored under DATA_Header
+// without recomputing the transactions root, unlike Block.FromTrimmedData.
+func (v *storeVerifier) readTrimmedBlock(hash Uint256) (*Header, []Uint256, error) {
+	data, err := v.st.Get(append([]byte{byte(DATA_Header)}, hash.ToArray()...))
+	if err != nil {
+		return nil, nil, err
+	}
+	r := bytes.NewReader(data)
+	// first 8 bytes is sys_fee
+	if _, err := serialization.ReadUint64(r); err != nil {
+		return nil, nil, err
+	}
+	return parseTrimmedBlock(r)
+}
+
+func parseTrimmedBlock(r *bytes.Reader) (*Header, []Uint256, error) {
+	header := new(Header)
+	if err := header.Deserialize(r); err != nil {
+		return nil, nil, err
+	}
+	count, err := serialization.ReadUint32(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if int64(count)*int64(UINT256SIZE) > int64(r.Len()) {
+		return nil, nil, errors.New("transaction hash list is truncated")
+	}
+	txHashes := make([]Uint256, count)
+	for i := range txHashes {
+		if err := txHashes[i].Deserialize(r); err != nil {
+			return nil, nil, err
+		}
+	}
+	return header, txHashes, nil
+}
@@ -0,0 +1,318 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ChainStore
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/common/log"
+	"github.com/Ontology/common/serialization"
+	. "github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/states"
+	. "github.com/Ontology/core/store"
+	"github.com/Ontology/core/store/LevelDBStore"
+	tx "github.com/Ontology/core/transaction"
+	"github.com/Ontology/core/transaction/payload"
+	"github.com/Ontology/core/transaction/utxo"
+	"github.com/Ontology/crypto"
+	"github.com/Ontology/merkle"
+)
+
+func init() {
+	log.Init()
+}
+
+type verifierChain struct {
+	store      *ChainStore
+	merklePath string
+	blocks     []*Block
+	// coinTx has two outputs, the first one is spent at height 2
+	coinTx Uint256
+}
+
+func newVerifierTx(nonce uint64, inputs []*utxo.UTXOTxInput, outputs int) *tx.Transaction {
+	t := &tx.Transaction{
+		TxType:         tx.BookKeeping,
+		PayloadVersion: payload.BookKeepingPayloadVersion,
+		Payload:        &payload.BookKeeping{Nonce: nonce},
+		Attributes:     []*tx.TxAttribute{},
+		UTXOInputs:     inputs,
+		BalanceInputs:  []*tx.BalanceTxInput{},
+		Outputs:        []*utxo.TxOutput{},
+	}
+	for i := 0; i < outputs; i++ {
+		t.Outputs = append(t.Outputs, &utxo.TxOutput{AssetID: Uint256{9}, Value: Fixed64(i + 1), ProgramHash: Uint160{1}})
+	}
+	return t
+}
+
+// newVerifierChain persists a genesis block and three blocks whose headers
+// commit to the state root they are applied to.
+func newVerifierChain(t *testing.T) *verifierChain {
+	crypto.SetAlg("P256R1")
+	_, pubKey, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := LevelDBStore.NewMemLevelDBStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &verifierChain{store: newChainStore(db), merklePath: filepath.Join(t.TempDir(), "merkle_tree.db")}
+	t.Cleanup(c.store.Close)
+
+	// InitLedgerStoreWithGenesisBlock without the hash store at MerkleTreeStorePath
+	bookKeeper := &states.BookKeeperState{CurrBookKeeper: []*crypto.PubKey{&pubKey}, NextBookKeeper: []*crypto.PubKey{&pubKey}}
+	w := new(bytes.Buffer)
+	if err := bookKeeper.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	db.Put(append([]byte{byte(ST_BookKeeper)}, BookerKeeper...), w.Bytes())
+	c.store.merkleHashStore, err = merkle.NewFileHashStore(c.merklePath, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.store.merkleHashStore.Close)
+	c.store.merkleTree = merkle.NewTree(0, nil, c.store.merkleHashStore)
+
+	genesis, err := GenesisBlockInit([]*crypto.PubKey{&pubKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis.RebuildMerkleRoot()
+	genesis.Header.BlockRoot = genesis.Header.TransactionsRoot
+	if err := c.store.persist(genesis); err != nil {
+		t.Fatal(err)
+	}
+	c.blocks = append(c.blocks, genesis)
+
+	coin := newVerifierTx(1, []*utxo.UTXOTxInput{}, 2)
+	c.coinTx = coin.Hash()
+	spend := newVerifierTx(2, []*utxo.UTXOTxInput{{ReferTxID: c.coinTx, ReferTxOutputIndex: 0}}, 1)
+	for i, txs := range [][]*tx.Transaction{{coin}, {spend, newVerifierTx(3, []*utxo.UTXOTxInput{}, 1)}, {newVerifierTx(4, []*utxo.UTXOTxInput{}, 0)}} {
+		prev := c.blocks[len(c.blocks)-1]
+		b := &Block{
+			Header: &Header{
+				Version:        BlockVersion,
+				PrevBlockHash:  prev.Hash(),
+				Timestamp:      prev.Header.Timestamp + 1,
+				Height:         uint32(i + 1),
+				ConsensusData:  uint64(i),
+				NextBookKeeper: prev.Header.NextBookKeeper,
+				StateRoot:      c.store.GetCurrentStateRoot(),
+				Program:        genesis.Header.Program,
+			},
+			Transactions: txs,
+		}
+		if err := b.RebuildMerkleRoot(); err != nil {
+			t.Fatal(err)
+		}
+		b.Header.BlockRoot = c.store.GetBlockRootWithNewTxRoot(b.Header.TransactionsRoot)
+		if err := c.store.persist(b); err != nil {
+			t.Fatal(err)
+		}
+		c.blocks = append(c.blocks, b)
+	}
+	return c
+}
+
+func (c *verifierChain) headerKey(height int) []byte {
+	hash := c.blocks[height].Hash()
+	return append([]byte{byte(DATA_Header)}, hash.ToArray()...)
+}
+
+func (c *verifierChain) blockIndexKey(height uint32) []byte {
+	key := bytes.NewBuffer([]byte{byte(DATA_Block)})
+	serialization.WriteUint32(key, height)
+	return key.Bytes()
+}
+
+func TestVerifyStore(t *testing.T) {
+	c := newVerifierChain(t)
+	result, err := VerifyStore(c.store.st, c.merklePath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Divergence != nil {
+		t.Fatalf("unexpected divergence: %v", result.Divergence)
+	}
+	if result.CurrentHeight != 3 || result.CurrentHash != c.blocks[3].Hash() || result.Blocks != 4 || result.Transactions != 5 {
+		t.Errorf("got height %d, hash %x, %d blocks, %d transactions", result.CurrentHeight, result.CurrentHash, result.Blocks, result.Transactions)
+	}
+	if c.blocks[3].Header.StateRoot == (Uint256{}) {
+		t.Error("the state entries are not checked against the state trie")
+	}
+}
+
+func TestVerifyStoreDivergence(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, c *verifierChain)
+		height  uint32
+		check   string
+	}{
+		{
+			name: "header linkage",
+			corrupt: func(t *testing.T, c *verifierChain) {
+				// block 3 links to a hash whose entry holds the header of block 2
+				data, err := c.store.st.Get(c.headerKey(2))
+				if err != nil {
+					t.Fatal(err)
+				}
+				c.store.st.Put(c.headerKey(1), data)
+			},
+			height: 1,
+			check:  CheckHeaderLink,
+		},
+		{
+			name: "transactions root",
+			corrupt: func(t *testing.T, c *verifierChain) {
+				// drop the last transaction hash of block 2
+				w := new(bytes.Buffer)
+				serialization.WriteUint64(w, 0)
+				b := c.blocks[2]
+				b.Header.Serialize(w)
+				serialization.WriteUint32(w, uint32(len(b.Transactions)-1))
+				for _, txn := range b.Transactions[:len(b.Transactions)-1] {
+					hash := txn.Hash()
+					hash.Serialize(w)
+				}
+				c.store.st.Put(c.headerKey(2), w.Bytes())
+			},
+			height: 2,
+			check:  CheckTransactionsRoot,
+		},
+		{
+			name: "stored state root",
+			corrupt: func(t *testing.T, c *verifierChain) {
+				c.store.st.Put(append([]byte{byte(Sys_CurrentStateRoot)}, CurrentStateRoot...), bytes.Repeat([]byte{7}, UINT256SIZE))
+			},
+			height: 3,
+			check:  CheckStateRoot,
+		},
+		{
+			name: "unspent coin",
+			corrupt: func(t *testing.T, c *verifierChain) {
+				w := new(bytes.Buffer)
+				(&states.UnspentCoinState{Item: repeat(2)}).Serialize(w)
+				c.store.st.Put(append([]byte{byte(ST_Coin)}, c.coinTx.ToArray()...), w.Bytes())
+			},
+			height: 1,
+			check:  CheckCoin,
+		},
+		{
+			name: "first divergence",
+			corrupt: func(t *testing.T, c *verifierChain) {
+				c.store.st.Put(append([]byte{byte(Sys_CurrentStateRoot)}, CurrentStateRoot...), bytes.Repeat([]byte{7}, UINT256SIZE))
+				c.store.st.Delete(append([]byte{byte(ST_Coin)}, c.coinTx.ToArray()...))
+			},
+			height: 1,
+			check:  CheckCoin,
+		},
+		{
+			name: "block index",
+			corrupt: func(t *testing.T, c *verifierChain) {
+				c.store.st.Delete(c.blockIndexKey(1))
+			},
+			height: 1,
+			check:  CheckBlockIndex,
+		},
+	}
+	for _, test := range tests {
+		c := newVerifierChain(t)
+		test.corrupt(t, c)
+		result, err := VerifyStore(c.store.st, c.merklePath, false)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		d := result.Divergence
+		if d == nil {
+			t.Errorf("%s: no divergence", test.name)
+		} else if d.Height != test.height || d.Check != test.check {
+			t.Errorf("%s: got %v, want %s at height %d", test.name, d, test.check, test.height)
+		}
+	}
+}
+
+func TestVerifyStoreRepair(t *testing.T) {
+	c := newVerifierChain(t)
+	st := c.store.st
+	st.Delete(c.blockIndexKey(1))
+	st.Put(c.blockIndexKey(2), bytes.Repeat([]byte{7}, UINT256SIZE))
+	// lists are only stored for full runs of HeaderHashListCount headers
+	list := bytes.NewBuffer([]byte{byte(IX_HeaderHashList)})
+	serialization.WriteUint32(list, 0)
+	st.Put(list.Bytes(), []byte{1})
+	tree, err := st.Get([]byte{byte(SYS_BlockMerkleTree)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.Delete([]byte{byte(SYS_BlockMerkleTree)})
+	if err := os.Remove(c.merklePath); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := VerifyStore(st, c.merklePath, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Divergence != nil {
+		t.Fatalf("unexpected divergence: %v", result.Divergence)
+	}
+	want := []string{CheckBlockIndex, CheckHeaderHashList, CheckBlockMerkleTree, CheckMerkleHashStore}
+	if len(result.Repaired) != len(want) {
+		t.Fatalf("repaired %v, want %v", result.Repaired, want)
+	}
+	for i := range want {
+		if result.Repaired[i] != want[i] {
+			t.Errorf("repaired %v, want %v", result.Repaired, want)
+		}
+	}
+
+	for height := uint32(1); height <= 2; height++ {
+		data, err := st.Get(c.blockIndexKey(height))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash := c.blocks[height].Hash()
+		if !bytes.Equal(data, hash.ToArray()) {
+			t.Errorf("block index %d is %x", height, data)
+		}
+	}
+	if _, err := st.Get(list.Bytes()); err == nil {
+		t.Error("stray header hash list was not removed")
+	}
+	data, err := st.Get([]byte{byte(SYS_BlockMerkleTree)})
+	if err != nil || !bytes.Equal(data, tree) {
+		t.Errorf("block merkle tree is %x, want %x", data, tree)
+	}
+
+	result, err = VerifyStore(st, c.merklePath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Divergence != nil || len(result.Repaired) != 0 {
+		t.Errorf("repaired store: divergence %v, repaired %v", result.Divergence, result.Repaired)
+	}
+}
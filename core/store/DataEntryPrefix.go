@@ -48,4 +48,15 @@ const (
 	SYS_Version
 	Sys_CurrentStateRoot
 	SYS_BlockMerkleTree
+
+	// EVENT LOG
+	DATA_Notify
+	IX_NotifyContract
+	IX_NotifyEvent
+
+	// CLAIM
+	ST_Claim
+
+	// FREEZE
+	ST_FrozenAccount
 )
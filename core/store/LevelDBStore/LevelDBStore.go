@@ -25,6 +25,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
@@ -61,6 +62,18 @@ func NewLevelDBStore(file string) (*LevelDBStore, error) {
 	}, nil
 }
 
+// NewMemLevelDBStore returns a store kept in memory, dropped on Close.
+func NewMemLevelDBStore() (*LevelDBStore, error) {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LevelDBStore{
+		db:    db,
+		batch: nil,
+	}, nil
+}
+
 func (self *LevelDBStore) Put(key []byte, value []byte) error {
 	return self.db.Put(key, value, nil)
 }
@@ -166,16 +166,97 @@ func NewDataFileTransaction(path string, fileName string, note string, issuer *c
 	}, nil
 }
 
-func NewDeployTransaction(fc *code.FunctionCode, programHash common.Uint160, name, codeversion, author, email, desp string, vmType types.VmType, needStorage bool) (*Transaction, error) {
+func NewIdentityTransaction(identity *payload.Identity) (*Transaction, error) {
+	return &Transaction{
+		TxType:        Identity,
+		Payload:       identity,
+		Attributes:    []*TxAttribute{},
+		UTXOInputs:    []*UTXOTxInput{},
+		BalanceInputs: []*BalanceTxInput{},
+		Programs:      []*program.Program{},
+	}, nil
+}
+
+// NewAttestationTransaction wraps an attestation signed by the issuer, the
+// transaction itself needs no witness.
+func NewAttestationTransaction(attestation *payload.Attestation) (*Transaction, error) {
+	return &Transaction{
+		TxType:        Attestation,
+		Payload:       attestation,
+		Attributes:    []*TxAttribute{},
+		UTXOInputs:    []*UTXOTxInput{},
+		BalanceInputs: []*BalanceTxInput{},
+		Programs:      []*program.Program{},
+	}, nil
+}
+
+// NewFreezeTransaction freezes or unfreezes an asset or an address within it,
+// the transaction is signed by the admin of the asset.
+func NewFreezeTransaction(freeze *payload.Freeze) (*Transaction, error) {
+	return &Transaction{
+		TxType:        Freeze,
+		Payload:       freeze,
+		Attributes:    []*TxAttribute{},
+		UTXOInputs:    []*UTXOTxInput{},
+		BalanceInputs: []*BalanceTxInput{},
+		Programs:      []*program.Program{},
+	}, nil
+}
+
+// NewAssetRenewTransaction renews an asset, the system fee is paid with the
+// inputs of the transaction.
+func NewAssetRenewTransaction(renew *payload.AssetRenew, inputs []*UTXOTxInput, outputs []*TxOutput) (*Transaction, error) {
+	return &Transaction{
+		TxType:        AssetRenew,
+		Payload:       renew,
+		Attributes:    []*TxAttribute{},
+		UTXOInputs:    inputs,
+		BalanceInputs: []*BalanceTxInput{},
+		Outputs:       outputs,
+		Programs:      []*program.Program{},
+	}, nil
+}
+
+// NewAssetAdminChangeTransaction replaces the admin and the issuer of an
+// asset, the transaction is signed by the owner of the asset.
+func NewAssetAdminChangeTransaction(change *payload.AssetAdminChange) (*Transaction, error) {
+	return &Transaction{
+		TxType:        AssetAdminChange,
+		Payload:       change,
+		Attributes:    []*TxAttribute{},
+		UTXOInputs:    []*UTXOTxInput{},
+		BalanceInputs: []*BalanceTxInput{},
+		Programs:      []*program.Program{},
+	}, nil
+}
+
+// NewDestroyTransaction destroys the amount of the asset spent by the inputs
+// and not paid back to the outputs.
+func NewDestroyTransaction(destroy *payload.Destroy, inputs []*UTXOTxInput, outputs []*TxOutput) (*Transaction, error) {
+	return &Transaction{
+		TxType:        Destroy,
+		Payload:       destroy,
+		Attributes:    []*TxAttribute{},
+		UTXOInputs:    inputs,
+		BalanceInputs: []*BalanceTxInput{},
+		Outputs:       outputs,
+		Programs:      []*program.Program{},
+	}, nil
+}
+
+func NewDeployTransaction(fc *code.FunctionCode, programHash common.Uint160, name, codeversion, author, email, desp string, vmType types.VmType, property types.ContractProperty) (*Transaction, error) {
 	//TODO: check arguments
 	DeployCodePayload := &payload.DeployCode{
-		Code:        fc,
-		NeedStorage: needStorage,
-		Name:        name,
-		CodeVersion: codeversion,
-		Author:      author,
-		Email:       email,
-		Description: desp,
+		Code:            fc,
+		VmType:          vmType,
+		NeedStorage:     property.Has(types.HasStorage),
+		DynamicInvoke:   property.Has(types.HasDynamicInvoke),
+		ReentrancyGuard: property.Has(types.HasReentrancyGuard),
+		Name:            name,
+		CodeVersion:     codeversion,
+		Author:          author,
+		Email:           email,
+		Description:     desp,
 	}
 
 	return &Transaction{
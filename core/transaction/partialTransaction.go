@@ -0,0 +1,270 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package transaction
+
+import (
+	"errors"
+	"io"
+	"sort"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	"github.com/Ontology/core/contract"
+	sig "github.com/Ontology/core/signature"
+	"github.com/Ontology/crypto"
+	. "github.com/Ontology/errors"
+)
+
+// PartialTransaction is a transaction spending from multi-signature contracts
+// with the signatures collected so far. It is passed between the co-signers,
+// each adding a signature, until the programs are complete.
+type PartialTransaction struct {
+	Tx      *Transaction
+	Context *contract.ContractContext
+}
+
+// NewPartialTransaction creates the partially signed tx. The given
+// multi-signature contracts must be exactly the ones whose program hashes
+// the transaction is verified with, the referenced outputs are looked up
+// in TxStore.
+func NewPartialTransaction(tx *Transaction, contracts []*contract.Contract) (*PartialTransaction, error) {
+	partial, err := newPartialTransaction(tx, contracts)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := tx.GetProgramHashes()
+	if err != nil {
+		return nil, NewDetailErr(err, ErrNoCode, "[PartialTransaction], GetProgramHashes failed.")
+	}
+	if len(hashes) != len(partial.Context.ProgramHashes) {
+		return nil, errors.New("[PartialTransaction], Contracts don't match the program hashes of the transaction.")
+	}
+	for i, hash := range hashes {
+		if partial.Context.ProgramHashes[i] != hash {
+			return nil, errors.New("[PartialTransaction], Contracts don't match the program hashes of the transaction.")
+		}
+	}
+	return partial, nil
+}
+
+func newPartialTransaction(tx *Transaction, contracts []*contract.Contract) (*PartialTransaction, error) {
+	hashes := make([]Uint160, 0, len(contracts))
+	codes := make(map[Uint160][]byte)
+	for _, c := range contracts {
+		if c.GetType() != contract.MultiSigContract {
+			return nil, errors.New("[PartialTransaction], Not a multi-signature contract.")
+		}
+		if _, ok := codes[c.ProgramHash]; !ok {
+			hashes = append(hashes, c.ProgramHash)
+			codes[c.ProgramHash] = c.Code
+		}
+	}
+	sort.Sort(byProgramHashes(hashes))
+	ctx := contract.NewContractContextWithProgramHashes(tx, hashes)
+	for i, hash := range hashes {
+		ctx.Codes[i] = codes[hash]
+	}
+	return &PartialTransaction{Tx: tx, Context: ctx}, nil
+}
+
+// Sign adds the signature of signer to every contract it is a member of.
+func (p *PartialTransaction) Sign(signer sig.Signer) error {
+	signed := false
+	for i := range p.Context.ProgramHashes {
+		c, err := contract.ParseMultiSigContract(p.Context.Codes[i])
+		if err != nil {
+			return err
+		}
+		keys, err := p.Context.ParseContractPubKeys(c)
+		if err != nil {
+			return err
+		}
+		pk, err := signer.PubKey().EncodePoint(true)
+		if err != nil {
+			return err
+		}
+		if _, ok := keys[ToHexString(pk)]; !ok {
+			continue
+		}
+		signature, err := sig.SignBySigner(p.Tx, signer)
+		if err != nil {
+			return err
+		}
+		if err := p.Context.AddSignature(c, signer.PubKey(), signature); err != nil {
+			return err
+		}
+		signed = true
+	}
+	if !signed {
+		return errors.New("[PartialTransaction], Signer is not a member of the contracts.")
+	}
+	return nil
+}
+
+// Combine adds the signatures collected by other for the same transaction.
+func (p *PartialTransaction) Combine(other *PartialTransaction) error {
+	if p.Tx.Hash() != other.Tx.Hash() {
+		return errors.New("[PartialTransaction], Transactions are different.")
+	}
+	if len(p.Context.ProgramHashes) != len(other.Context.ProgramHashes) {
+		return errors.New("[PartialTransaction], Contracts are different.")
+	}
+	for i, hash := range p.Context.ProgramHashes {
+		if other.Context.ProgramHashes[i] != hash {
+			return errors.New("[PartialTransaction], Contracts are different.")
+		}
+		for _, pubkeyPara := range other.Context.MultiPubkeyPara[i] {
+			pk, err := HexToBytes(pubkeyPara.PubKey)
+			if err != nil {
+				return err
+			}
+			signature, err := HexToBytes(pubkeyPara.Parameter)
+			if err != nil {
+				return err
+			}
+			if err := p.addSignature(i, pk, signature); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addSignature verifies and adds the signature of the encoded public key to
+// the contract at index.
+func (p *PartialTransaction) addSignature(index int, pk, signature []byte) error {
+	c, err := contract.ParseMultiSigContract(p.Context.Codes[index])
+	if err != nil {
+		return err
+	}
+	pubkey, err := crypto.DecodePoint(pk)
+	if err != nil {
+		return err
+	}
+	if err := crypto.Verify(*pubkey, p.Tx.GetMessage(), signature); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[PartialTransaction], Invalid signature.")
+	}
+	return p.Context.AddSignature(c, pubkey, signature)
+}
+
+func (p *PartialTransaction) IsCompleted() bool {
+	return p.Context.IsCompleted()
+}
+
+// Complete sets the programs of the transaction once every contract has
+// enough signatures.
+func (p *PartialTransaction) Complete() (*Transaction, error) {
+	if !p.Context.IsCompleted() {
+		return nil, errors.New("[PartialTransaction], Not enough signatures.")
+	}
+	p.Tx.SetPrograms(p.Context.GetPrograms())
+	return p.Tx, nil
+}
+
+func (p *PartialTransaction) Serialize(w io.Writer) error {
+	if err := p.Tx.SerializeUnsigned(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[PartialTransaction], Transaction Serialize failed.")
+	}
+	if err := serialization.WriteVarUint(w, uint64(len(p.Context.ProgramHashes))); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[PartialTransaction], Contracts Serialize failed.")
+	}
+	for i := range p.Context.ProgramHashes {
+		if err := serialization.WriteVarBytes(w, p.Context.Codes[i]); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[PartialTransaction], Code Serialize failed.")
+		}
+		if err := serialization.WriteVarUint(w, uint64(len(p.Context.MultiPubkeyPara[i]))); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[PartialTransaction], Signatures Serialize failed.")
+		}
+		for _, pubkeyPara := range p.Context.MultiPubkeyPara[i] {
+			pk, err := HexToBytes(pubkeyPara.PubKey)
+			if err != nil {
+				return err
+			}
+			signature, err := HexToBytes(pubkeyPara.Parameter)
+			if err != nil {
+				return err
+			}
+			if err := serialization.WriteVarBytes(w, pk); err != nil {
+				return NewDetailErr(err, ErrNoCode, "[PartialTransaction], PubKey Serialize failed.")
+			}
+			if err := serialization.WriteVarBytes(w, signature); err != nil {
+				return NewDetailErr(err, ErrNoCode, "[PartialTransaction], Signature Serialize failed.")
+			}
+		}
+	}
+	return nil
+}
+
+func (p *PartialTransaction) Deserialize(r io.Reader) error {
+	p.Tx = new(Transaction)
+	if err := p.Tx.DeserializeUnsigned(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[PartialTransaction], Transaction Deserialize failed.")
+	}
+	n, err := serialization.ReadVarUint(r, 0)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "[PartialTransaction], Contracts Deserialize failed.")
+	}
+	contracts := make([]*contract.Contract, 0, n)
+	signatures := make([][][2][]byte, 0, n)
+	for i := uint64(0); i < n; i++ {
+		code, err := serialization.ReadVarBytes(r)
+		if err != nil {
+			return NewDetailErr(err, ErrNoCode, "[PartialTransaction], Code Deserialize failed.")
+		}
+		c, err := contract.ParseMultiSigContract(code)
+		if err != nil {
+			return err
+		}
+		contracts = append(contracts, c)
+		count, err := serialization.ReadVarUint(r, 0)
+		if err != nil {
+			return NewDetailErr(err, ErrNoCode, "[PartialTransaction], Signatures Deserialize failed.")
+		}
+		pairs := make([][2][]byte, 0, count)
+		for j := uint64(0); j < count; j++ {
+			pk, err := serialization.ReadVarBytes(r)
+			if err != nil {
+				return NewDetailErr(err, ErrNoCode, "[PartialTransaction], PubKey Deserialize failed.")
+			}
+			signature, err := serialization.ReadVarBytes(r)
+			if err != nil {
+				return NewDetailErr(err, ErrNoCode, "[PartialTransaction], Signature Deserialize failed.")
+			}
+			pairs = append(pairs, [2][]byte{pk, signature})
+		}
+		signatures = append(signatures, pairs)
+	}
+	// the co-signers may not reach a node to look up the referenced outputs,
+	// a file with the wrong contracts fails the verification of the complete tx
+	partial, err := newPartialTransaction(p.Tx, contracts)
+	if err != nil {
+		return err
+	}
+	p.Context = partial.Context
+	for i, c := range contracts {
+		index := p.Context.GetIndex(c.ProgramHash)
+		for _, pair := range signatures[i] {
+			if err := p.addSignature(index, pair[0], pair[1]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
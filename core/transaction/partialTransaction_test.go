@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package transaction_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/log"
+	"github.com/Ontology/core/contract"
+	"github.com/Ontology/core/states"
+	tx "github.com/Ontology/core/transaction"
+	"github.com/Ontology/core/transaction/payload"
+	"github.com/Ontology/core/transaction/utxo"
+	"github.com/Ontology/core/validation"
+	"github.com/Ontology/crypto"
+)
+
+func init() {
+	log.Init()
+}
+
+type txStore struct {
+	txs map[common.Uint256]*tx.Transaction
+}
+
+func (s *txStore) GetTransaction(hash common.Uint256) (*tx.Transaction, error) {
+	t, ok := s.txs[hash]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return t, nil
+}
+
+func (s *txStore) GetQuantityIssued(assetID common.Uint256) (common.Fixed64, error) {
+	return 0, nil
+}
+
+func (s *txStore) GetAsset(hash common.Uint256) (*states.AssetState, error) {
+	return nil, errors.New("not found")
+}
+
+type keySigner struct {
+	priv []byte
+	pub  *crypto.PubKey
+}
+
+func (s *keySigner) PrivKey() []byte        { return s.priv }
+func (s *keySigner) PubKey() *crypto.PubKey { return s.pub }
+
+func TestPartialTransaction(t *testing.T) {
+	crypto.SetAlg("P256R1")
+	signers := make([]*keySigner, 3)
+	pubkeys := make([]*crypto.PubKey, 3)
+	for i := range signers {
+		priv, pk, err := crypto.GenKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signers[i] = &keySigner{priv, &pk}
+		pubkeys[i] = &pk
+	}
+	multisig, err := contract.CreateMultiSigContract(common.Uint160{}, 2, pubkeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	asset := common.Uint256{1}
+	funding := common.Uint256{2}
+	tx.TxStore = &txStore{
+		txs: map[common.Uint256]*tx.Transaction{
+			funding: {Outputs: []*utxo.TxOutput{{AssetID: asset, Value: 10, ProgramHash: multisig.ProgramHash}}},
+		},
+	}
+	defer func() { tx.TxStore = nil }()
+
+	transfer := &tx.Transaction{
+		TxType:     tx.TransferAsset,
+		Payload:    &payload.TransferAsset{},
+		UTXOInputs: []*utxo.UTXOTxInput{{ReferTxID: funding}},
+		Outputs:    []*utxo.TxOutput{{AssetID: asset, Value: 10, ProgramHash: common.Uint160{1}}},
+	}
+	// a contract the transaction doesn't spend from is refused
+	other, err := contract.CreateMultiSigContract(common.Uint160{}, 1, pubkeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, contracts := range [][]*contract.Contract{{other}, {multisig, other}} {
+		if _, err := tx.NewPartialTransaction(transfer, contracts); err == nil {
+			t.Errorf("%d contracts: partial transaction created for other contracts", len(contracts))
+		}
+	}
+	// the co-signers sign their own copy, passed around serialized
+	partials := make([]*tx.PartialTransaction, 0, 2)
+	for _, signer := range []*keySigner{signers[2], signers[0]} {
+		partial, err := tx.NewPartialTransaction(transfer, []*contract.Contract{multisig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := partial.Sign(signer); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := partial.Serialize(&buf); err != nil {
+			t.Fatal(err)
+		}
+		decoded := new(tx.PartialTransaction)
+		if err := decoded.Deserialize(&buf); err != nil {
+			t.Fatal(err)
+		}
+		partials = append(partials, decoded)
+	}
+	if partials[0].IsCompleted() {
+		t.Fatal("one signature completed the transaction")
+	}
+	if err := partials[0].Combine(partials[1]); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := partials[0].Complete()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := validation.VerifySignableData(signed); !ok {
+		t.Errorf("verify: got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package payload
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/Ontology/common"
+	. "github.com/Ontology/errors"
+)
+
+const AssetAdminChangePayloadVersion byte = 0x00
+
+// AssetAdminChange replaces the admin and the issuer of an asset, it is
+// signed by the owner of the asset.
+type AssetAdminChange struct {
+	AssetID common.Uint256
+	Admin   common.Uint160
+	Issuer  common.Uint160
+}
+
+func (self *AssetAdminChange) Data(version byte) []byte {
+	var buf bytes.Buffer
+	self.Serialize(&buf, version)
+	return buf.Bytes()
+}
+
+func (self *AssetAdminChange) Serialize(w io.Writer, version byte) error {
+	if _, err := self.AssetID.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[AssetAdminChange], AssetID Serialize failed.")
+	}
+	if _, err := self.Admin.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[AssetAdminChange], Admin Serialize failed.")
+	}
+	if _, err := self.Issuer.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[AssetAdminChange], Issuer Serialize failed.")
+	}
+	return nil
+}
+
+func (self *AssetAdminChange) Deserialize(r io.Reader, version byte) error {
+	if err := self.AssetID.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[AssetAdminChange], AssetID Deserialize failed.")
+	}
+	if err := self.Admin.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[AssetAdminChange], Admin Deserialize failed.")
+	}
+	if err := self.Issuer.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[AssetAdminChange], Issuer Deserialize failed.")
+	}
+	return nil
+}
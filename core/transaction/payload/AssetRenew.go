@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package payload
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	. "github.com/Ontology/errors"
+)
+
+const AssetRenewPayloadVersion byte = 0x00
+
+// AssetRenew extends the expiration of an asset by Years, the renewal is paid
+// with the system fee, so anyone may renew an asset.
+type AssetRenew struct {
+	AssetID common.Uint256
+	Years   uint32
+}
+
+func (self *AssetRenew) Data(version byte) []byte {
+	var buf bytes.Buffer
+	self.Serialize(&buf, version)
+	return buf.Bytes()
+}
+
+func (self *AssetRenew) Serialize(w io.Writer, version byte) error {
+	if _, err := self.AssetID.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[AssetRenew], AssetID Serialize failed.")
+	}
+	if err := serialization.WriteUint32(w, self.Years); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[AssetRenew], Years Serialize failed.")
+	}
+	return nil
+}
+
+func (self *AssetRenew) Deserialize(r io.Reader, version byte) error {
+	if err := self.AssetID.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[AssetRenew], AssetID Deserialize failed.")
+	}
+	years, err := serialization.ReadUint32(r)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "[AssetRenew], Years Deserialize failed.")
+	}
+	self.Years = years
+	return nil
+}
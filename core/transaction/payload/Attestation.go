@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package payload
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	"github.com/Ontology/crypto"
+	. "github.com/Ontology/errors"
+)
+
+const AttestationPayloadVersion byte = 0x00
+
+type AttestationAction byte
+
+const (
+	AttestationAction_COMMIT AttestationAction = 0
+	AttestationAction_REVOKE AttestationAction = 1
+)
+
+// Attestation commits the hash of a verifiable claim an issuer made about a
+// subject, or revokes it. Both are ONT IDs. The payload is signed by a key of
+// the issuer DDO, so anyone may relay it.
+type Attestation struct {
+	Action    AttestationAction
+	ClaimHash common.Uint256
+	IssuerID  []byte
+	// SubjectID and Expiry are only given on commit, Expiry is the last block
+	// height the claim is valid at, 0 if it does not expire.
+	SubjectID []byte
+	Expiry    uint32
+
+	Signer    *crypto.PubKey
+	Signature []byte
+}
+
+// SignedData is the part of the payload signed by the issuer.
+func (self *Attestation) SignedData() []byte {
+	var buf bytes.Buffer
+	self.serializeUnsigned(&buf)
+	return buf.Bytes()
+}
+
+func (self *Attestation) serializeUnsigned(w io.Writer) error {
+	if err := serialization.WriteByte(w, byte(self.Action)); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Attestation], Action Serialize failed.")
+	}
+	if _, err := self.ClaimHash.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Attestation], ClaimHash Serialize failed.")
+	}
+	if err := serialization.WriteVarBytes(w, self.IssuerID); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Attestation], IssuerID Serialize failed.")
+	}
+	if self.Action == AttestationAction_COMMIT {
+		if err := serialization.WriteVarBytes(w, self.SubjectID); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Attestation], SubjectID Serialize failed.")
+		}
+		if err := serialization.WriteUint32(w, self.Expiry); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Attestation], Expiry Serialize failed.")
+		}
+	}
+	if self.Signer == nil {
+		return NewErr("[Attestation], Signer is nil.")
+	}
+	if err := self.Signer.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Attestation], Signer Serialize failed.")
+	}
+	return nil
+}
+
+func (self *Attestation) Data(version byte) []byte {
+	var buf bytes.Buffer
+	self.Serialize(&buf, version)
+	return buf.Bytes()
+}
+
+func (self *Attestation) Serialize(w io.Writer, version byte) error {
+	if err := self.serializeUnsigned(w); err != nil {
+		return err
+	}
+	if err := serialization.WriteVarBytes(w, self.Signature); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Attestation], Signature Serialize failed.")
+	}
+	return nil
+}
+
+func (self *Attestation) Deserialize(r io.Reader, version byte) error {
+	action, err := serialization.ReadByte(r)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Attestation], Action Deserialize failed.")
+	}
+	self.Action = AttestationAction(action)
+	if self.Action != AttestationAction_COMMIT && self.Action != AttestationAction_REVOKE {
+		return NewErr("[Attestation], Invalid action.")
+	}
+	if err := self.ClaimHash.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Attestation], ClaimHash Deserialize failed.")
+	}
+	if self.IssuerID, err = serialization.ReadVarBytes(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Attestation], IssuerID Deserialize failed.")
+	}
+	if self.Action == AttestationAction_COMMIT {
+		if self.SubjectID, err = serialization.ReadVarBytes(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Attestation], SubjectID Deserialize failed.")
+		}
+		if self.Expiry, err = serialization.ReadUint32(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Attestation], Expiry Deserialize failed.")
+		}
+	}
+	self.Signer = new(crypto.PubKey)
+	if err := self.Signer.DeSerialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Attestation], Signer Deserialize failed.")
+	}
+	if self.Signature, err = serialization.ReadVarBytes(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Attestation], Signature Deserialize failed.")
+	}
+	return nil
+}
@@ -29,15 +29,21 @@ import (
 
 const DeployCodePayloadVersion byte = 0x00
 
+// DeployCodeABIPayloadVersion adds the ABI manifest to the payload.
+const DeployCodeABIPayloadVersion byte = 0x01
+
 type DeployCode struct {
-	Code        *FunctionCode
-	VmType      types.VmType
-	NeedStorage bool
-	Name        string
-	CodeVersion string
-	Author      string
-	Email       string
-	Description string
+	Code            *FunctionCode
+	VmType          types.VmType
+	NeedStorage     bool
+	DynamicInvoke   bool
+	ReentrancyGuard bool
+	Name            string
+	CodeVersion     string
+	Author          string
+	Email           string
+	Description     string
+	ABI             *ABI
 }
 
 func (dc *DeployCode) Data(version byte) []byte {
@@ -60,7 +66,7 @@ func (dc *DeployCode) Serialize(w io.Writer, version byte) error {
 		return err
 	}
 
-	err = serialization.WriteBool(w, dc.NeedStorage)
+	err = serialization.WriteByte(w, byte(types.NewContractProperty(dc.NeedStorage, dc.DynamicInvoke, dc.ReentrancyGuard)))
 	if err != nil {
 		return err
 	}
@@ -90,6 +96,17 @@ func (dc *DeployCode) Serialize(w io.Writer, version byte) error {
 		return err
 	}
 
+	if version >= DeployCodeABIPayloadVersion {
+		abi := dc.ABI
+		if abi == nil {
+			abi = new(ABI)
+		}
+		err = abi.Serialize(w)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -109,10 +126,13 @@ func (dc *DeployCode) Deserialize(r io.Reader, version byte) error {
 	}
 	dc.VmType = types.VmType(vmType)
 
-	dc.NeedStorage, err = serialization.ReadBool(r)
+	property, err := serialization.ReadByte(r)
 	if err != nil {
 		return NewDetailErr(err, ErrNoCode, "Transaction DeployCode NeedStorage Deserialize failed.")
 	}
+	dc.NeedStorage = types.ContractProperty(property).Has(types.HasStorage)
+	dc.DynamicInvoke = types.ContractProperty(property).Has(types.HasDynamicInvoke)
+	dc.ReentrancyGuard = types.ContractProperty(property).Has(types.HasReentrancyGuard)
 
 	dc.Name, err = serialization.ReadVarString(r)
 	if err != nil {
@@ -139,5 +159,13 @@ func (dc *DeployCode) Deserialize(r io.Reader, version byte) error {
 		return NewDetailErr(err, ErrNoCode, "Transaction DeployCode Description Deserialize failed.")
 	}
 
+	if version >= DeployCodeABIPayloadVersion {
+		dc.ABI = new(ABI)
+		err = dc.ABI.Deserialize(r)
+		if err != nil {
+			return NewDetailErr(err, ErrNoCode, "Transaction DeployCode ABI Deserialize failed.")
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package payload
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract"
+)
+
+func TestDeployCodeABI(t *testing.T) {
+	abi := &code.ABI{
+		Methods: []*code.ABIMethod{{
+			Name:       "balanceOf",
+			Parameters: []*code.ABIParameter{{Name: "account", Type: contract.Hash160}},
+			ReturnType: contract.Integer,
+		}},
+		Events: []*code.ABIEvent{},
+	}
+	cases := []struct {
+		version byte
+		abi     *code.ABI
+		want    *code.ABI
+	}{
+		{DeployCodePayloadVersion, abi, nil},
+		{DeployCodeABIPayloadVersion, abi, abi},
+		{DeployCodeABIPayloadVersion, nil, &code.ABI{Methods: []*code.ABIMethod{}, Events: []*code.ABIEvent{}}},
+	}
+	for _, c := range cases {
+		dc := &DeployCode{
+			Code:        &code.FunctionCode{Code: []byte{0x51}, ParameterTypes: []contract.ContractParameterType{}},
+			Name:        "token",
+			CodeVersion: "1",
+			Description: "a token",
+			ABI:         c.abi,
+		}
+		var buf bytes.Buffer
+		if err := dc.Serialize(&buf, c.version); err != nil {
+			t.Fatal(err)
+		}
+		if dc.ABI != c.abi {
+			t.Errorf("version %d: serializing changed the ABI of the payload", c.version)
+		}
+		decoded := new(DeployCode)
+		if err := decoded.Deserialize(bytes.NewReader(buf.Bytes()), c.version); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded.ABI, c.want) {
+			t.Errorf("version %d: got ABI %+v, want %+v", c.version, decoded.ABI, c.want)
+		}
+		if decoded.Name != "token" || decoded.Description != "a token" {
+			t.Errorf("version %d: got %+v", c.version, decoded)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package payload
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/Ontology/common"
+	. "github.com/Ontology/errors"
+)
+
+const DestroyPayloadVersion byte = 0x00
+
+// Destroy removes Amount of the asset from circulation, the amount is the
+// value of the asset spent by the inputs and not paid to the outputs.
+type Destroy struct {
+	AssetID common.Uint256
+	Amount  common.Fixed64
+}
+
+func (self *Destroy) Data(version byte) []byte {
+	var buf bytes.Buffer
+	self.Serialize(&buf, version)
+	return buf.Bytes()
+}
+
+func (self *Destroy) Serialize(w io.Writer, version byte) error {
+	if _, err := self.AssetID.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Destroy], AssetID Serialize failed.")
+	}
+	if err := self.Amount.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Destroy], Amount Serialize failed.")
+	}
+	return nil
+}
+
+func (self *Destroy) Deserialize(r io.Reader, version byte) error {
+	if err := self.AssetID.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Destroy], AssetID Deserialize failed.")
+	}
+	if err := self.Amount.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Destroy], Amount Deserialize failed.")
+	}
+	return nil
+}
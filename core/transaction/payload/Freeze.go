@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package payload
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	. "github.com/Ontology/errors"
+)
+
+const FreezePayloadVersion byte = 0x00
+
+type FreezeTarget byte
+
+const (
+	FreezeTarget_ASSET   FreezeTarget = 0
+	FreezeTarget_ACCOUNT FreezeTarget = 1
+)
+
+// Freeze freezes or unfreezes an asset, or an address within an asset, so its
+// coins can not be spent. It is signed by the admin of the asset.
+type Freeze struct {
+	Target  FreezeTarget
+	AssetID common.Uint256
+	Account common.Uint160 // only given for FreezeTarget_ACCOUNT
+	Frozen  bool
+}
+
+func (self *Freeze) Data(version byte) []byte {
+	var buf bytes.Buffer
+	self.Serialize(&buf, version)
+	return buf.Bytes()
+}
+
+func (self *Freeze) Serialize(w io.Writer, version byte) error {
+	if err := serialization.WriteByte(w, byte(self.Target)); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Freeze], Target Serialize failed.")
+	}
+	if _, err := self.AssetID.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Freeze], AssetID Serialize failed.")
+	}
+	if self.Target == FreezeTarget_ACCOUNT {
+		if _, err := self.Account.Serialize(w); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Freeze], Account Serialize failed.")
+		}
+	}
+	if err := serialization.WriteBool(w, self.Frozen); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Freeze], Frozen Serialize failed.")
+	}
+	return nil
+}
+
+func (self *Freeze) Deserialize(r io.Reader, version byte) error {
+	target, err := serialization.ReadByte(r)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Freeze], Target Deserialize failed.")
+	}
+	self.Target = FreezeTarget(target)
+	if self.Target != FreezeTarget_ASSET && self.Target != FreezeTarget_ACCOUNT {
+		return NewErr("[Freeze], Invalid target.")
+	}
+	if err := self.AssetID.Deserialize(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Freeze], AssetID Deserialize failed.")
+	}
+	if self.Target == FreezeTarget_ACCOUNT {
+		if err := self.Account.Deserialize(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Freeze], Account Deserialize failed.")
+		}
+	}
+	if self.Frozen, err = serialization.ReadBool(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Freeze], Frozen Deserialize failed.")
+	}
+	return nil
+}
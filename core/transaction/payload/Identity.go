@@ -0,0 +1,203 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package payload
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
+	"github.com/Ontology/crypto"
+	. "github.com/Ontology/errors"
+)
+
+const IdentityPayloadVersion byte = 0x00
+
+// MaxIdentityFieldSize bounds the ID and the attribute fields of an identity.
+const MaxIdentityFieldSize = 1024
+
+type IdentityAction byte
+
+const (
+	IdentityAction_REGISTER         IdentityAction = 0
+	IdentityAction_ADD_KEY          IdentityAction = 1
+	IdentityAction_REMOVE_KEY       IdentityAction = 2
+	IdentityAction_ADD_ATTRIBUTE    IdentityAction = 3
+	IdentityAction_UPDATE_ATTRIBUTE IdentityAction = 4
+	IdentityAction_REMOVE_ATTRIBUTE IdentityAction = 5
+	IdentityAction_SET_RECOVERY     IdentityAction = 6
+)
+
+type IdentityAttribute struct {
+	Key   []byte
+	Type  []byte
+	Value []byte
+}
+
+func (self *IdentityAttribute) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, self.Key); err != nil {
+		return err
+	}
+	if err := serialization.WriteVarBytes(w, self.Type); err != nil {
+		return err
+	}
+	return serialization.WriteVarBytes(w, self.Value)
+}
+
+func (self *IdentityAttribute) Deserialize(r io.Reader) error {
+	var err error
+	if self.Key, err = serialization.ReadVarBytes(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[IdentityAttribute], Key Deserialize failed.")
+	}
+	if self.Type, err = serialization.ReadVarBytes(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[IdentityAttribute], Type Deserialize failed.")
+	}
+	if self.Value, err = serialization.ReadVarBytes(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[IdentityAttribute], Value Deserialize failed.")
+	}
+	return nil
+}
+
+// Identity changes the DDO (the identity document) of an ONT ID. Register
+// creates the DDO with the owner key PubKey and is signed by it. Other
+// actions are signed by Signer, a key of the DDO, or by the recovery address
+// Recoverer of the DDO when Signer is nil.
+type Identity struct {
+	ID     []byte
+	Action IdentityAction
+	// PubKey is the owner key to register, or the key to add or remove
+	PubKey *crypto.PubKey
+	// Attribute to add or update, only its Key is used to remove one
+	Attribute *IdentityAttribute
+	// Recovery is the new recovery address
+	Recovery common.Uint160
+
+	Signer    *crypto.PubKey
+	Recoverer common.Uint160
+}
+
+func (self *Identity) Data(version byte) []byte {
+	var buf bytes.Buffer
+	self.Serialize(&buf, version)
+	return buf.Bytes()
+}
+
+func (self *Identity) Serialize(w io.Writer, version byte) error {
+	if err := serialization.WriteVarBytes(w, self.ID); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Identity], ID Serialize failed.")
+	}
+	if err := serialization.WriteByte(w, byte(self.Action)); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Identity], Action Serialize failed.")
+	}
+	switch self.Action {
+	case IdentityAction_REGISTER, IdentityAction_ADD_KEY, IdentityAction_REMOVE_KEY:
+		if self.PubKey == nil {
+			return NewErr("[Identity], PubKey is nil.")
+		}
+		if err := self.PubKey.Serialize(w); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Identity], PubKey Serialize failed.")
+		}
+	case IdentityAction_ADD_ATTRIBUTE, IdentityAction_UPDATE_ATTRIBUTE, IdentityAction_REMOVE_ATTRIBUTE:
+		if self.Attribute == nil {
+			return NewErr("[Identity], Attribute is nil.")
+		}
+		if err := self.Attribute.Serialize(w); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Identity], Attribute Serialize failed.")
+		}
+	case IdentityAction_SET_RECOVERY:
+		if _, err := self.Recovery.Serialize(w); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Identity], Recovery Serialize failed.")
+		}
+	default:
+		return NewErr("[Identity], Invalid action.")
+	}
+	if self.Action == IdentityAction_REGISTER {
+		return nil
+	}
+	// the signer is a key of the DDO, or its recovery address
+	if self.Signer != nil {
+		if err := serialization.WriteByte(w, 0); err != nil {
+			return err
+		}
+		if err := self.Signer.Serialize(w); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Identity], Signer Serialize failed.")
+		}
+		return nil
+	}
+	if err := serialization.WriteByte(w, 1); err != nil {
+		return err
+	}
+	if _, err := self.Recoverer.Serialize(w); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Identity], Recoverer Serialize failed.")
+	}
+	return nil
+}
+
+func (self *Identity) Deserialize(r io.Reader, version byte) error {
+	var err error
+	if self.ID, err = serialization.ReadVarBytes(r); err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Identity], ID Deserialize failed.")
+	}
+	action, err := serialization.ReadByte(r)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Identity], Action Deserialize failed.")
+	}
+	self.Action = IdentityAction(action)
+	switch self.Action {
+	case IdentityAction_REGISTER, IdentityAction_ADD_KEY, IdentityAction_REMOVE_KEY:
+		self.PubKey = new(crypto.PubKey)
+		if err := self.PubKey.DeSerialize(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Identity], PubKey Deserialize failed.")
+		}
+	case IdentityAction_ADD_ATTRIBUTE, IdentityAction_UPDATE_ATTRIBUTE, IdentityAction_REMOVE_ATTRIBUTE:
+		self.Attribute = new(IdentityAttribute)
+		if err := self.Attribute.Deserialize(r); err != nil {
+			return err
+		}
+	case IdentityAction_SET_RECOVERY:
+		if err := self.Recovery.Deserialize(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Identity], Recovery Deserialize failed.")
+		}
+	default:
+		return NewErr("[Identity], Invalid action.")
+	}
+	if self.Action == IdentityAction_REGISTER {
+		return nil
+	}
+	kind, err := serialization.ReadByte(r)
+	if err != nil {
+		return NewDetailErr(err, ErrNoCode, "[Identity], Signer Deserialize failed.")
+	}
+	switch kind {
+	case 0:
+		self.Signer = new(crypto.PubKey)
+		if err := self.Signer.DeSerialize(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Identity], Signer Deserialize failed.")
+		}
+	case 1:
+		if err := self.Recoverer.Deserialize(r); err != nil {
+			return NewDetailErr(err, ErrNoCode, "[Identity], Recoverer Deserialize failed.")
+		}
+	default:
+		return NewErr("[Identity], Invalid signer.")
+	}
+	return nil
+}
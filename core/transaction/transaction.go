@@ -21,6 +21,7 @@ package transaction
 
 import (
 	. "github.com/Ontology/common"
+	"github.com/Ontology/common/config"
 	"github.com/Ontology/common/serialization"
 	"github.com/Ontology/core/contract"
 	"github.com/Ontology/core/contract/program"
@@ -28,6 +29,7 @@ import (
 	"github.com/Ontology/core/transaction/payload"
 	. "github.com/Ontology/errors"
 	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
@@ -53,8 +55,41 @@ const (
 	Deploy TransactionType = 0xd0
 	Invoke TransactionType = 0xd1
 	DataFile TransactionType = 0x12
+	Identity TransactionType = 0x13
+	Attestation TransactionType = 0x14
+	Freeze TransactionType = 0x15
+	AssetRenew TransactionType = 0x16
+	AssetAdminChange TransactionType = 0x17
+	Destroy TransactionType = 0x18
 )
 
+var transactionTypeNames = map[TransactionType]string{
+	BookKeeping:      "BookKeeping",
+	IssueAsset:       "IssueAsset",
+	BookKeeper:       "BookKeeper",
+	Claim:            "Claim",
+	PrivacyPayload:   "PrivacyPayload",
+	RegisterAsset:    "RegisterAsset",
+	TransferAsset:    "TransferAsset",
+	Record:           "Record",
+	Deploy:           "Deploy",
+	Invoke:           "Invoke",
+	DataFile:         "DataFile",
+	Identity:         "Identity",
+	Attestation:      "Attestation",
+	Freeze:           "Freeze",
+	AssetRenew:       "AssetRenew",
+	AssetAdminChange: "AssetAdminChange",
+	Destroy:          "Destroy",
+}
+
+func (t TransactionType) String() string {
+	if name, ok := transactionTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("TransactionType(0x%x)", byte(t))
+}
+
 //Payload define the func for loading the payload data
 //base on payload type which have different struture
 type Payload interface {
@@ -67,6 +102,15 @@ type Payload interface {
 	Deserialize(r io.Reader, version byte) error
 }
 
+// OutputLockVersion is set in the PayloadVersion of a transaction whose
+// outputs carry a lock, NetworkMagicVersion in the one of a transaction
+// carrying the magic of the network it is valid on. The other bits are the
+// version of the payload.
+const (
+	OutputLockVersion   byte = 0x80
+	NetworkMagicVersion byte = 0x40
+)
+
 //Transaction is used for carry information or action to Ledger
 //validated transaction will be added to block and updates state correspondingly
 
@@ -81,6 +125,9 @@ type Transaction struct {
 	BalanceInputs     []*BalanceTxInput
 	Outputs           []*TxOutput
 	Programs          []*program.Program
+	// NetworkMagic is serialized after the PayloadVersion when it has the
+	// NetworkMagicVersion flag, it is then signed with the transaction
+	NetworkMagic      uint32
 
 	//Inputs/Outputs map base on Asset (needn't serialize)
 	AssetOutputs      map[Uint256][]*TxOutput
@@ -119,12 +166,24 @@ func (tx *Transaction) SerializeUnsigned(w io.Writer) error {
 	//txType
 	w.Write([]byte{byte(tx.TxType)})
 	//PayloadVersion
-	w.Write([]byte{tx.PayloadVersion})
+	version := tx.PayloadVersion
+	if tx.hasLockedOutputs() {
+		version |= OutputLockVersion
+	}
+	if tx.HasNetworkMagic() {
+		version |= NetworkMagicVersion
+	}
+	w.Write([]byte{version})
+	if version&NetworkMagicVersion != 0 {
+		if err := serialization.WriteUint32(w, tx.NetworkMagic); err != nil {
+			return NewDetailErr(err, ErrNoCode, "Transaction NetworkMagic serialization failed.")
+		}
+	}
 	//Payload
 	if tx.Payload == nil {
 		return errors.New("Transaction Payload is nil.")
 	}
-	tx.Payload.Serialize(w, tx.PayloadVersion)
+	tx.Payload.Serialize(w, tx.payloadVersion())
 	//[]*txAttribute
 	err := serialization.WriteVarUint(w, uint64(len(tx.Attributes)))
 	if err != nil {
@@ -154,6 +213,11 @@ func (tx *Transaction) SerializeUnsigned(w io.Writer) error {
 	if len(tx.Outputs) > 0 {
 		for _, output := range tx.Outputs {
 			output.Serialize(w)
+			if version&OutputLockVersion != 0 {
+				if err := output.SerializeLock(w); err != nil {
+					return NewDetailErr(err, ErrNoCode, "Transaction item Outputs lock serialization failed.")
+				}
+			}
 		}
 	}
 
@@ -203,6 +267,11 @@ func (tx *Transaction) DeserializeUnsignedWithoutType(r io.Reader) error {
 	if err != nil {
 		return err
 	}
+	if tx.PayloadVersion&NetworkMagicVersion != 0 {
+		if tx.NetworkMagic, err = serialization.ReadUint32(r); err != nil {
+			return err
+		}
+	}
 
 	//payload
 	//tx.Payload.Deserialize(r)
@@ -227,10 +296,22 @@ func (tx *Transaction) DeserializeUnsignedWithoutType(r io.Reader) error {
 		tx.Payload = new(payload.DeployCode)
 	case Invoke:
 		tx.Payload = new(payload.InvokeCode)
+	case Identity:
+		tx.Payload = new(payload.Identity)
+	case Attestation:
+		tx.Payload = new(payload.Attestation)
+	case Freeze:
+		tx.Payload = new(payload.Freeze)
+	case AssetRenew:
+		tx.Payload = new(payload.AssetRenew)
+	case AssetAdminChange:
+		tx.Payload = new(payload.AssetAdminChange)
+	case Destroy:
+		tx.Payload = new(payload.Destroy)
 	default:
 		return errors.New("[Transaction],invalide transaction type.")
 	}
-	err = tx.Payload.Deserialize(r, tx.PayloadVersion)
+	err = tx.Payload.Deserialize(r, tx.payloadVersion())
 	if err != nil {
 		return NewDetailErr(err, ErrNoCode, "Payload Parse error")
 	}
@@ -274,6 +355,11 @@ func (tx *Transaction) DeserializeUnsignedWithoutType(r io.Reader) error {
 		for i := uint64(0); i < Len; i++ {
 			output := new(TxOutput)
 			output.Deserialize(r)
+			if tx.PayloadVersion&OutputLockVersion != 0 {
+				if err := output.DeserializeLock(r); err != nil {
+					return err
+				}
+			}
 
 			tx.Outputs = append(tx.Outputs, output)
 		}
@@ -281,6 +367,49 @@ func (tx *Transaction) DeserializeUnsignedWithoutType(r io.Reader) error {
 	return nil
 }
 
+// payloadVersion returns the PayloadVersion without the transaction flags.
+func (tx *Transaction) payloadVersion() byte {
+	return tx.PayloadVersion &^ (OutputLockVersion | NetworkMagicVersion)
+}
+
+// SetNetworkMagic binds the transaction to the network with the given magic.
+func (tx *Transaction) SetNetworkMagic(magic uint32) {
+	tx.PayloadVersion |= NetworkMagicVersion
+	tx.NetworkMagic = magic
+	tx.hash = nil
+}
+
+// HasNetworkMagic reports if the transaction carries a network magic.
+func (tx *Transaction) HasNetworkMagic() bool {
+	return tx.PayloadVersion&NetworkMagicVersion != 0 || tx.NetworkMagic != 0
+}
+
+// hasLockedOutputs reports if the outputs are serialized with their lock. A
+// deserialized transaction keeps the flag of its PayloadVersion, so its hash
+// does not change.
+func (tx *Transaction) hasLockedOutputs() bool {
+	if tx.PayloadVersion&OutputLockVersion != 0 {
+		return true
+	}
+	for _, output := range tx.Outputs {
+		if output.IsLocked() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetValidUntilBlock returns the last block height the transaction can be
+// included at, false if it does not expire.
+func (tx *Transaction) GetValidUntilBlock() (uint32, bool) {
+	for _, attr := range tx.Attributes {
+		if attr.Usage == ValidUntilBlock && len(attr.Data) == 4 {
+			return binary.LittleEndian.Uint32(attr.Data), true
+		}
+	}
+	return 0, false
+}
+
 func (tx *Transaction) GetProgramHashes() ([]Uint160, error) {
 	if tx == nil {
 		return []Uint160{}, errors.New("[Transaction],GetProgramHashes transaction is nil.")
@@ -324,20 +453,13 @@ func (tx *Transaction) GetProgramHashes() ([]Uint160, error) {
 			return nil, NewDetailErr(err, ErrNoCode, "[Transaction], GetTransactionResults failed.")
 		}
 		for k := range result {
-			tx, err := TxStore.GetTransaction(k)
+			// the issuer starts as the controller of the RegisterAsset and
+			// may be replaced by an AssetAdminChange
+			asset, err := TxStore.GetAsset(k)
 			if err != nil {
-				return nil, NewDetailErr(err, ErrNoCode, fmt.Sprintf("[Transaction], GetTransaction failed With AssetID:=%x", k))
-			}
-			if tx.TxType != RegisterAsset {
-				return nil, NewDetailErr(errors.New("[Transaction] error"), ErrNoCode, fmt.Sprintf("[Transaction], Transaction Type ileage With AssetID:=%x", k))
-			}
-
-			switch v1 := tx.Payload.(type) {
-			case *payload.RegisterAsset:
-				hashs = append(hashs, v1.Controller)
-			default:
-				return nil, NewDetailErr(errors.New("[Transaction] error"), ErrNoCode, fmt.Sprintf("[Transaction], payload is illegal", k))
+				return nil, NewDetailErr(err, ErrNoCode, fmt.Sprintf("[Transaction], GetAsset failed With AssetID:=%x", k))
 			}
+			hashs = append(hashs, asset.Issuer)
 		}
 	case DataFile:
 		issuer := tx.Payload.(*payload.DataFile).Issuer
@@ -377,6 +499,49 @@ func (tx *Transaction) GetProgramHashes() ([]Uint160, error) {
 			return nil, NewDetailErr(err, ErrNoCode, "[Transaction], GetProgramHashes ToCodeHash failed.")
 		}
 		hashs = append(hashs, astHash)
+	case Identity:
+		id := tx.Payload.(*payload.Identity)
+		signer := id.Signer
+		if id.Action == payload.IdentityAction_REGISTER {
+			signer = id.PubKey
+		}
+		if signer == nil {
+			hashs = append(hashs, id.Recoverer)
+			break
+		}
+		signatureRedeemScript, err := contract.CreateSignatureRedeemScript(signer)
+		if err != nil {
+			return nil, NewDetailErr(err, ErrNoCode, "[Transaction - Identity], GetProgramHashes CreateSignatureRedeemScript failed.")
+		}
+
+		astHash, err := ToCodeHash(signatureRedeemScript)
+		if err != nil {
+			return nil, NewDetailErr(err, ErrNoCode, "[Transaction - Identity], GetProgramHashes ToCodeHash failed.")
+		}
+		hashs = append(hashs, astHash)
+	case Freeze:
+		assetID := tx.Payload.(*payload.Freeze).AssetID
+		asset, err := TxStore.GetAsset(assetID)
+		if err != nil {
+			return nil, NewDetailErr(err, ErrNoCode, fmt.Sprintf("[Transaction - Freeze], GetAsset failed With AssetID:=%x", assetID))
+		}
+		hashs = append(hashs, asset.Admin)
+	case AssetAdminChange:
+		assetID := tx.Payload.(*payload.AssetAdminChange).AssetID
+		asset, err := TxStore.GetAsset(assetID)
+		if err != nil {
+			return nil, NewDetailErr(err, ErrNoCode, fmt.Sprintf("[Transaction - AssetAdminChange], GetAsset failed With AssetID:=%x", assetID))
+		}
+		signatureRedeemScript, err := contract.CreateSignatureRedeemScript(asset.Owner)
+		if err != nil {
+			return nil, NewDetailErr(err, ErrNoCode, "[Transaction - AssetAdminChange], GetProgramHashes CreateSignatureRedeemScript failed.")
+		}
+
+		astHash, err := ToCodeHash(signatureRedeemScript)
+		if err != nil {
+			return nil, NewDetailErr(err, ErrNoCode, "[Transaction - AssetAdminChange], GetProgramHashes ToCodeHash failed.")
+		}
+		hashs = append(hashs, astHash)
 	default:
 	}
 	//remove dupilicated hashes
@@ -464,6 +629,36 @@ func (tx *Transaction) GetReference() (map[*UTXOTxInput]*TxOutput, error) {
 	}
 	return reference, nil
 }
+// GetSystemFee returns the system fee of the transaction configured for its
+// type, a renewal pays it for every year. Issue and register transactions
+// reference no inputs to pay a fee with, so they pay none whatever is
+// configured, the config warns about it when loaded.
+func (tx *Transaction) GetSystemFee() Fixed64 {
+	if tx.TxType == IssueAsset || tx.TxType == RegisterAsset {
+		return 0
+	}
+	fee := Fixed64(config.Parameters.SystemFee[tx.TxType.String()])
+	if p, ok := tx.Payload.(*payload.AssetRenew); ok {
+		fee *= Fixed64(p.Years)
+	}
+	return fee
+}
+
+// GetSystemFeeAssetID returns the asset paying system fees, ok is false when
+// none is configured and no fee is charged. A malformed asset id is rejected
+// when the config is loaded.
+func GetSystemFeeAssetID() (assetID Uint256, ok bool) {
+	if config.Parameters.SystemFeeAsset == "" {
+		return Uint256{}, false
+	}
+	b, err := HexToBytes(config.Parameters.SystemFeeAsset)
+	if err != nil {
+		return Uint256{}, false
+	}
+	assetID, err = Uint256ParseFromBytes(b)
+	return assetID, err == nil
+}
+
 func (tx *Transaction) GetTransactionResults() (TransactionResult, error) {
 	result := make(map[Uint256]Fixed64)
 	outputResult := tx.GetMergedAssetIDValueFromOutputs()
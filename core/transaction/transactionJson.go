@@ -0,0 +1,683 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package transaction
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/core/asset"
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract"
+	"github.com/Ontology/core/contract/program"
+	"github.com/Ontology/core/transaction/payload"
+	. "github.com/Ontology/core/transaction/utxo"
+	"github.com/Ontology/crypto"
+	"github.com/Ontology/smartcontract/types"
+)
+
+// TransactionJson is the canonical JSON form of a transaction. Byte strings,
+// hashes and public keys are hex encoded, public keys compressed, and it
+// converts to the binary form and back without loss.
+type TransactionJson struct {
+	TxType         string
+	PayloadVersion byte
+	NetworkMagic   uint32 `json:",omitempty"`
+	Payload        json.RawMessage
+	Attributes     []TxAttributeJson
+	UTXOInputs     []UTXOTxInputJson
+	Outputs        []TxOutputJson
+	Programs       []ProgramJson
+}
+
+type TxAttributeJson struct {
+	Usage TransactionAttributeUsage
+	Data  HexBytes
+}
+
+type UTXOTxInputJson struct {
+	ReferTxID          HexBytes
+	ReferTxOutputIndex uint16
+}
+
+type TxOutputJson struct {
+	AssetID     HexBytes
+	Value       Fixed64
+	ProgramHash HexBytes
+	LockType    OutputLockType `json:",omitempty"`
+	LockValue   uint32         `json:",omitempty"`
+}
+
+type ProgramJson struct {
+	Code      HexBytes
+	Parameter HexBytes
+}
+
+// HexBytes is a byte string encoded as hex in JSON.
+type HexBytes []byte
+
+func (h HexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ToHexString(h))
+}
+
+func (h *HexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	b, err := HexToBytes(s)
+	if err != nil {
+		return err
+	}
+	*h = b
+	return nil
+}
+
+func encodePubKey(pk *crypto.PubKey) (HexBytes, error) {
+	if pk == nil {
+		return nil, nil
+	}
+	return pk.EncodePoint(true)
+}
+
+func decodePubKey(h HexBytes) (*crypto.PubKey, error) {
+	if len(h) == 0 {
+		return nil, nil
+	}
+	return crypto.DecodePoint(h)
+}
+
+// ParseTransactionType returns the type with the given name.
+func ParseTransactionType(name string) (TransactionType, error) {
+	for t, n := range transactionTypeNames {
+		if n == name {
+			return t, nil
+		}
+	}
+	return 0, errors.New(fmt.Sprintf("[Transaction], Unknown transaction type %s.", name))
+}
+
+// ToJson returns the canonical JSON form of the transaction.
+func (tx *Transaction) ToJson() (*TransactionJson, error) {
+	obj := &TransactionJson{
+		TxType:         tx.TxType.String(),
+		PayloadVersion: tx.PayloadVersion,
+		NetworkMagic:   tx.NetworkMagic,
+		Attributes:     make([]TxAttributeJson, 0, len(tx.Attributes)),
+		UTXOInputs:     make([]UTXOTxInputJson, 0, len(tx.UTXOInputs)),
+		Outputs:        make([]TxOutputJson, 0, len(tx.Outputs)),
+		Programs:       make([]ProgramJson, 0, len(tx.Programs)),
+	}
+	p, err := payloadToJson(tx.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if obj.Payload, err = json.Marshal(p); err != nil {
+		return nil, err
+	}
+	for _, a := range tx.Attributes {
+		obj.Attributes = append(obj.Attributes, TxAttributeJson{Usage: a.Usage, Data: a.Data})
+	}
+	for _, i := range tx.UTXOInputs {
+		obj.UTXOInputs = append(obj.UTXOInputs, UTXOTxInputJson{
+			ReferTxID:          i.ReferTxID.ToArray(),
+			ReferTxOutputIndex: i.ReferTxOutputIndex,
+		})
+	}
+	for _, o := range tx.Outputs {
+		obj.Outputs = append(obj.Outputs, TxOutputJson{
+			AssetID:     o.AssetID.ToArray(),
+			Value:       o.Value,
+			ProgramHash: o.ProgramHash.ToArray(),
+			LockType:    o.LockType,
+			LockValue:   o.LockValue,
+		})
+	}
+	for _, p := range tx.Programs {
+		obj.Programs = append(obj.Programs, ProgramJson{Code: p.Code, Parameter: p.Parameter})
+	}
+	return obj, nil
+}
+
+// ToTransaction returns the transaction of the JSON form.
+func (obj *TransactionJson) ToTransaction() (*Transaction, error) {
+	txType, err := ParseTransactionType(obj.TxType)
+	if err != nil {
+		return nil, err
+	}
+	tx := &Transaction{
+		TxType:         txType,
+		PayloadVersion: obj.PayloadVersion,
+		NetworkMagic:   obj.NetworkMagic,
+		Attributes:     []*TxAttribute{},
+		UTXOInputs:     []*UTXOTxInput{},
+		BalanceInputs:  []*BalanceTxInput{},
+		Outputs:        []*TxOutput{},
+		Programs:       []*program.Program{},
+	}
+	if tx.Payload, err = payloadFromJson(txType, obj.Payload); err != nil {
+		return nil, err
+	}
+	for _, a := range obj.Attributes {
+		if !IsValidAttributeType(a.Usage) {
+			return nil, errors.New("[Transaction], Unsupported attribute usage.")
+		}
+		if min, max := AttributeDataSize(a.Usage); len(a.Data) < min || len(a.Data) > max {
+			return nil, errors.New(fmt.Sprintf("[Transaction], Invalid %s attribute size.", a.Usage))
+		}
+		attr := NewTxAttribute(a.Usage, a.Data)
+		tx.Attributes = append(tx.Attributes, &attr)
+	}
+	for _, i := range obj.UTXOInputs {
+		referTxID, err := Uint256ParseFromBytes(i.ReferTxID)
+		if err != nil {
+			return nil, err
+		}
+		tx.UTXOInputs = append(tx.UTXOInputs, &UTXOTxInput{ReferTxID: referTxID, ReferTxOutputIndex: i.ReferTxOutputIndex})
+	}
+	for _, o := range obj.Outputs {
+		assetID, err := Uint256ParseFromBytes(o.AssetID)
+		if err != nil {
+			return nil, err
+		}
+		programHash, err := Uint160ParseFromBytes(o.ProgramHash)
+		if err != nil {
+			return nil, err
+		}
+		if o.LockType > OutputLock_TIME {
+			return nil, errors.New("[Transaction], Invalid output lock type.")
+		}
+		tx.Outputs = append(tx.Outputs, &TxOutput{
+			AssetID:     assetID,
+			Value:       o.Value,
+			ProgramHash: programHash,
+			LockType:    o.LockType,
+			LockValue:   o.LockValue,
+		})
+	}
+	for _, p := range obj.Programs {
+		tx.Programs = append(tx.Programs, &program.Program{Code: p.Code, Parameter: p.Parameter})
+	}
+	return tx, nil
+}
+
+type bookKeepingJson struct {
+	Nonce uint64
+}
+
+type bookKeeperJson struct {
+	PubKey HexBytes
+	Action payload.BookKeeperAction
+	Cert   HexBytes
+	Issuer HexBytes
+}
+
+type registerAssetJson struct {
+	Asset      *asset.Asset
+	Amount     Fixed64
+	Issuer     HexBytes
+	Controller HexBytes
+}
+
+type recordJson struct {
+	RecordType string
+	RecordData HexBytes
+}
+
+type dataFileJson struct {
+	IPFSPath string
+	Filename string
+	Note     string
+	Issuer   HexBytes
+}
+
+type ecdhAes256Json struct {
+	FromPubkey HexBytes
+	ToPubkey   HexBytes
+	Nonce      HexBytes
+}
+
+type privacyPayloadJson struct {
+	PayloadType payload.EncryptedPayloadType
+	Payload     HexBytes
+	EncryptType payload.PayloadEncryptType
+	EncryptAttr *ecdhAes256Json
+}
+
+type deployCodeJson struct {
+	Code            HexBytes
+	ParameterTypes  HexBytes
+	ReturnType      byte
+	VmType          byte
+	NeedStorage     bool
+	DynamicInvoke   bool
+	ReentrancyGuard bool
+	Name            string
+	CodeVersion     string
+	Author          string
+	Email           string
+	Description     string
+	ABI             *code.ABI `json:",omitempty"`
+}
+
+type invokeCodeJson struct {
+	CodeHash HexBytes
+	Code     HexBytes
+}
+
+type identityAttributeJson struct {
+	Key   HexBytes
+	Type  HexBytes
+	Value HexBytes
+}
+
+type identityJson struct {
+	ID        HexBytes
+	Action    payload.IdentityAction
+	PubKey    HexBytes               `json:",omitempty"`
+	Attribute *identityAttributeJson `json:",omitempty"`
+	Recovery  HexBytes
+	Signer    HexBytes `json:",omitempty"`
+	Recoverer HexBytes
+}
+
+type attestationJson struct {
+	Action    payload.AttestationAction
+	ClaimHash HexBytes
+	IssuerID  HexBytes
+	SubjectID HexBytes
+	Expiry    uint32
+	Signer    HexBytes
+	Signature HexBytes
+}
+
+type freezeJson struct {
+	Target  payload.FreezeTarget
+	AssetID HexBytes
+	Account HexBytes
+	Frozen  bool
+}
+
+type assetRenewJson struct {
+	AssetID HexBytes
+	Years   uint32
+}
+
+type assetAdminChangeJson struct {
+	AssetID HexBytes
+	Admin   HexBytes
+	Issuer  HexBytes
+}
+
+type destroyJson struct {
+	AssetID HexBytes
+	Amount  Fixed64
+}
+
+func payloadToJson(p Payload) (interface{}, error) {
+	var err error
+	switch object := p.(type) {
+	case *payload.BookKeeping:
+		return &bookKeepingJson{Nonce: object.Nonce}, nil
+	case *payload.IssueAsset, *payload.TransferAsset:
+		return struct{}{}, nil
+	case *payload.BookKeeper:
+		obj := &bookKeeperJson{Action: object.Action, Cert: object.Cert}
+		if obj.PubKey, err = encodePubKey(object.PubKey); err != nil {
+			return nil, err
+		}
+		if obj.Issuer, err = encodePubKey(object.Issuer); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case *payload.RegisterAsset:
+		obj := &registerAssetJson{
+			Asset:      object.Asset,
+			Amount:     object.Amount,
+			Controller: object.Controller.ToArray(),
+		}
+		if obj.Issuer, err = encodePubKey(object.Issuer); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case *payload.Record:
+		return &recordJson{RecordType: object.RecordType, RecordData: object.RecordData}, nil
+	case *payload.DataFile:
+		obj := &dataFileJson{IPFSPath: object.IPFSPath, Filename: object.Filename, Note: object.Note}
+		if obj.Issuer, err = encodePubKey(object.Issuer); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case *payload.PrivacyPayload:
+		obj := &privacyPayloadJson{
+			PayloadType: object.PayloadType,
+			Payload:     HexBytes(object.Payload),
+			EncryptType: object.EncryptType,
+		}
+		attr, ok := object.EncryptAttr.(*payload.EcdhAes256)
+		if !ok {
+			return nil, errors.New("[Transaction], Unknown EncryptType.")
+		}
+		obj.EncryptAttr = &ecdhAes256Json{Nonce: attr.Nonce}
+		if obj.EncryptAttr.FromPubkey, err = encodePubKey(attr.FromPubkey); err != nil {
+			return nil, err
+		}
+		if obj.EncryptAttr.ToPubkey, err = encodePubKey(attr.ToPubkey); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case *payload.DeployCode:
+		obj := &deployCodeJson{
+			VmType:          byte(object.VmType),
+			NeedStorage:     object.NeedStorage,
+			DynamicInvoke:   object.DynamicInvoke,
+			ReentrancyGuard: object.ReentrancyGuard,
+			Name:            object.Name,
+			CodeVersion:     object.CodeVersion,
+			Author:          object.Author,
+			Email:           object.Email,
+			Description:     object.Description,
+			ABI:             object.ABI,
+		}
+		if object.Code != nil {
+			obj.Code = object.Code.Code
+			obj.ParameterTypes = contract.ContractParameterTypeToByte(object.Code.ParameterTypes)
+			obj.ReturnType = byte(object.Code.ReturnType)
+		}
+		return obj, nil
+	case *payload.InvokeCode:
+		return &invokeCodeJson{CodeHash: object.CodeHash.ToArray(), Code: object.Code}, nil
+	case *payload.Identity:
+		obj := &identityJson{
+			ID:        object.ID,
+			Action:    object.Action,
+			Recovery:  object.Recovery.ToArray(),
+			Recoverer: object.Recoverer.ToArray(),
+		}
+		if object.Attribute != nil {
+			obj.Attribute = &identityAttributeJson{
+				Key:   object.Attribute.Key,
+				Type:  object.Attribute.Type,
+				Value: object.Attribute.Value,
+			}
+		}
+		if obj.PubKey, err = encodePubKey(object.PubKey); err != nil {
+			return nil, err
+		}
+		if obj.Signer, err = encodePubKey(object.Signer); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case *payload.Attestation:
+		obj := &attestationJson{
+			Action:    object.Action,
+			ClaimHash: object.ClaimHash.ToArray(),
+			IssuerID:  object.IssuerID,
+			SubjectID: object.SubjectID,
+			Expiry:    object.Expiry,
+			Signature: object.Signature,
+		}
+		if obj.Signer, err = encodePubKey(object.Signer); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case *payload.Freeze:
+		return &freezeJson{
+			Target:  object.Target,
+			AssetID: object.AssetID.ToArray(),
+			Account: object.Account.ToArray(),
+			Frozen:  object.Frozen,
+		}, nil
+	case *payload.AssetRenew:
+		return &assetRenewJson{AssetID: object.AssetID.ToArray(), Years: object.Years}, nil
+	case *payload.AssetAdminChange:
+		return &assetAdminChangeJson{
+			AssetID: object.AssetID.ToArray(),
+			Admin:   object.Admin.ToArray(),
+			Issuer:  object.Issuer.ToArray(),
+		}, nil
+	case *payload.Destroy:
+		return &destroyJson{AssetID: object.AssetID.ToArray(), Amount: object.Amount}, nil
+	}
+	return nil, errors.New("[Transaction], Unsupported payload.")
+}
+
+func payloadFromJson(txType TransactionType, data json.RawMessage) (Payload, error) {
+	var err error
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+	switch txType {
+	case BookKeeping:
+		obj := new(bookKeepingJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		return &payload.BookKeeping{Nonce: obj.Nonce}, nil
+	case IssueAsset:
+		return &payload.IssueAsset{}, nil
+	case TransferAsset:
+		return &payload.TransferAsset{}, nil
+	case BookKeeper:
+		obj := new(bookKeeperJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		p := &payload.BookKeeper{Action: obj.Action, Cert: obj.Cert}
+		if p.PubKey, err = decodePubKey(obj.PubKey); err != nil {
+			return nil, err
+		}
+		if p.Issuer, err = decodePubKey(obj.Issuer); err != nil {
+			return nil, err
+		}
+		return p, nil
+	case RegisterAsset:
+		obj := new(registerAssetJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		p := &payload.RegisterAsset{Asset: obj.Asset, Amount: obj.Amount}
+		if p.Asset == nil {
+			return nil, errors.New("[Transaction], RegisterAsset Asset is missing.")
+		}
+		if p.Issuer, err = decodePubKey(obj.Issuer); err != nil {
+			return nil, err
+		}
+		if p.Controller, err = Uint160ParseFromBytes(obj.Controller); err != nil {
+			return nil, err
+		}
+		return p, nil
+	case Record:
+		obj := new(recordJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		return &payload.Record{RecordType: obj.RecordType, RecordData: obj.RecordData}, nil
+	case DataFile:
+		obj := new(dataFileJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		p := &payload.DataFile{IPFSPath: obj.IPFSPath, Filename: obj.Filename, Note: obj.Note}
+		if p.Issuer, err = decodePubKey(obj.Issuer); err != nil {
+			return nil, err
+		}
+		return p, nil
+	case PrivacyPayload:
+		obj := new(privacyPayloadJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		if obj.EncryptType != payload.ECDH_AES256 || obj.EncryptAttr == nil {
+			return nil, errors.New("[Transaction], Unknown EncryptType.")
+		}
+		attr := &payload.EcdhAes256{Nonce: obj.EncryptAttr.Nonce}
+		if attr.FromPubkey, err = decodePubKey(obj.EncryptAttr.FromPubkey); err != nil {
+			return nil, err
+		}
+		if attr.ToPubkey, err = decodePubKey(obj.EncryptAttr.ToPubkey); err != nil {
+			return nil, err
+		}
+		return &payload.PrivacyPayload{
+			PayloadType: obj.PayloadType,
+			Payload:     payload.EncryptedPayload(obj.Payload),
+			EncryptType: obj.EncryptType,
+			EncryptAttr: attr,
+		}, nil
+	case Deploy:
+		obj := new(deployCodeJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		return &payload.DeployCode{
+			Code: &code.FunctionCode{
+				Code:           obj.Code,
+				ParameterTypes: contract.ByteToContractParameterType(obj.ParameterTypes),
+				ReturnType:     contract.ContractParameterType(obj.ReturnType),
+			},
+			VmType:          types.VmType(obj.VmType),
+			NeedStorage:     obj.NeedStorage,
+			DynamicInvoke:   obj.DynamicInvoke,
+			ReentrancyGuard: obj.ReentrancyGuard,
+			Name:            obj.Name,
+			CodeVersion:     obj.CodeVersion,
+			Author:          obj.Author,
+			Email:           obj.Email,
+			Description:     obj.Description,
+			ABI:             obj.ABI,
+		}, nil
+	case Invoke:
+		obj := new(invokeCodeJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		p := &payload.InvokeCode{Code: obj.Code}
+		if p.CodeHash, err = Uint160ParseFromBytes(obj.CodeHash); err != nil {
+			return nil, err
+		}
+		return p, nil
+	case Identity:
+		obj := new(identityJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		p := &payload.Identity{ID: obj.ID, Action: obj.Action}
+		if obj.Attribute != nil {
+			p.Attribute = &payload.IdentityAttribute{
+				Key:   obj.Attribute.Key,
+				Type:  obj.Attribute.Type,
+				Value: obj.Attribute.Value,
+			}
+		}
+		if p.PubKey, err = decodePubKey(obj.PubKey); err != nil {
+			return nil, err
+		}
+		if p.Signer, err = decodePubKey(obj.Signer); err != nil {
+			return nil, err
+		}
+		if p.Recovery, err = parseOptionalUint160(obj.Recovery); err != nil {
+			return nil, err
+		}
+		if p.Recoverer, err = parseOptionalUint160(obj.Recoverer); err != nil {
+			return nil, err
+		}
+		return p, nil
+	case Attestation:
+		obj := new(attestationJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		p := &payload.Attestation{
+			Action:    obj.Action,
+			IssuerID:  obj.IssuerID,
+			SubjectID: obj.SubjectID,
+			Expiry:    obj.Expiry,
+			Signature: obj.Signature,
+		}
+		if p.ClaimHash, err = Uint256ParseFromBytes(obj.ClaimHash); err != nil {
+			return nil, err
+		}
+		if p.Signer, err = decodePubKey(obj.Signer); err != nil {
+			return nil, err
+		}
+		return p, nil
+	case Freeze:
+		obj := new(freezeJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		p := &payload.Freeze{Target: obj.Target, Frozen: obj.Frozen}
+		if p.AssetID, err = Uint256ParseFromBytes(obj.AssetID); err != nil {
+			return nil, err
+		}
+		if p.Account, err = parseOptionalUint160(obj.Account); err != nil {
+			return nil, err
+		}
+		return p, nil
+	case AssetRenew:
+		obj := new(assetRenewJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		p := &payload.AssetRenew{Years: obj.Years}
+		if p.AssetID, err = Uint256ParseFromBytes(obj.AssetID); err != nil {
+			return nil, err
+		}
+		return p, nil
+	case AssetAdminChange:
+		obj := new(assetAdminChangeJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		p := new(payload.AssetAdminChange)
+		if p.AssetID, err = Uint256ParseFromBytes(obj.AssetID); err != nil {
+			return nil, err
+		}
+		if p.Admin, err = Uint160ParseFromBytes(obj.Admin); err != nil {
+			return nil, err
+		}
+		if p.Issuer, err = Uint160ParseFromBytes(obj.Issuer); err != nil {
+			return nil, err
+		}
+		return p, nil
+	case Destroy:
+		obj := new(destroyJson)
+		if err := json.Unmarshal(data, obj); err != nil {
+			return nil, err
+		}
+		p := &payload.Destroy{Amount: obj.Amount}
+		if p.AssetID, err = Uint256ParseFromBytes(obj.AssetID); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	return nil, errors.New("[Transaction], Unsupported transaction type.")
+}
+
+// parseOptionalUint160 parses a hash which is only serialized by some
+// actions of a payload, it is zero when not given.
+func parseOptionalUint160(h HexBytes) (Uint160, error) {
+	if len(h) == 0 {
+		return Uint160{}, nil
+	}
+	return Uint160ParseFromBytes(h)
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package transaction_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/core/asset"
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract"
+	"github.com/Ontology/core/contract/program"
+	tx "github.com/Ontology/core/transaction"
+	"github.com/Ontology/core/transaction/payload"
+	"github.com/Ontology/core/transaction/utxo"
+	"github.com/Ontology/crypto"
+	"github.com/Ontology/smartcontract/types"
+)
+
+func TestTransactionJson(t *testing.T) {
+	crypto.SetAlg("P256R1")
+	_, pk, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, other, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	attr := tx.NewValidUntilBlockAttribute(10)
+	txns := []*tx.Transaction{
+		{
+			TxType:     tx.TransferAsset,
+			Payload:    &payload.TransferAsset{},
+			Attributes: []*tx.TxAttribute{&attr},
+			UTXOInputs: []*utxo.UTXOTxInput{{ReferTxID: common.Uint256{2}, ReferTxOutputIndex: 1}},
+			Outputs: []*utxo.TxOutput{
+				{AssetID: common.Uint256{1}, Value: 10, ProgramHash: common.Uint160{1}, LockType: utxo.OutputLock_HEIGHT, LockValue: 20},
+			},
+			Programs: []*program.Program{{Code: []byte{1, 2}, Parameter: []byte{3}}},
+		},
+		{
+			TxType:  tx.BookKeeper,
+			Payload: &payload.BookKeeper{PubKey: &pk, Action: payload.BookKeeperAction_SUB, Cert: []byte("cert"), Issuer: &other},
+		},
+		{
+			TxType: tx.RegisterAsset,
+			Payload: &payload.RegisterAsset{
+				Asset:      &asset.Asset{Name: "token", Description: "test token", Precision: 8, AssetType: asset.Token, RecordType: asset.UTXO},
+				Amount:     1000,
+				Issuer:     &pk,
+				Controller: common.Uint160{3},
+			},
+		},
+		{
+			TxType:  tx.IssueAsset,
+			Payload: &payload.IssueAsset{},
+			Outputs: []*utxo.TxOutput{{AssetID: common.Uint256{1}, Value: 5, ProgramHash: common.Uint160{2}}},
+		},
+		{
+			TxType:  tx.Record,
+			Payload: &payload.Record{RecordType: "note", RecordData: []byte("data")},
+		},
+		{
+			TxType:  tx.DataFile,
+			Payload: &payload.DataFile{IPFSPath: "QmPath", Filename: "file.txt", Note: "note", Issuer: &pk},
+		},
+		{
+			TxType: tx.PrivacyPayload,
+			Payload: &payload.PrivacyPayload{
+				PayloadType: payload.RawPayload,
+				Payload:     payload.EncryptedPayload("encrypted"),
+				EncryptType: payload.ECDH_AES256,
+				EncryptAttr: &payload.EcdhAes256{FromPubkey: &pk, ToPubkey: &other, Nonce: []byte{1, 2, 3}},
+			},
+		},
+		{
+			TxType:  tx.Invoke,
+			Payload: &payload.InvokeCode{CodeHash: common.Uint160{4}, Code: []byte{0x51, 0x52}},
+		},
+		{
+			TxType: tx.Identity,
+			Payload: &payload.Identity{
+				ID:        []byte("did:ont:new"),
+				Action:    payload.IdentityAction_ADD_ATTRIBUTE,
+				Attribute: &payload.IdentityAttribute{Key: []byte("name"), Type: []byte("string"), Value: []byte("alice")},
+				Signer:    &pk,
+			},
+		},
+		{
+			TxType: tx.Deploy,
+			Payload: &payload.DeployCode{
+				Code:        &code.FunctionCode{Code: []byte{0x51}, ParameterTypes: []contract.ContractParameterType{contract.Integer}, ReturnType: contract.Boolean},
+				VmType:      types.NEOVM,
+				NeedStorage: true,
+				Name:        "test",
+			},
+		},
+		{
+			TxType:  tx.Freeze,
+			Payload: &payload.Freeze{Target: payload.FreezeTarget_ACCOUNT, AssetID: common.Uint256{1}, Account: common.Uint160{1}, Frozen: true},
+		},
+	}
+	for _, txn := range txns {
+		var raw bytes.Buffer
+		if err := txn.Serialize(&raw); err != nil {
+			t.Fatal(err)
+		}
+		decoded := new(tx.Transaction)
+		if err := decoded.Deserialize(bytes.NewReader(raw.Bytes())); err != nil {
+			t.Fatalf("%s: %v", txn.TxType, err)
+		}
+		obj, err := decoded.ToJson()
+		if err != nil {
+			t.Fatalf("%s: %v", txn.TxType, err)
+		}
+		data, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed := new(tx.TransactionJson)
+		if err := json.Unmarshal(data, parsed); err != nil {
+			t.Fatalf("%s: %v", txn.TxType, err)
+		}
+		built, err := parsed.ToTransaction()
+		if err != nil {
+			t.Fatalf("%s: %v", txn.TxType, err)
+		}
+		var buf bytes.Buffer
+		if err := built.Serialize(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), raw.Bytes()) {
+			t.Errorf("%s: round trip through %s", txn.TxType, data)
+		}
+	}
+}
@@ -20,25 +20,130 @@
 package transaction
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
+	"fmt"
 	"github.com/Ontology/common/serialization"
 	. "github.com/Ontology/errors"
 	"io"
-	"bytes"
 )
 
 type TransactionAttributeUsage byte
 
 const (
 	Nonce TransactionAttributeUsage = 0x00
-	Script TransactionAttributeUsage = 0x20
+	// ContractHash is the code hash of a contract the transaction refers to.
+	ContractHash TransactionAttributeUsage = 0x01
+	// ECDH02 and ECDH03 carry the X coordinate of a public key for key
+	// agreement, the usage is the prefix of its compressed encoding.
+	ECDH02 TransactionAttributeUsage = 0x02
+	ECDH03 TransactionAttributeUsage = 0x03
+	// ValidUntilBlock is the last block height the transaction can be
+	// included at, encoded as an uint32.
+	ValidUntilBlock TransactionAttributeUsage = 0x10
+	// Script is the program hash of an additional signer, it is verified
+	// with the other program hashes of the transaction.
+	Script         TransactionAttributeUsage = 0x20
+	Vote           TransactionAttributeUsage = 0x30
 	DescriptionUrl TransactionAttributeUsage = 0x81
-	Description TransactionAttributeUsage = 0x90
+	Description    TransactionAttributeUsage = 0x90
+
+	// Hash1 to Hash15 are 32 bytes correlation IDs of the integrators.
+	Hash1  TransactionAttributeUsage = 0xa1
+	Hash2  TransactionAttributeUsage = 0xa2
+	Hash3  TransactionAttributeUsage = 0xa3
+	Hash4  TransactionAttributeUsage = 0xa4
+	Hash5  TransactionAttributeUsage = 0xa5
+	Hash6  TransactionAttributeUsage = 0xa6
+	Hash7  TransactionAttributeUsage = 0xa7
+	Hash8  TransactionAttributeUsage = 0xa8
+	Hash9  TransactionAttributeUsage = 0xa9
+	Hash10 TransactionAttributeUsage = 0xaa
+	Hash11 TransactionAttributeUsage = 0xab
+	Hash12 TransactionAttributeUsage = 0xac
+	Hash13 TransactionAttributeUsage = 0xad
+	Hash14 TransactionAttributeUsage = 0xae
+	Hash15 TransactionAttributeUsage = 0xaf
+
+	Remark   TransactionAttributeUsage = 0xf0
+	Remark1  TransactionAttributeUsage = 0xf1
+	Remark2  TransactionAttributeUsage = 0xf2
+	Remark3  TransactionAttributeUsage = 0xf3
+	Remark4  TransactionAttributeUsage = 0xf4
+	Remark5  TransactionAttributeUsage = 0xf5
+	Remark6  TransactionAttributeUsage = 0xf6
+	Remark7  TransactionAttributeUsage = 0xf7
+	Remark8  TransactionAttributeUsage = 0xf8
+	Remark9  TransactionAttributeUsage = 0xf9
+	Remark10 TransactionAttributeUsage = 0xfa
+	Remark11 TransactionAttributeUsage = 0xfb
+	Remark12 TransactionAttributeUsage = 0xfc
+	Remark13 TransactionAttributeUsage = 0xfd
+	Remark14 TransactionAttributeUsage = 0xfe
+	Remark15 TransactionAttributeUsage = 0xff
+)
+
+const (
+	MaxDescriptionUrlSize = 255
+	MaxAttributeDataSize  = 65535
 )
 
 func IsValidAttributeType(usage TransactionAttributeUsage) bool {
-	return usage == Nonce || usage == Script ||
-		usage == DescriptionUrl || usage == Description
+	switch {
+	case usage == Nonce || usage == ContractHash || usage == ECDH02 || usage == ECDH03:
+	case usage == ValidUntilBlock || usage == Script || usage == Vote:
+	case usage == DescriptionUrl || usage == Description:
+	case usage >= Hash1 && usage <= Hash15:
+	case usage >= Remark:
+	default:
+		return false
+	}
+	return true
+}
+
+// AttributeDataSize returns the bounds of the data length of an usage, they
+// are equal for the usages of fixed size.
+func AttributeDataSize(usage TransactionAttributeUsage) (min, max int) {
+	switch {
+	case usage == ContractHash || usage == Script:
+		return 20, 20
+	case usage == ECDH02 || usage == ECDH03 || usage == Vote:
+		return 32, 32
+	case usage >= Hash1 && usage <= Hash15:
+		return 32, 32
+	case usage == ValidUntilBlock:
+		return 4, 4
+	case usage == DescriptionUrl:
+		return 0, MaxDescriptionUrlSize
+	}
+	return 0, MaxAttributeDataSize
+}
+
+func (u TransactionAttributeUsage) String() string {
+	switch {
+	case u >= Hash1 && u <= Hash15:
+		return fmt.Sprintf("Hash%d", u-Hash1+1)
+	case u > Remark:
+		return fmt.Sprintf("Remark%d", u-Remark)
+	}
+	if name, ok := attributeUsageNames[u]; ok {
+		return name
+	}
+	return fmt.Sprintf("TransactionAttributeUsage(0x%x)", byte(u))
+}
+
+var attributeUsageNames = map[TransactionAttributeUsage]string{
+	Nonce:           "Nonce",
+	ContractHash:    "ContractHash",
+	ECDH02:          "ECDH02",
+	ECDH03:          "ECDH03",
+	ValidUntilBlock: "ValidUntilBlock",
+	Script:          "Script",
+	Vote:            "Vote",
+	DescriptionUrl:  "DescriptionUrl",
+	Description:     "Description",
+	Remark:          "Remark",
 }
 
 type TxAttribute struct {
@@ -53,11 +158,15 @@ func NewTxAttribute(u TransactionAttributeUsage, d []byte) TxAttribute {
 	return tx
 }
 
+func NewValidUntilBlockAttribute(height uint32) TxAttribute {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, height)
+	return NewTxAttribute(ValidUntilBlock, data)
+}
+
+// GetSize returns the serialized size of the attribute.
 func (u *TxAttribute) GetSize() uint32 {
-	if u.Usage == DescriptionUrl {
-		return uint32(len([]byte{(byte(0xff))}) + len([]byte{(byte(0xff))}) + len(u.Data))
-	}
-	return 0
+	return uint32(1 + serialization.GetVarUintSize(uint64(len(u.Data))) + len(u.Data))
 }
 
 func (tx *TxAttribute) Serialize(w io.Writer) error {
@@ -95,4 +204,3 @@ func (tx *TxAttribute) ToArray() []byte {
 	tx.Serialize(bf)
 	return bf.Bytes()
 }
-
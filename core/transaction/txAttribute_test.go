@@ -36,5 +36,42 @@ func TestTxAttribute(t *testing.T) {
 	tm.Deserialize(b)
 	fmt.Println("Deserialize complete.")
 
-	fmt.Printf("Print: Usage= :0x%x,Url Date: %q\n", tm.Usage, tm.Date)
+	fmt.Printf("Print: Usage= :0x%x,Url Date: %q\n", tm.Usage, tm.Data)
+}
+
+func TestTxAttributeSize(t *testing.T) {
+	cases := []struct {
+		usage TransactionAttributeUsage
+		data  int
+		size  uint32
+	}{
+		{Script, 20, 1 + 1 + 20},
+		{Hash15, 32, 1 + 1 + 32},
+		{Remark3, 300, 1 + 3 + 300},
+	}
+	for _, c := range cases {
+		attr := NewTxAttribute(c.usage, make([]byte, c.data))
+		if attr.Size != c.size {
+			t.Errorf("%s: got size %d, expected %d", c.usage, attr.Size, c.size)
+		}
+		if n := len(attr.ToArray()); n != int(c.size) {
+			t.Errorf("%s: serialized %d bytes, expected %d", c.usage, n, c.size)
+		}
+	}
+}
+
+func TestTxAttributeUsageName(t *testing.T) {
+	cases := map[TransactionAttributeUsage]string{
+		Hash1:           "Hash1",
+		Hash15:          "Hash15",
+		Remark3:         "Remark3",
+		Script:          "Script",
+		ValidUntilBlock: "ValidUntilBlock",
+		0x40:            "TransactionAttributeUsage(0x40)",
+	}
+	for usage, name := range cases {
+		if usage.String() != name {
+			t.Errorf("usage 0x%x: got name %s, expected %s", byte(usage), usage.String(), name)
+		}
+	}
 }
@@ -21,10 +21,12 @@ package transaction
 
 import (
 	. "github.com/Ontology/common"
+	"github.com/Ontology/core/states"
 )
 
 // ILedgerStore provides func with store package.
 type ILedgerStore interface {
 	GetTransaction(hash Uint256) (*Transaction, error)
 	GetQuantityIssued(AssetId Uint256) (Fixed64, error)
+	GetAsset(hash Uint256) (*states.AssetState, error)
 }
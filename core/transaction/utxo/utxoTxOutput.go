@@ -21,14 +21,66 @@ package utxo
 
 import (
 	"github.com/Ontology/common"
+	"github.com/Ontology/common/serialization"
 	"io"
 	"bytes"
+	"errors"
+)
+
+type OutputLockType byte
+
+const (
+	OutputLock_NONE   OutputLockType = 0
+	OutputLock_HEIGHT OutputLockType = 1
+	OutputLock_TIME   OutputLockType = 2
 )
 
 type TxOutput struct {
 	AssetID     common.Uint256
 	Value       common.Fixed64
 	ProgramHash common.Uint160
+
+	// The output can not be spent before the block height, or the block
+	// timestamp, given by LockValue. Only serialized by transactions with
+	// locked outputs.
+	LockType  OutputLockType
+	LockValue uint32
+}
+
+func (o *TxOutput) IsLocked() bool {
+	return o.LockType != OutputLock_NONE
+}
+
+// IsLockReleased reports if an output with the given lock may be spent in the
+// block of the given height, while time is the timestamp of the last block.
+func IsLockReleased(lockType OutputLockType, lockValue uint32, height, time uint32) bool {
+	switch lockType {
+	case OutputLock_HEIGHT:
+		return height >= lockValue
+	case OutputLock_TIME:
+		return time >= lockValue
+	}
+	return true
+}
+
+func (o *TxOutput) SerializeLock(w io.Writer) error {
+	if err := serialization.WriteByte(w, byte(o.LockType)); err != nil {
+		return err
+	}
+	return serialization.WriteUint32(w, o.LockValue)
+}
+
+func (o *TxOutput) DeserializeLock(r io.Reader) error {
+	lockType, err := serialization.ReadByte(r)
+	if err != nil {
+		return err
+	}
+	o.LockType = OutputLockType(lockType)
+	if o.LockType > OutputLock_TIME {
+		return errors.New("[TxOutput], Invalid lock type.")
+	}
+	o.LockValue, err = serialization.ReadUint32(r)
+	return err
 }
 
 func (o *TxOutput) Serialize(w io.Writer) {
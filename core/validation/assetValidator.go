@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/states"
+	tx "github.com/Ontology/core/transaction"
+	"github.com/Ontology/core/transaction/payload"
+)
+
+// checkAssets checks the transaction moves no coins of an expired or frozen
+// asset, nor coins of an address frozen within their asset. Expired and
+// frozen assets can not be issued either.
+func checkAssets(Tx *tx.Transaction) error {
+	height := ledger.DefaultLedger.Store.GetHeight() + 1
+	assets := make(map[common.Uint256]bool)
+	checkAsset := func(assetID common.Uint256) error {
+		if _, ok := assets[assetID]; ok {
+			return nil
+		}
+		asset, err := ledger.DefaultLedger.Store.GetAsset(assetID)
+		if err != nil {
+			return errors.New("The asset not exist in local blockchain.")
+		}
+		if asset.IsExpired(height) {
+			return errors.New(fmt.Sprintf("AssetID %x is expired.", assetID))
+		}
+		if asset.IsFrozen {
+			return errors.New(fmt.Sprintf("AssetID %x is frozen.", assetID))
+		}
+		assets[assetID] = true
+		return nil
+	}
+	if Tx.TxType == tx.IssueAsset {
+		for _, output := range Tx.Outputs {
+			if err := checkAsset(output.AssetID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	reference, err := Tx.GetReference()
+	if err != nil {
+		return err
+	}
+	for _, output := range reference {
+		if err := checkAsset(output.AssetID); err != nil {
+			return err
+		}
+		frozen, err := isAccountFrozen(output.AssetID, output.ProgramHash)
+		if err != nil {
+			return err
+		}
+		if frozen {
+			return errors.New(fmt.Sprintf("Account %x is frozen within AssetID %x.", output.ProgramHash, output.AssetID))
+		}
+	}
+	return nil
+}
+
+// deductSpent deducts the value the transaction may consume from the
+// input/output results: the system fee and the amount it destroys. Whatever
+// remains is unbalanced.
+func deductSpent(Tx *tx.Transaction, results tx.TransactionResult) {
+	if fee := Tx.GetSystemFee(); fee != 0 {
+		if feeAsset, ok := tx.GetSystemFeeAssetID(); ok {
+			results[feeAsset] -= fee
+		}
+	}
+	if p, ok := Tx.Payload.(*payload.Destroy); ok {
+		results[p.AssetID] -= p.Amount
+	}
+}
+
+func checkDestroy(p *payload.Destroy) error {
+	if p.Amount <= 0 {
+		return errors.New("[checkDestroy] Invalid destroy amount.")
+	}
+	asset, err := ledger.DefaultLedger.Store.GetAsset(p.AssetID)
+	if err != nil {
+		return errors.New("[checkDestroy] The asset not exist in local blockchain.")
+	}
+	if checkAmountPrecise(p.Amount, asset.Precision) {
+		return errors.New("[checkDestroy] The precision of destroy amount is incorrect.")
+	}
+	return nil
+}
+
+func checkAssetRenew(p *payload.AssetRenew) error {
+	asset, err := ledger.DefaultLedger.Store.GetAsset(p.AssetID)
+	if err != nil {
+		return errors.New("[checkAssetRenew] The asset not exist in local blockchain.")
+	}
+	if p.Years == 0 {
+		return errors.New("[checkAssetRenew] Invalid renewal years.")
+	}
+	from := ledger.DefaultLedger.Store.GetHeight() + 1
+	if asset.Expiration > from {
+		from = asset.Expiration
+	}
+	if uint64(from)+uint64(p.Years)*uint64(states.BlocksPerYear) > math.MaxUint32 {
+		return errors.New("[checkAssetRenew] Renewal out of range.")
+	}
+	return nil
+}
+
+// checkAssetAdminChange checks the asset exists, the owner signature is
+// checked with the transaction programs.
+func checkAssetAdminChange(p *payload.AssetAdminChange) error {
+	if _, err := ledger.DefaultLedger.Store.GetAsset(p.AssetID); err != nil {
+		return errors.New("[checkAssetAdminChange] The asset not exist in local blockchain.")
+	}
+	return nil
+}
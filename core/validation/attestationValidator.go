@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package validation
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/transaction/payload"
+	"github.com/Ontology/crypto"
+)
+
+// checkAttestation checks the issuer signed the attestation with a key of its
+// DDO. A claim is committed once, about a registered subject, and may only be
+// revoked by its issuer.
+func checkAttestation(p *payload.Attestation) error {
+	issuer, err := ledger.DefaultLedger.GetIdentity(p.IssuerID)
+	if err != nil {
+		return err
+	}
+	if issuer == nil {
+		return errors.New("[checkAttestation] Issuer ONT ID not registered.")
+	}
+	if issuer.KeyIndex(p.Signer) < 0 {
+		return errors.New("[checkAttestation] Signer is not a key of the issuer.")
+	}
+	if err := crypto.Verify(*p.Signer, p.SignedData(), p.Signature); err != nil {
+		return errors.New("[checkAttestation] Invalid issuer signature.")
+	}
+	claim, err := ledger.DefaultLedger.Store.GetClaim(p.ClaimHash)
+	if err != nil {
+		return err
+	}
+	switch p.Action {
+	case payload.AttestationAction_COMMIT:
+		if claim != nil {
+			return errors.New("[checkAttestation] Claim already committed.")
+		}
+		subject, err := ledger.DefaultLedger.GetIdentity(p.SubjectID)
+		if err != nil {
+			return err
+		}
+		if subject == nil {
+			return errors.New("[checkAttestation] Subject ONT ID not registered.")
+		}
+	case payload.AttestationAction_REVOKE:
+		if claim == nil {
+			return errors.New("[checkAttestation] Claim not committed.")
+		}
+		if !bytes.Equal(claim.IssuerID, p.IssuerID) {
+			return errors.New("[checkAttestation] Claim can only be revoked by its issuer.")
+		}
+		if claim.Revoked {
+			return errors.New("[checkAttestation] Claim already revoked.")
+		}
+	}
+	return nil
+}
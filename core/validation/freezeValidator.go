@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package validation
+
+import (
+	"errors"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/transaction/payload"
+)
+
+// checkFreeze checks a freeze changes the state of a registered asset. The
+// admin signature is checked with the transaction programs.
+func checkFreeze(p *payload.Freeze) error {
+	asset, err := ledger.DefaultLedger.Store.GetAsset(p.AssetID)
+	if err != nil {
+		return errors.New("[checkFreeze] The asset not exist in local blockchain.")
+	}
+	if p.Target == payload.FreezeTarget_ASSET {
+		if asset.IsFrozen == p.Frozen {
+			return errors.New("[checkFreeze] The asset is already in the state.")
+		}
+		return nil
+	}
+	frozen, err := isAccountFrozen(p.AssetID, p.Account)
+	if err != nil {
+		return err
+	}
+	if frozen == p.Frozen {
+		return errors.New("[checkFreeze] The account is already in the state.")
+	}
+	return nil
+}
+
+func isAccountFrozen(assetID common.Uint256, programHash common.Uint160) (bool, error) {
+	state, err := ledger.DefaultLedger.Store.GetFrozenAccount(assetID, programHash)
+	if err != nil {
+		return false, err
+	}
+	return state != nil && state.IsFrozen, nil
+}
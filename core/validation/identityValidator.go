@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package validation
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/states"
+	"github.com/Ontology/core/transaction/payload"
+)
+
+// IdentityPrefix starts every ONT ID.
+const IdentityPrefix = "did:ont:"
+
+// checkIdentity checks an identity transaction against the current DDO. The
+// transaction is signed by the owner key for a register, else by Signer,
+// which must be a key of the DDO, or by the recovery address of the DDO.
+// The signature itself is checked with the transaction programs.
+func checkIdentity(p *payload.Identity) error {
+	if len(p.ID) <= len(IdentityPrefix) || len(p.ID) > payload.MaxIdentityFieldSize ||
+		!bytes.HasPrefix(p.ID, []byte(IdentityPrefix)) {
+		return errors.New("[checkIdentity] Invalid ONT ID.")
+	}
+	ddo, err := ledger.DefaultLedger.GetIdentity(p.ID)
+	if err != nil {
+		return err
+	}
+	if p.Action == payload.IdentityAction_REGISTER {
+		if ddo != nil {
+			return errors.New("[checkIdentity] ONT ID already registered.")
+		}
+		return nil
+	}
+	if ddo == nil {
+		return errors.New("[checkIdentity] ONT ID not registered.")
+	}
+	if err := checkIdentitySigner(p, ddo); err != nil {
+		return err
+	}
+	switch p.Action {
+	case payload.IdentityAction_ADD_KEY:
+		if ddo.KeyIndex(p.PubKey) >= 0 {
+			return errors.New("[checkIdentity] Public key already added.")
+		}
+		if len(ddo.PubKeys) >= states.MaxIdentityKeys {
+			return errors.New("[checkIdentity] Too many public keys.")
+		}
+	case payload.IdentityAction_REMOVE_KEY:
+		if ddo.KeyIndex(p.PubKey) < 0 {
+			return errors.New("[checkIdentity] Public key not found.")
+		}
+		if len(ddo.PubKeys) == 1 {
+			return errors.New("[checkIdentity] The last public key can not be removed.")
+		}
+	case payload.IdentityAction_ADD_ATTRIBUTE, payload.IdentityAction_UPDATE_ATTRIBUTE:
+		a := p.Attribute
+		if len(a.Key) == 0 || len(a.Key) > payload.MaxIdentityFieldSize || len(a.Type) > payload.MaxIdentityFieldSize ||
+			len(a.Value) > payload.MaxIdentityFieldSize {
+			return errors.New("[checkIdentity] Invalid attribute.")
+		}
+		exist := ddo.AttributeIndex(a.Key) >= 0
+		if p.Action == payload.IdentityAction_ADD_ATTRIBUTE {
+			if exist {
+				return errors.New("[checkIdentity] Attribute already added.")
+			}
+			if len(ddo.Attributes) >= states.MaxIdentityAttributes {
+				return errors.New("[checkIdentity] Too many attributes.")
+			}
+		} else if !exist {
+			return errors.New("[checkIdentity] Attribute not found.")
+		}
+	case payload.IdentityAction_REMOVE_ATTRIBUTE:
+		if ddo.AttributeIndex(p.Attribute.Key) < 0 {
+			return errors.New("[checkIdentity] Attribute not found.")
+		}
+	}
+	return nil
+}
+
+// checkIdentitySigner checks who may sign the change. The recovery address
+// manages keys and replaces itself, attributes are changed with a key. Once
+// set, the recovery address can only be changed by itself.
+func checkIdentitySigner(p *payload.Identity, ddo *states.IdentityState) error {
+	if p.Signer != nil {
+		if ddo.KeyIndex(p.Signer) < 0 {
+			return errors.New("[checkIdentity] Signer is not a key of the ONT ID.")
+		}
+		if p.Action == payload.IdentityAction_SET_RECOVERY && ddo.HasRecovery {
+			return errors.New("[checkIdentity] Recovery address can only be changed by itself.")
+		}
+		return nil
+	}
+	if !ddo.HasRecovery || ddo.Recovery != p.Recoverer {
+		return errors.New("[checkIdentity] Signer is not the recovery address of the ONT ID.")
+	}
+	switch p.Action {
+	case payload.IdentityAction_ADD_KEY, payload.IdentityAction_REMOVE_KEY, payload.IdentityAction_SET_RECOVERY:
+		return nil
+	}
+	return errors.New("[checkIdentity] Recovery address can only change keys.")
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Ontology/core/ledger"
+	tx "github.com/Ontology/core/transaction"
+)
+
+// checkOutputLocks checks the transaction spends no output before its lock is
+// released. Height locks are checked against the next block, time locks
+// against the timestamp of the current block.
+func checkOutputLocks(Tx *tx.Transaction) error {
+	reference, err := Tx.GetReference()
+	if err != nil {
+		return err
+	}
+	for input, output := range reference {
+		locked, err := ledger.DefaultLedger.IsLocked(output.LockType, output.LockValue)
+		if err != nil {
+			return err
+		}
+		if locked {
+			return errors.New(fmt.Sprintf("Output %x:%d is locked.", input.ReferTxID, input.ReferTxOutputIndex))
+		}
+	}
+	return nil
+}
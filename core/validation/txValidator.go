@@ -21,6 +21,7 @@ package validation
 
 import (
 	"github.com/Ontology/common"
+	"github.com/Ontology/common/config"
 	"github.com/Ontology/common/log"
 	"github.com/Ontology/core/asset"
 	"github.com/Ontology/core/ledger"
@@ -74,6 +75,10 @@ func VerifyTransaction(Tx *tx.Transaction) ErrCode {
 func VerifyTransactionWithBlock(TxPool []*tx.Transaction) error {
 	//initial
 	txnlist := make(map[common.Uint256]*tx.Transaction, 0)
+	identities := make(map[string]bool)
+	claims := make(map[common.Uint256]bool)
+	freezes := make(map[string]bool)
+	adminChanges := make(map[common.Uint256]bool)
 	var txPoolInputs []string
 	//sum all inputs in TxPool
 	for _, Tx := range TxPool {
@@ -95,6 +100,36 @@ func VerifyTransactionWithBlock(TxPool []*tx.Transaction) error {
 		}
 		//3.check issue amount
 		switch txn.TxType {
+		case tx.Identity:
+			// changes of a DDO are validated against the DDO of the last block
+			id := string(txn.Payload.(*payload.Identity).ID)
+			if identities[id] {
+				return errors.New("[VerifyTransactionWithBlock], duplicate identity transaction exist in block.")
+			}
+			identities[id] = true
+		case tx.Attestation:
+			hash := txn.Payload.(*payload.Attestation).ClaimHash
+			if claims[hash] {
+				return errors.New("[VerifyTransactionWithBlock], duplicate attestation exist in block.")
+			}
+			claims[hash] = true
+		case tx.Freeze:
+			// a freeze and an unfreeze of the same target are not ordered in a block
+			p := txn.Payload.(*payload.Freeze)
+			target := string(p.AssetID.ToArray())
+			if p.Target == payload.FreezeTarget_ACCOUNT {
+				target += string(p.Account.ToArray())
+			}
+			if freezes[target] {
+				return errors.New("[VerifyTransactionWithBlock], duplicate freeze transaction exist in block.")
+			}
+			freezes[target] = true
+		case tx.AssetAdminChange:
+			assetID := txn.Payload.(*payload.AssetAdminChange).AssetID
+			if adminChanges[assetID] {
+				return errors.New("[VerifyTransactionWithBlock], duplicate asset admin change exist in block.")
+			}
+			adminChanges[assetID] = true
 		case tx.IssueAsset:
 			//TODO: use delta mode to improve performance
 			results := txn.GetMergedAssetIDValueFromOutputs()
@@ -155,6 +190,14 @@ func VerifyTransactionWithLedger(Tx *tx.Transaction, ledger *ledger.Ledger) ErrC
 		log.Info("[VerifyTransactionWithLedger] duplicate transaction check faild.")
 		return ErrTxHashDuplicate
 	}
+	if err := CheckNetworkMagic(Tx, ledger.Store.GetHeight()+1); err != nil {
+		log.Info("[VerifyTransactionWithLedger] ", err)
+		return ErrNetworkMagic
+	}
+	if err := CheckTransactionExpiry(Tx, ledger.Store.GetHeight()+1); err != nil {
+		log.Info("[VerifyTransactionWithLedger] ", err)
+		return ErrTxExpired
+	}
 	return ErrNoError
 }
 
@@ -222,16 +265,23 @@ func CheckTransactionBalance(Tx *tx.Transaction) error {
 			return errors.New("Invalide transaction UTXO output.")
 		}
 	}
+	if err := checkAssets(Tx); err != nil {
+		return err
+	}
 	if Tx.TxType == tx.IssueAsset {
 		if len(Tx.UTXOInputs) > 0 {
 			return errors.New("Invalide Issue transaction.")
 		}
 		return nil
 	}
+	if err := checkOutputLocks(Tx); err != nil {
+		return err
+	}
 	results, err := Tx.GetTransactionResults()
 	if err != nil {
 		return err
 	}
+	deductSpent(Tx, results)
 	for k, v := range results {
 		if v != 0 {
 			log.Debug(fmt.Sprintf("AssetID %x in Transfer transactions %x , Input/output UTXO not equal.", k, Tx.Hash()))
@@ -242,7 +292,49 @@ func CheckTransactionBalance(Tx *tx.Transaction) error {
 }
 
 func CheckAttributeProgram(Tx *tx.Transaction) error {
-	//TODO: implement CheckAttributeProgram
+	validUntil := 0
+	for _, attr := range Tx.Attributes {
+		if !tx.IsValidAttributeType(attr.Usage) {
+			return errors.New(fmt.Sprintf("Unsupported attribute usage %s.", attr.Usage))
+		}
+		min, max := tx.AttributeDataSize(attr.Usage)
+		if len(attr.Data) < min || len(attr.Data) > max {
+			return errors.New(fmt.Sprintf("Invalid %s attribute size %d.", attr.Usage, len(attr.Data)))
+		}
+		if attr.Usage == tx.ValidUntilBlock {
+			validUntil++
+		}
+	}
+	if validUntil > 1 {
+		return errors.New("Duplicated ValidUntilBlock attribute.")
+	}
+	return nil
+}
+
+// CheckNetworkMagic checks the transaction is bound to this network, to be
+// included in the block of the given height. From MagicHeight on every
+// transaction but the bookkeeping ones, built by the bookkeepers of the block,
+// must carry the network magic.
+func CheckNetworkMagic(Tx *tx.Transaction, height uint32) error {
+	if !Tx.HasNetworkMagic() {
+		magicHeight := config.Parameters.MagicHeight
+		if Tx.TxType != tx.BookKeeping && magicHeight > 0 && height >= magicHeight {
+			return errors.New(fmt.Sprintf("Transaction without network magic at height %d.", height))
+		}
+		return nil
+	}
+	if Tx.NetworkMagic != uint32(config.Parameters.Magic) {
+		return errors.New(fmt.Sprintf("Transaction of network %d.", Tx.NetworkMagic))
+	}
+	return nil
+}
+
+// CheckTransactionExpiry checks the transaction can still be included in the
+// block of the given height.
+func CheckTransactionExpiry(Tx *tx.Transaction, height uint32) error {
+	if until, ok := Tx.GetValidUntilBlock(); ok && height > until {
+		return errors.New(fmt.Sprintf("Transaction %x expired at height %d.", Tx.Hash(), until))
+	}
 	return nil
 }
 
@@ -298,6 +390,18 @@ func CheckTransactionPayload(Tx *tx.Transaction) error {
 	case *payload.DeployCode:
 	case *payload.InvokeCode:
 	case *payload.DataFile:
+	case *payload.Identity:
+		return checkIdentity(pld)
+	case *payload.Attestation:
+		return checkAttestation(pld)
+	case *payload.Freeze:
+		return checkFreeze(pld)
+	case *payload.AssetRenew:
+		return checkAssetRenew(pld)
+	case *payload.AssetAdminChange:
+		return checkAssetAdminChange(pld)
+	case *payload.Destroy:
+		return checkDestroy(pld)
 	default:
 		return errors.New("[txValidator],invalidate transaction payload type.")
 	}
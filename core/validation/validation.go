@@ -21,8 +21,13 @@ package validation
 
 import (
 	"errors"
+	"fmt"
+
 	. "github.com/Ontology/common"
+	"github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/contract/program"
 	sig "github.com/Ontology/core/signature"
+	"github.com/Ontology/core/states"
 	"github.com/Ontology/crypto"
 	. "github.com/Ontology/errors"
 	vm "github.com/Ontology/vm/neovm"
@@ -31,6 +36,74 @@ import (
 	"github.com/Ontology/smartcontract/types"
 )
 
+// MaxVerificationSteps bounds the instructions a contract account may run to
+// verify a witness, so that mempool checks can not be stalled.
+const MaxVerificationSteps = 100000
+
+// ledgerCodeTable resolves APPCALL during verification to deployed contracts,
+// with the call permissions they were deployed with.
+type ledgerCodeTable struct{}
+
+func (ledgerCodeTable) getContract(codeHash []byte) (*states.ContractState, error) {
+	hash, err := Uint160ParseFromBytes(codeHash)
+	if err != nil {
+		return nil, err
+	}
+	c, err := ledger.DefaultLedger.Store.GetContract(hash)
+	if err != nil {
+		return nil, NewDetailErr(err, ErrNoCode, fmt.Sprintf("[GetCode] Contract %x not found.", codeHash))
+	}
+	if c == nil || c.Code == nil {
+		return nil, errors.New(fmt.Sprintf("[GetCode] Contract %x has no code.", codeHash))
+	}
+	return c, nil
+}
+
+func (table ledgerCodeTable) GetCode(codeHash []byte) ([]byte, error) {
+	c, err := table.getContract(codeHash)
+	if err != nil {
+		return nil, err
+	}
+	return c.Code.Code, nil
+}
+
+func (table ledgerCodeTable) CanDynamicInvoke(codeHash []byte) bool {
+	c, err := table.getContract(codeHash)
+	return err == nil && c.DynamicInvoke
+}
+
+func (table ledgerCodeTable) HasReentrancyGuard(codeHash []byte) bool {
+	c, err := table.getContract(codeHash)
+	return err == nil && c.ReentrancyGuard
+}
+
+// verificationCode returns the code a witness for hash runs. A program with
+// code is a redeem script which must hash to hash. A program without code
+// spends from a contract account: the contract deployed at hash verifies it.
+func verificationCode(hash Uint160, p *program.Program) ([]byte, error) {
+	if len(p.Code) > 0 {
+		temp, _ := ToCodeHash(p.Code)
+		if hash != temp {
+			return nil, errors.New("The data hashes is different with corresponding program code.")
+		}
+		return p.Code, nil
+	}
+	if ledger.DefaultLedger == nil || ledger.DefaultLedger.Store == nil {
+		return nil, errors.New("[VerifySignableData] No ledger to look up the contract account.")
+	}
+	c, err := ledger.DefaultLedger.Store.GetContract(hash)
+	if err != nil {
+		return nil, NewDetailErr(err, ErrNoCode, fmt.Sprintf("[VerifySignableData] Contract account %x not found.", hash.ToArray()))
+	}
+	if c == nil || c.Code == nil {
+		return nil, errors.New("[VerifySignableData] Contract account has no code.")
+	}
+	if c.VmType != types.NEOVM {
+		return nil, errors.New("[VerifySignableData] Contract account must be a NeoVM contract.")
+	}
+	return c.Code.Code, nil
+}
+
 func VerifySignableData(signableData sig.SignableData) (bool, error) {
 
 	hashes, err := signableData.GetProgramHashes()
@@ -46,16 +119,21 @@ func VerifySignableData(signableData sig.SignableData) (bool, error) {
 
 	programs = signableData.GetPrograms()
 	for i := 0; i < len(programs); i++ {
-		temp, _ := ToCodeHash(programs[i].Code)
-		if hashes[i] != temp {
-			return false, errors.New("The data hashes is different with corresponding program code.")
+		code, err := verificationCode(hashes[i], programs[i])
+		if err != nil {
+			return false, err
 		}
 		//execute program on VM
 		var cryptos interfaces.ICrypto
 		cryptos = new(vm.ECDsaCrypto)
 		stateReader := service.NewStateReader(types.Verification)
-		se := vm.NewExecutionEngine(signableData, cryptos, nil, stateReader)
-		se.LoadCode(programs[i].Code, false)
+		var table interfaces.ICodeTable
+		if len(programs[i].Code) == 0 {
+			table = ledgerCodeTable{}
+		}
+		se := vm.NewExecutionEngine(signableData, cryptos, table, stateReader)
+		se.SetMaxSteps(MaxVerificationSteps)
+		se.LoadCode(code, false)
 		se.LoadCode(programs[i].Parameter, true)
 		se.Execute()
 
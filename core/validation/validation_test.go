@@ -0,0 +1,665 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package validation
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/config"
+	"github.com/Ontology/common/log"
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract/program"
+	"github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/states"
+	tx "github.com/Ontology/core/transaction"
+	"github.com/Ontology/core/transaction/payload"
+	"github.com/Ontology/core/transaction/utxo"
+	"github.com/Ontology/crypto"
+	. "github.com/Ontology/errors"
+	"github.com/Ontology/smartcontract/types"
+	vm "github.com/Ontology/vm/neovm"
+)
+
+func init() {
+	log.Init()
+}
+
+type contractStore struct {
+	ledger.ILedgerStore
+	contracts  map[common.Uint160]*states.ContractState
+	identities map[string]*states.IdentityState
+	claims     map[common.Uint256]*states.ClaimState
+	assets     map[common.Uint256]*states.AssetState
+	frozen     map[common.Uint160]bool
+	txs        map[common.Uint256]*tx.Transaction
+	height     uint32
+}
+
+func (s *contractStore) GetHeight() uint32 {
+	return s.height
+}
+
+func (s *contractStore) GetContract(hash common.Uint160) (*states.ContractState, error) {
+	c, ok := s.contracts[hash]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return c, nil
+}
+
+func (s *contractStore) GetIdentity(id []byte) ([]byte, error) {
+	ddo, ok := s.identities[string(id)]
+	if !ok {
+		return nil, nil
+	}
+	buf := new(bytes.Buffer)
+	if err := ddo.Serialize(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *contractStore) GetClaim(hash common.Uint256) (*states.ClaimState, error) {
+	return s.claims[hash], nil
+}
+
+func (s *contractStore) GetAsset(hash common.Uint256) (*states.AssetState, error) {
+	asset, ok := s.assets[hash]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return asset, nil
+}
+
+func (s *contractStore) GetFrozenAccount(assetId common.Uint256, programHash common.Uint160) (*states.FrozenAccountState, error) {
+	if !s.frozen[programHash] {
+		return nil, nil
+	}
+	return &states.FrozenAccountState{AssetId: assetId, ProgramHash: programHash, IsFrozen: true}, nil
+}
+
+func (s *contractStore) GetTransaction(hash common.Uint256) (*tx.Transaction, error) {
+	t, ok := s.txs[hash]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return t, nil
+}
+
+type signable struct {
+	hashes   []common.Uint160
+	programs []*program.Program
+}
+
+func (s *signable) GetMessage() []byte                          { return []byte("message") }
+func (s *signable) ToArray() []byte                             { return []byte("message") }
+func (s *signable) GetProgramHashes() ([]common.Uint160, error) { return s.hashes, nil }
+func (s *signable) SetPrograms(programs []*program.Program)     { s.programs = programs }
+func (s *signable) GetPrograms() []*program.Program             { return s.programs }
+func (s *signable) SerializeUnsigned(w io.Writer) error         { return nil }
+
+func deploy(store *contractStore, avm []byte) common.Uint160 {
+	hash, _ := common.ToCodeHash(avm)
+	store.contracts[hash] = &states.ContractState{
+		Code:   &code.FunctionCode{Code: avm},
+		VmType: types.NEOVM,
+	}
+	return hash
+}
+
+func TestVerifyContractAccount(t *testing.T) {
+	store := &contractStore{contracts: make(map[common.Uint160]*states.ContractState)}
+	ledger.DefaultLedger = &ledger.Ledger{Store: store}
+	defer func() { ledger.DefaultLedger = nil }()
+
+	secret := []byte("secret")
+	// the witness must push the secret
+	escrow := deploy(store, append(append([]byte{byte(len(secret))}, secret...), byte(vm.EQUAL)))
+	loop := deploy(store, []byte{byte(vm.JMP), 0, 0})
+
+	push := func(b []byte) []byte { return append([]byte{byte(len(b))}, b...) }
+	cases := []struct {
+		name  string
+		hash  common.Uint160
+		param []byte
+		ok    bool
+	}{
+		{"valid", escrow, push(secret), true},
+		{"wrong secret", escrow, push([]byte("guess")), false},
+		{"not deployed", common.Uint160{1}, push(secret), false},
+		{"endless loop", loop, push(secret), false},
+	}
+	for _, c := range cases {
+		data := &signable{
+			hashes:   []common.Uint160{c.hash},
+			programs: []*program.Program{{Parameter: c.param}},
+		}
+		ok, err := VerifySignableData(data)
+		if ok != c.ok || (err == nil) != c.ok {
+			t.Errorf("%s: got %v, %v", c.name, ok, err)
+		}
+	}
+}
+
+func TestVerifyContractAccountTransaction(t *testing.T) {
+	asset := common.Uint256{1}
+	holder := common.Uint160{1}
+	store := &contractStore{
+		contracts: make(map[common.Uint160]*states.ContractState),
+		assets: map[common.Uint256]*states.AssetState{
+			asset: {AssetId: asset, Expiration: 100, Precision: 8},
+		},
+		txs: make(map[common.Uint256]*tx.Transaction),
+	}
+	ledger.DefaultLedger = &ledger.Ledger{Store: store}
+	tx.TxStore = store
+	defer func() { ledger.DefaultLedger, tx.TxStore = nil, nil }()
+
+	secret := []byte("secret")
+	push := func(b []byte) []byte { return append([]byte{byte(len(b))}, b...) }
+	escrow := deploy(store, append(push(secret), byte(vm.EQUAL)))
+	// proxies call the escrow with a hash pushed at run time
+	dynamicCall := append([]byte{byte(vm.APPCALL)}, make([]byte, 20)...)
+	proxyCode := append(push(escrow.ToArray()), dynamicCall...)
+	proxy := deploy(store, proxyCode)
+	dynamicProxy := deploy(store, append(proxyCode, byte(vm.NOP)))
+	store.contracts[dynamicProxy].DynamicInvoke = true
+	// routers call the contract whose hash the witness pushed last, and are
+	// called back by the contract before it reaches the escrow
+	router := deploy(store, dynamicCall)
+	guardedRouter := deploy(store, append(dynamicCall, byte(vm.NOP)))
+	store.contracts[router].DynamicInvoke = true
+	store.contracts[guardedRouter].DynamicInvoke = true
+	store.contracts[guardedRouter].ReentrancyGuard = true
+	routed := func(router common.Uint160) []byte {
+		callBack := deploy(store, append([]byte{byte(vm.APPCALL)}, router.ToArray()...))
+		param := append(push(secret), push(escrow.ToArray())...)
+		return append(param, push(callBack.ToArray())...)
+	}
+
+	spend := func(account common.Uint160, param []byte) *tx.Transaction {
+		funding := common.Uint256{byte(len(store.txs) + 2)}
+		store.txs[funding] = &tx.Transaction{Outputs: []*utxo.TxOutput{{AssetID: asset, Value: 10, ProgramHash: account}}}
+		return &tx.Transaction{
+			TxType:     tx.TransferAsset,
+			Payload:    &payload.TransferAsset{},
+			UTXOInputs: []*utxo.UTXOTxInput{{ReferTxID: funding}},
+			Outputs:    []*utxo.TxOutput{{AssetID: asset, Value: 10, ProgramHash: holder}},
+			Programs:   []*program.Program{{Parameter: param}},
+		}
+	}
+	cases := []struct {
+		name string
+		tx   *tx.Transaction
+		ok   bool
+	}{
+		{"valid", spend(escrow, push(secret)), true},
+		{"wrong secret", spend(escrow, push([]byte("guess"))), false},
+		{"dynamic call", spend(dynamicProxy, push(secret)), true},
+		{"dynamic call not allowed", spend(proxy, push(secret)), false},
+		{"reentry", spend(router, routed(router)), true},
+		{"guarded reentry", spend(guardedRouter, routed(guardedRouter)), false},
+	}
+	for _, c := range cases {
+		if code := VerifyTransaction(c.tx); (code == ErrNoError) != c.ok {
+			t.Errorf("%s: got %v", c.name, code)
+		}
+	}
+}
+
+func TestCheckIdentity(t *testing.T) {
+	crypto.SetAlg("P256R1")
+	keys := make([]*crypto.PubKey, 3)
+	for i := range keys {
+		_, pk, err := crypto.GenKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = &pk
+	}
+	owner, other, added := keys[0], keys[1], keys[2]
+	id := []byte("did:ont:TA7yZ9Ni8PD1gkDJLVzDeyPw5JrEkNy1cB")
+	recovery := common.Uint160{9}
+	store := &contractStore{identities: map[string]*states.IdentityState{
+		string(id): {ID: id, PubKeys: []*crypto.PubKey{owner}, HasRecovery: true, Recovery: recovery},
+	}}
+	ledger.DefaultLedger = &ledger.Ledger{Store: store}
+	defer func() { ledger.DefaultLedger = nil }()
+
+	attr := &payload.IdentityAttribute{Key: []byte("name"), Type: []byte("string"), Value: []byte("alice")}
+	cases := []struct {
+		name string
+		p    *payload.Identity
+		ok   bool
+	}{
+		{"register new", &payload.Identity{ID: []byte("did:ont:new"), Action: payload.IdentityAction_REGISTER, PubKey: other}, true},
+		{"register taken", &payload.Identity{ID: id, Action: payload.IdentityAction_REGISTER, PubKey: other}, false},
+		{"register bad id", &payload.Identity{ID: []byte("ont:new"), Action: payload.IdentityAction_REGISTER, PubKey: other}, false},
+		{"add key", &payload.Identity{ID: id, Action: payload.IdentityAction_ADD_KEY, PubKey: added, Signer: owner}, true},
+		{"add key by stranger", &payload.Identity{ID: id, Action: payload.IdentityAction_ADD_KEY, PubKey: added, Signer: other}, false},
+		{"add key by recovery", &payload.Identity{ID: id, Action: payload.IdentityAction_ADD_KEY, PubKey: added, Recoverer: recovery}, true},
+		{"remove last key", &payload.Identity{ID: id, Action: payload.IdentityAction_REMOVE_KEY, PubKey: owner, Recoverer: recovery}, false},
+		{"add attribute", &payload.Identity{ID: id, Action: payload.IdentityAction_ADD_ATTRIBUTE, Attribute: attr, Signer: owner}, true},
+		{"add attribute by recovery", &payload.Identity{ID: id, Action: payload.IdentityAction_ADD_ATTRIBUTE, Attribute: attr, Recoverer: recovery}, false},
+		{"update missing attribute", &payload.Identity{ID: id, Action: payload.IdentityAction_UPDATE_ATTRIBUTE, Attribute: attr, Signer: owner}, false},
+		{"replace recovery by key", &payload.Identity{ID: id, Action: payload.IdentityAction_SET_RECOVERY, Recovery: common.Uint160{1}, Signer: owner}, false},
+		{"replace recovery", &payload.Identity{ID: id, Action: payload.IdentityAction_SET_RECOVERY, Recovery: common.Uint160{1}, Recoverer: recovery}, true},
+	}
+	for _, c := range cases {
+		if err := checkIdentity(c.p); (err == nil) != c.ok {
+			t.Errorf("%s: got %v", c.name, err)
+		}
+	}
+}
+
+func TestCheckAttestation(t *testing.T) {
+	crypto.SetAlg("P256R1")
+	priv, pk, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, other, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := []byte("did:ont:issuer")
+	subject := []byte("did:ont:subject")
+	committed, revoked := common.Uint256{1}, common.Uint256{2}
+	store := &contractStore{
+		identities: map[string]*states.IdentityState{
+			string(issuer):  {ID: issuer, PubKeys: []*crypto.PubKey{&pk}},
+			string(subject): {ID: subject, PubKeys: []*crypto.PubKey{&other}},
+		},
+		claims: map[common.Uint256]*states.ClaimState{
+			committed: {ClaimHash: committed, IssuerID: issuer, SubjectID: subject},
+			revoked:   {ClaimHash: revoked, IssuerID: issuer, SubjectID: subject, Revoked: true},
+		},
+	}
+	ledger.DefaultLedger = &ledger.Ledger{Store: store}
+	defer func() { ledger.DefaultLedger = nil }()
+
+	attest := func(action payload.AttestationAction, hash common.Uint256, issuerID []byte, signer *crypto.PubKey) *payload.Attestation {
+		p := &payload.Attestation{Action: action, ClaimHash: hash, IssuerID: issuerID, SubjectID: subject, Signer: signer}
+		p.Signature, err = crypto.Sign(priv, p.SignedData())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	tampered := attest(payload.AttestationAction_COMMIT, common.Uint256{3}, issuer, &pk)
+	tampered.Expiry = 100
+	cases := []struct {
+		name string
+		p    *payload.Attestation
+		ok   bool
+	}{
+		{"commit", attest(payload.AttestationAction_COMMIT, common.Uint256{3}, issuer, &pk), true},
+		{"commit twice", attest(payload.AttestationAction_COMMIT, committed, issuer, &pk), false},
+		{"commit tampered", tampered, false},
+		{"commit by stranger", attest(payload.AttestationAction_COMMIT, common.Uint256{3}, issuer, &other), false},
+		{"commit unknown issuer", attest(payload.AttestationAction_COMMIT, common.Uint256{3}, []byte("did:ont:nobody"), &pk), false},
+		{"revoke", attest(payload.AttestationAction_REVOKE, committed, issuer, &pk), true},
+		{"revoke unknown", attest(payload.AttestationAction_REVOKE, common.Uint256{3}, issuer, &pk), false},
+		{"revoke twice", attest(payload.AttestationAction_REVOKE, revoked, issuer, &pk), false},
+	}
+	for _, c := range cases {
+		if err := checkAttestation(c.p); (err == nil) != c.ok {
+			t.Errorf("%s: got %v", c.name, err)
+		}
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	active, frozenAsset := common.Uint256{1}, common.Uint256{2}
+	holder, frozenHolder := common.Uint160{1}, common.Uint160{2}
+	funding := common.Uint256{3}
+	store := &contractStore{
+		assets: map[common.Uint256]*states.AssetState{
+			active:      {AssetId: active, Expiration: 100},
+			frozenAsset: {AssetId: frozenAsset, Expiration: 100, IsFrozen: true},
+		},
+		frozen: map[common.Uint160]bool{frozenHolder: true},
+		txs: map[common.Uint256]*tx.Transaction{
+			funding: {Outputs: []*utxo.TxOutput{
+				{AssetID: active, Value: 1, ProgramHash: holder},
+				{AssetID: active, Value: 1, ProgramHash: frozenHolder},
+				{AssetID: frozenAsset, Value: 1, ProgramHash: holder},
+			}},
+		},
+	}
+	ledger.DefaultLedger = &ledger.Ledger{Store: store}
+	tx.TxStore = store
+	defer func() { ledger.DefaultLedger, tx.TxStore = nil, nil }()
+
+	freezes := []struct {
+		name string
+		p    *payload.Freeze
+		ok   bool
+	}{
+		{"freeze asset", &payload.Freeze{Target: payload.FreezeTarget_ASSET, AssetID: active, Frozen: true}, true},
+		{"freeze frozen asset", &payload.Freeze{Target: payload.FreezeTarget_ASSET, AssetID: frozenAsset, Frozen: true}, false},
+		{"unfreeze asset", &payload.Freeze{Target: payload.FreezeTarget_ASSET, AssetID: frozenAsset}, true},
+		{"freeze unknown asset", &payload.Freeze{Target: payload.FreezeTarget_ASSET, AssetID: funding, Frozen: true}, false},
+		{"freeze account", &payload.Freeze{Target: payload.FreezeTarget_ACCOUNT, AssetID: active, Account: holder, Frozen: true}, true},
+		{"unfreeze account", &payload.Freeze{Target: payload.FreezeTarget_ACCOUNT, AssetID: active, Account: frozenHolder}, true},
+		{"unfreeze active account", &payload.Freeze{Target: payload.FreezeTarget_ACCOUNT, AssetID: active, Account: holder}, false},
+	}
+	for _, c := range freezes {
+		if err := checkFreeze(c.p); (err == nil) != c.ok {
+			t.Errorf("%s: got %v", c.name, err)
+		}
+	}
+
+	spend := func(index uint16) *tx.Transaction {
+		return &tx.Transaction{TxType: tx.TransferAsset, UTXOInputs: []*utxo.UTXOTxInput{{ReferTxID: funding, ReferTxOutputIndex: index}}}
+	}
+	transfers := []struct {
+		name string
+		tx   *tx.Transaction
+		ok   bool
+	}{
+		{"spend active", spend(0), true},
+		{"spend frozen account", spend(1), false},
+		{"spend frozen asset", spend(2), false},
+		{"issue frozen asset", &tx.Transaction{TxType: tx.IssueAsset, Outputs: []*utxo.TxOutput{{AssetID: frozenAsset, Value: 1}}}, false},
+	}
+	for _, c := range transfers {
+		if err := checkAssets(c.tx); (err == nil) != c.ok {
+			t.Errorf("%s: got %v", c.name, err)
+		}
+	}
+}
+
+func TestAssetRenew(t *testing.T) {
+	active, expired, fee := common.Uint256{1}, common.Uint256{2}, common.Uint256{3}
+	holder := common.Uint160{1}
+	funding := common.Uint256{4}
+	store := &contractStore{
+		assets: map[common.Uint256]*states.AssetState{
+			active:  {AssetId: active, Expiration: 100},
+			expired: {AssetId: expired, Expiration: 9},
+			fee:     {AssetId: fee, Expiration: 100},
+		},
+		txs: map[common.Uint256]*tx.Transaction{
+			funding: {Outputs: []*utxo.TxOutput{
+				{AssetID: expired, Value: 1, ProgramHash: holder},
+				{AssetID: fee, Value: 10, ProgramHash: holder},
+			}},
+		},
+		height: 9,
+	}
+	ledger.DefaultLedger = &ledger.Ledger{Store: store}
+	tx.TxStore = store
+	feeAsset, feeRenew := config.Parameters.SystemFeeAsset, config.Parameters.SystemFee
+	config.Parameters.SystemFeeAsset = common.ToHexString(fee.ToArray())
+	config.Parameters.SystemFee = map[string]int64{"AssetRenew": 3}
+	defer func() {
+		ledger.DefaultLedger, tx.TxStore = nil, nil
+		config.Parameters.SystemFeeAsset, config.Parameters.SystemFee = feeAsset, feeRenew
+	}()
+
+	if err := checkAssets(&tx.Transaction{TxType: tx.TransferAsset, UTXOInputs: []*utxo.UTXOTxInput{{ReferTxID: funding}}}); err == nil {
+		t.Error("spend expired: got nil")
+	}
+	renews := []struct {
+		name string
+		p    *payload.AssetRenew
+		ok   bool
+	}{
+		{"renew", &payload.AssetRenew{AssetID: expired, Years: 1}, true},
+		{"renew no year", &payload.AssetRenew{AssetID: expired}, false},
+		{"renew unknown", &payload.AssetRenew{AssetID: funding, Years: 1}, false},
+		{"renew overflow", &payload.AssetRenew{AssetID: active, Years: 3000}, false},
+	}
+	for _, c := range renews {
+		if err := checkAssetRenew(c.p); (err == nil) != c.ok {
+			t.Errorf("%s: got %v", c.name, err)
+		}
+	}
+
+	renew := func(change common.Fixed64) *tx.Transaction {
+		return &tx.Transaction{
+			TxType:     tx.AssetRenew,
+			Payload:    &payload.AssetRenew{AssetID: active, Years: 2},
+			UTXOInputs: []*utxo.UTXOTxInput{{ReferTxID: funding, ReferTxOutputIndex: 1}},
+			Outputs:    []*utxo.TxOutput{{AssetID: fee, Value: change, ProgramHash: holder}},
+		}
+	}
+	if err := CheckTransactionBalance(renew(4)); err != nil {
+		t.Errorf("pay fee: got %v", err)
+	}
+	if err := CheckTransactionBalance(renew(7)); err == nil {
+		t.Error("underpay fee: got nil")
+	}
+
+	asset := &states.AssetState{Expiration: 9}
+	asset.Renew(10, 1)
+	if asset.Expiration != 10+states.BlocksPerYear {
+		t.Errorf("renew expired asset: got %d", asset.Expiration)
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	asset := common.Uint256{1}
+	holder := common.Uint160{1}
+	funding := common.Uint256{2}
+	store := &contractStore{
+		assets: map[common.Uint256]*states.AssetState{
+			asset: {AssetId: asset, Expiration: 100, Precision: 8},
+		},
+		txs: map[common.Uint256]*tx.Transaction{
+			funding: {Outputs: []*utxo.TxOutput{{AssetID: asset, Value: 10, ProgramHash: holder}}},
+		},
+	}
+	ledger.DefaultLedger = &ledger.Ledger{Store: store}
+	tx.TxStore = store
+	defer func() { ledger.DefaultLedger, tx.TxStore = nil, nil }()
+
+	destroy := func(amount, change common.Fixed64) *tx.Transaction {
+		t := &tx.Transaction{
+			TxType:     tx.Destroy,
+			Payload:    &payload.Destroy{AssetID: asset, Amount: amount},
+			UTXOInputs: []*utxo.UTXOTxInput{{ReferTxID: funding}},
+		}
+		if change > 0 {
+			t.Outputs = []*utxo.TxOutput{{AssetID: asset, Value: change, ProgramHash: holder}}
+		}
+		return t
+	}
+	cases := []struct {
+		name string
+		tx   *tx.Transaction
+		ok   bool
+	}{
+		{"destroy all", destroy(10, 0), true},
+		{"destroy with change", destroy(4, 6), true},
+		{"destroy more than spent", destroy(11, 0), false},
+		{"destroy less than spent", destroy(4, 5), false},
+		{"destroy nothing", destroy(0, 10), false},
+	}
+	for _, c := range cases {
+		err := CheckTransactionBalance(c.tx)
+		if err == nil {
+			err = CheckTransactionPayload(c.tx)
+		}
+		if (err == nil) != c.ok {
+			t.Errorf("%s: got %v", c.name, err)
+		}
+	}
+
+	state := &states.AssetState{Amount: 100, Available: 40, Burned: 10}
+	if state.Issued() != 60 || state.Circulating() != 50 {
+		t.Errorf("supply: got issued %d, circulating %d", state.Issued(), state.Circulating())
+	}
+}
+
+func TestOutputLock(t *testing.T) {
+	asset := common.Uint256{1}
+	holder := common.Uint160{1}
+	funding := common.Uint256{2}
+	store := &contractStore{
+		assets: map[common.Uint256]*states.AssetState{
+			asset: {AssetId: asset, Expiration: 100, Precision: 8},
+		},
+		txs: map[common.Uint256]*tx.Transaction{
+			funding: {Outputs: []*utxo.TxOutput{
+				{AssetID: asset, Value: 10, ProgramHash: holder, LockType: utxo.OutputLock_HEIGHT, LockValue: 20},
+			}},
+		},
+	}
+	ledger.DefaultLedger = &ledger.Ledger{Store: store}
+	tx.TxStore = store
+	defer func() { ledger.DefaultLedger, tx.TxStore = nil, nil }()
+
+	spend := &tx.Transaction{
+		TxType:     tx.TransferAsset,
+		Payload:    &payload.TransferAsset{},
+		UTXOInputs: []*utxo.UTXOTxInput{{ReferTxID: funding}},
+		Outputs: []*utxo.TxOutput{
+			{AssetID: asset, Value: 10, ProgramHash: holder, LockType: utxo.OutputLock_TIME, LockValue: 1000},
+		},
+	}
+	for _, c := range []struct {
+		height uint32
+		ok     bool
+	}{{18, false}, {19, true}} {
+		store.height = c.height
+		if err := CheckTransactionBalance(spend); (err == nil) != c.ok {
+			t.Errorf("spend at height %d: got %v", c.height+1, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := spend.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Bytes()[1] != tx.OutputLockVersion {
+		t.Errorf("payload version: got %x", buf.Bytes()[1])
+	}
+	decoded := new(tx.Transaction)
+	if err := decoded.Deserialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if *decoded.Outputs[0] != *spend.Outputs[0] || decoded.Hash() != spend.Hash() {
+		t.Errorf("round trip: got output %+v", decoded.Outputs[0])
+	}
+}
+
+func TestTransactionExpiry(t *testing.T) {
+	attr := tx.NewValidUntilBlockAttribute(10)
+	txn := &tx.Transaction{
+		TxType:     tx.TransferAsset,
+		Payload:    &payload.TransferAsset{},
+		Attributes: []*tx.TxAttribute{&attr},
+	}
+	if err := CheckAttributeProgram(txn); err != nil {
+		t.Errorf("attribute: got %v", err)
+	}
+	if err := CheckTransactionExpiry(txn, 10); err != nil {
+		t.Errorf("expiry at height 10: got %v", err)
+	}
+	if err := CheckTransactionExpiry(txn, 11); err == nil {
+		t.Errorf("expiry at height 11: expected error")
+	}
+	txn.Attributes = append(txn.Attributes, &attr)
+	if err := CheckAttributeProgram(txn); err == nil {
+		t.Errorf("duplicated attribute: expected error")
+	}
+
+}
+
+func TestNetworkMagic(t *testing.T) {
+	txn := &tx.Transaction{
+		TxType:  tx.TransferAsset,
+		Payload: &payload.TransferAsset{},
+	}
+	bookKeeping := &tx.Transaction{
+		TxType:  tx.BookKeeping,
+		Payload: &payload.BookKeeping{},
+	}
+	config.Parameters.MagicHeight = 10
+	defer func() { config.Parameters.MagicHeight = 0 }()
+	message := txn.GetMessage()
+	if err := CheckNetworkMagic(txn, 9); err != nil {
+		t.Errorf("transaction without magic before it is required: got %v", err)
+	}
+	if err := CheckNetworkMagic(txn, 10); err == nil {
+		t.Errorf("transaction without magic once it is required: expected error")
+	}
+	if err := CheckNetworkMagic(bookKeeping, 10); err != nil {
+		t.Errorf("bookkeeping transaction without magic: got %v", err)
+	}
+
+	magic := uint32(config.Parameters.Magic)
+	txn.SetNetworkMagic(magic)
+	if bytes.Equal(message, txn.GetMessage()) {
+		t.Errorf("message does not depend on the network magic")
+	}
+	if err := CheckNetworkMagic(txn, 10); err != nil {
+		t.Errorf("transaction of this network: got %v", err)
+	}
+	var buf bytes.Buffer
+	if err := txn.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(tx.Transaction)
+	if err := decoded.Deserialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.NetworkMagic != magic || decoded.Hash() != txn.Hash() {
+		t.Errorf("round trip: got magic %d", decoded.NetworkMagic)
+	}
+
+	txn.SetNetworkMagic(magic + 1)
+	if err := CheckNetworkMagic(txn, 9); err == nil {
+		t.Errorf("transaction of another network: expected error")
+	}
+}
+
+func TestAttributeUsage(t *testing.T) {
+	cases := []struct {
+		name string
+		attr tx.TxAttribute
+		ok   bool
+	}{
+		{"hash", tx.NewTxAttribute(tx.Hash15, make([]byte, 32)), true},
+		{"short hash", tx.NewTxAttribute(tx.Hash1, make([]byte, 31)), false},
+		{"ecdh", tx.NewTxAttribute(tx.ECDH02, make([]byte, 32)), true},
+		{"remark", tx.NewTxAttribute(tx.Remark3, make([]byte, 300)), true},
+		{"long description url", tx.NewTxAttribute(tx.DescriptionUrl, make([]byte, 256)), false},
+		{"unknown usage", tx.NewTxAttribute(0x40, nil), false},
+		{"script", tx.NewTxAttribute(tx.Script, make([]byte, 20)), true},
+		{"short script", tx.NewTxAttribute(tx.Script, make([]byte, 19)), false},
+	}
+	for _, c := range cases {
+		txn := &tx.Transaction{
+			TxType:     tx.TransferAsset,
+			Payload:    &payload.TransferAsset{},
+			Attributes: []*tx.TxAttribute{&c.attr},
+		}
+		if err := CheckAttributeProgram(txn); (err == nil) != c.ok {
+			t.Errorf("%s: got %v", c.name, err)
+		}
+	}
+}
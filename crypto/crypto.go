@@ -42,12 +42,17 @@ var AlgChoice int
 
 var algSet util.CryptoAlgSet
 
+// curves of each algorithm, independent of AlgChoice
+var p256r1Set, sm2Set util.CryptoAlgSet
+
 type PubKey struct {
 	X, Y *big.Int
 }
 
 func init() {
 	AlgChoice = 0
+	p256r1.Init(&p256r1Set)
+	sm2.Init(&sm2Set)
 }
 
 func SetAlg(algChoice string) {
@@ -122,6 +127,49 @@ func Verify(publicKey PubKey, data []byte, signature []byte) error {
 	return p256r1.Verify(&algSet, publicKey.X, publicKey.Y, data, r, s)
 }
 
+func getAlgSet(alg int) (*util.CryptoAlgSet, error) {
+	switch alg {
+	case P256R1:
+		return &p256r1Set, nil
+	case SM2:
+		return &sm2Set, nil
+	}
+	return nil, fmt.Errorf("unknown algorithm %d", alg)
+}
+
+// DecodePointWithAlgorithm decodes a public key on the curve of alg rather
+// than the configured one.
+func DecodePointWithAlgorithm(alg int, encodeData []byte) (*PubKey, error) {
+	set, err := getAlgSet(alg)
+	if err != nil {
+		return nil, err
+	}
+	return decodePoint(encodeData, &set.EccParams)
+}
+
+// VerifyWithAlgorithm verifies a signature with alg rather than the
+// configured algorithm, so that a chain can check signatures of both.
+func VerifyWithAlgorithm(alg int, publicKey PubKey, data []byte, signature []byte) error {
+	set, err := getAlgSet(alg)
+	if err != nil {
+		return err
+	}
+	if len(signature) != util.SIGNATURELEN {
+		return errors.New("Unknown signature length")
+	}
+	if publicKey.X == nil || publicKey.Y == nil || !set.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+		return errors.New("Public key is not on the curve")
+	}
+
+	r := new(big.Int).SetBytes(signature[:util.SIGNRLEN])
+	s := new(big.Int).SetBytes(signature[util.SIGNRLEN:])
+
+	if SM2 == alg {
+		return sm2.Verify(set, publicKey.X, publicKey.Y, data, r, s)
+	}
+	return p256r1.Verify(set, publicKey.X, publicKey.Y, data, r, s)
+}
+
 func (e *PubKey) Serialize(w io.Writer) error {
 	bufX := []byte{}
 	if e.X.Sign() == -1 {
@@ -254,11 +254,15 @@ func deCompress(yTilde int, xValue []byte, curve *elliptic.CurveParams) (*PubKey
 }
 
 func DecodePoint(encodeData []byte) (*PubKey, error) {
-	if nil == encodeData {
+	return decodePoint(encodeData, &algSet.EccParams)
+}
+
+func decodePoint(encodeData []byte, curve *elliptic.CurveParams) (*PubKey, error) {
+	if len(encodeData) == 0 {
 		return nil, NewDetailErr(errors.New("The encodeData cann't be nil"), ErrNoCode, "")
 	}
 
-	expectedLength := (algSet.EccParams.P.BitLen() + 7) / 8
+	expectedLength := (curve.P.BitLen() + 7) / 8
 
 	switch encodeData[0] {
 	case 0x00:
@@ -270,14 +274,16 @@ func DecodePoint(encodeData []byte) (*PubKey, error) {
 		}
 
 		yTilde := int(encodeData[0] & 1)
-		pubKey, err := deCompress(yTilde, encodeData[FLAGLEN:FLAGLEN + XORYVALUELEN],
-			&algSet.EccParams)
+		pubKey, err := deCompress(yTilde, encodeData[FLAGLEN:FLAGLEN + XORYVALUELEN], curve)
 		if nil != err {
 			return nil, NewDetailErr(err, ErrNoCode, "Invalid point encoding")
 		}
 		return pubKey, nil
 
 	case 0x04, 0x06, 0x07: //uncompressed
+		if len(encodeData) != NOCOMPRESSEDLEN {
+			return nil, NewDetailErr(errors.New("The encodeData format is error"), ErrNoCode, "")
+		}
 		pubKeyX := new(big.Int).SetBytes(encodeData[FLAGLEN : FLAGLEN + XORYVALUELEN])
 		pubKeyY := new(big.Int).SetBytes(encodeData[FLAGLEN + XORYVALUELEN : NOCOMPRESSEDLEN])
 		return &PubKey{pubKeyX, pubKeyY}, nil
@@ -52,6 +52,7 @@ func Sign(algSet *util.CryptoAlgSet, priKey []byte, data []byte) (*big.Int, *big
 	privateKey.Curve = algSet.Curve
 	privateKey.D = big.NewInt(0)
 	privateKey.D.SetBytes(priKey)
+	privateKey.PublicKey.X, privateKey.PublicKey.Y = algSet.Curve.ScalarBaseMult(priKey)
 
 	r := big.NewInt(0)
 	s := big.NewInt(0)
@@ -45,6 +45,8 @@ const (
 	ErrStateUpdaterVaild ErrCode = 45011
 	ErrSummaryAsset ErrCode = 45012
 	ErrXmitFail ErrCode = 45013
+	ErrTxExpired ErrCode = 45014
+	ErrNetworkMagic ErrCode = 45015
 )
 
 func (err ErrCode) Error() string {
@@ -79,6 +81,10 @@ func (err ErrCode) Error() string {
 		return "invalid summary asset"
 	case ErrXmitFail:
 		return "transmit error"
+	case ErrTxExpired:
+		return "transaction expired"
+	case ErrNetworkMagic:
+		return "transaction of another network"
 	}
 
 	return fmt.Sprintf("Unknown error? Error code = %d", err)
@@ -139,6 +139,10 @@ func (self *MemHashStore) GetHash(pos uint32) (Uint256, error) {
 	return self.hashes[pos], nil
 }
 
+func (self *MemHashStore) Len() uint32 {
+	return uint32(len(self.hashes))
+}
+
 func (self *MemHashStore) Flush() error {
 	return nil
 }
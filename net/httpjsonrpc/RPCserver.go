@@ -38,6 +38,7 @@ func StartRPCServer() {
 	HandleFunc("getconnectioncount", getConnectionCount)
 	HandleFunc("getrawmempool", getRawMemPool)
 	HandleFunc("getrawtransaction", getRawTransaction)
+	HandleFunc("getcontract", getContract)
 	HandleFunc("sendrawtransaction", sendRawTransaction)
 	HandleFunc("getstorage", getStorage)
 	HandleFunc("getbalance", getBalance)
@@ -48,6 +49,12 @@ func StartRPCServer() {
 	HandleFunc("regdatafile", regDataFile)
 	HandleFunc("uploadDataFile", uploadDataFile)
 	HandleFunc("getsmartcodeevent", getSmartCodeEvent)
+	HandleFunc("getlogs", getLogs)
+	HandleFunc("getddo", getDDO)
+	HandleFunc("getclaimstatus", getClaimStatus)
+	HandleFunc("getfreezestatus", getFreezeStatus)
+	HandleFunc("getassetsupply", getAssetSupply)
+	HandleFunc("buildtransaction", buildTransaction)
 
 	err := http.ListenAndServe(":" + strconv.Itoa(Parameters.HttpJsonPort), nil)
 	if err != nil {
@@ -22,9 +22,12 @@ package httpjsonrpc
 import (
 	. "github.com/Ontology/common"
 	"github.com/Ontology/core/asset"
+	"github.com/Ontology/core/code"
 	. "github.com/Ontology/core/contract"
+	"github.com/Ontology/core/states"
 	. "github.com/Ontology/core/transaction"
 	"github.com/Ontology/core/transaction/payload"
+	"github.com/Ontology/vm/neovm/asm"
 	"bytes"
 )
 
@@ -41,10 +44,12 @@ type FunctionCodeInfo struct {
 	Code           string
 	ParameterTypes string
 	ReturnType     uint8
+	Disasm         string `json:",omitempty"`
 }
 type InvokeCodeInfo struct {
 	CodeHash string
 	Code     string
+	Disasm   string `json:",omitempty"`
 }
 type DeployCodeInfo struct {
 	Code        *FunctionCodeInfo
@@ -53,6 +58,22 @@ type DeployCodeInfo struct {
 	Author      string
 	Email       string
 	Description string
+	ABI         *code.ABI `json:",omitempty"`
+}
+
+type ContractInfo struct {
+	CodeHash        string
+	Code            *FunctionCodeInfo
+	VmType          byte
+	NeedStorage     bool
+	DynamicInvoke   bool
+	ReentrancyGuard bool
+	Name            string
+	CodeVersion     string
+	Author          string
+	Email           string
+	Description     string
+	ABI             *code.ABI `json:",omitempty"`
 }
 
 //implement PayloadInfo define IssueAssetInfo
@@ -71,6 +92,25 @@ type RegisterAssetInfo struct {
 	Controller string
 }
 
+//implement PayloadInfo define AssetRenewInfo
+type AssetRenewInfo struct {
+	AssetID string
+	Years   uint32
+}
+
+//implement PayloadInfo define AssetAdminChangeInfo
+type AssetAdminChangeInfo struct {
+	AssetID string
+	Admin   string
+	Issuer  string
+}
+
+//implement PayloadInfo define DestroyInfo
+type DestroyInfo struct {
+	AssetID string
+	Amount  Fixed64
+}
+
 //implement PayloadInfo define TransferAssetInfo
 type TransferAssetInfo struct {
 }
@@ -135,15 +175,13 @@ func TransPayloadToHex(p Payload) PayloadInfo {
 		return obj
 	case *payload.DeployCode:
 		obj := new(DeployCodeInfo)
-		obj.Code = new(FunctionCodeInfo)
-		obj.Code.Code = ToHexString(object.Code.Code)
-		obj.Code.ParameterTypes = ToHexString(ContractParameterTypeToByte(object.Code.ParameterTypes))
-		obj.Code.ReturnType = uint8(object.Code.ReturnType)
+		obj.Code = transFunctionCode(object.Code)
 		obj.Name = object.Name
 		obj.CodeVersion = object.CodeVersion
 		obj.Author = object.Author
 		obj.Email = object.Email
 		obj.Description = object.Description
+		obj.ABI = object.ABI
 		return obj
 	case *payload.RegisterAsset:
 		obj := new(RegisterAssetInfo)
@@ -153,6 +191,22 @@ func TransPayloadToHex(p Payload) PayloadInfo {
 		obj.Issuer.Y = object.Issuer.Y.String()
 		obj.Controller = ToHexString(object.Controller.ToArray())
 		return obj
+	case *payload.AssetRenew:
+		obj := new(AssetRenewInfo)
+		obj.AssetID = ToHexString(object.AssetID.ToArray())
+		obj.Years = object.Years
+		return obj
+	case *payload.Destroy:
+		obj := new(DestroyInfo)
+		obj.AssetID = ToHexString(object.AssetID.ToArray())
+		obj.Amount = object.Amount
+		return obj
+	case *payload.AssetAdminChange:
+		obj := new(AssetAdminChangeInfo)
+		obj.AssetID = ToHexString(object.AssetID.ToArray())
+		obj.Admin = ToHexString(object.Admin.ToArray())
+		obj.Issuer = ToHexString(object.Issuer.ToArray())
+		return obj
 	case *payload.Record:
 		obj := new(RecordInfo)
 		obj.RecordType = object.RecordType
@@ -175,6 +229,57 @@ func TransPayloadToHex(p Payload) PayloadInfo {
 		obj.Issuer.X = object.Issuer.X.String()
 		obj.Issuer.Y = object.Issuer.Y.String()
 		return obj
+	case *payload.Identity:
+		return transIdentityPayload(object)
+	case *payload.Attestation:
+		return transAttestationPayload(object)
+	case *payload.Freeze:
+		return transFreezePayload(object)
 	}
 	return nil
 }
+
+func transFunctionCode(fc *code.FunctionCode) *FunctionCodeInfo {
+	obj := new(FunctionCodeInfo)
+	obj.Code = ToHexString(fc.Code)
+	obj.ParameterTypes = ToHexString(ContractParameterTypeToByte(fc.ParameterTypes))
+	obj.ReturnType = uint8(fc.ReturnType)
+	return obj
+}
+
+func TransContractStateToInfo(hash Uint160, c *states.ContractState) *ContractInfo {
+	obj := new(ContractInfo)
+	obj.CodeHash = ToHexString(hash.ToArray())
+	obj.Code = transFunctionCode(c.Code)
+	obj.VmType = byte(c.VmType)
+	obj.NeedStorage = c.NeedStorage
+	obj.DynamicInvoke = c.DynamicInvoke
+	obj.ReentrancyGuard = c.ReentrancyGuard
+	obj.Name = c.Name
+	obj.CodeVersion = c.Version
+	obj.Author = c.Author
+	obj.Email = c.Email
+	obj.Description = c.Description
+	obj.ABI = c.ABI
+	return obj
+}
+
+// AddContractDisasm fills in the disassembly of the contract code.
+func AddContractDisasm(info *ContractInfo, c *states.ContractState) {
+	info.Code.Disasm = asm.DisassembleString(c.Code.Code)
+}
+
+// AddPayloadDisasm fills in the disassembly of the code carried by an
+// InvokeCode or DeployCode payload.
+func AddPayloadDisasm(info PayloadInfo, p Payload) {
+	switch object := p.(type) {
+	case *payload.InvokeCode:
+		if obj, ok := info.(*InvokeCodeInfo); ok {
+			obj.Disasm = asm.DisassembleString(object.Code)
+		}
+	case *payload.DeployCode:
+		if obj, ok := info.(*DeployCodeInfo); ok {
+			obj.Code.Disasm = asm.DisassembleString(object.Code.Code)
+		}
+	}
+}
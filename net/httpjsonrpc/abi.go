@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package httpjsonrpc
+
+import (
+	. "github.com/Ontology/common"
+	"github.com/Ontology/core/ledger"
+	sc "github.com/Ontology/smartcontract/common"
+	"github.com/Ontology/vm/neovm/types"
+)
+
+type EventParamInfo struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+type NotifyEventInfo struct {
+	Name   string
+	Params []EventParamInfo
+}
+
+// DecodeNotify decodes the state of a Runtime.Notify with the matching event
+// of the ABI of the contract, see sc.DecodeEvent. It returns nil if the
+// contract declares no such event.
+func DecodeNotify(codeHash Uint160, state types.StackItemInterface) *NotifyEventInfo {
+	if ledger.DefaultLedger == nil {
+		return nil
+	}
+	contract, err := ledger.DefaultLedger.Store.GetContract(codeHash)
+	if err != nil || contract == nil {
+		return nil
+	}
+	event, values := sc.DecodeEvent(contract.ABI, state)
+	if event == nil {
+		return nil
+	}
+	info := &NotifyEventInfo{Name: event.Name}
+	for i, p := range event.Parameters {
+		info.Params = append(info.Params, EventParamInfo{
+			Name:  p.Name,
+			Type:  p.Type.Name(),
+			Value: values[i],
+		})
+	}
+	return info
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package httpjsonrpc
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	. "github.com/Ontology/common"
+	tx "github.com/Ontology/core/transaction"
+)
+
+// BuildTransactionInfo is a transaction built from its JSON form. Hex is the
+// serialized transaction, SignData the data its signers sign and SignHash
+// the sha256 of it.
+type BuildTransactionInfo struct {
+	Hex      string
+	Hash     string
+	SignData string
+	SignHash string
+}
+
+func BuildTransaction(obj *tx.TransactionJson) (*BuildTransactionInfo, error) {
+	txn, err := obj.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := txn.Serialize(&buffer); err != nil {
+		return nil, err
+	}
+	hash := txn.Hash()
+	message := txn.GetMessage()
+	signHash := sha256.Sum256(message)
+	return &BuildTransactionInfo{
+		Hex:      ToHexString(buffer.Bytes()),
+		Hash:     ToHexString(hash.ToArray()),
+		SignData: ToHexString(message),
+		SignHash: ToHexString(signHash[:]),
+	}, nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package httpjsonrpc
+
+import (
+	"bytes"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/transaction/payload"
+)
+
+type AttestationInfo struct {
+	Action    string
+	ClaimHash string
+	IssuerID  string
+	SubjectID string `json:",omitempty"`
+	Expiry    uint32 `json:",omitempty"`
+	Signer    string
+	Signature string
+}
+
+// ClaimStatusInfo is the status of a claim with its proof. State is the
+// serialized claim, its sha256 is the value proven under StateRoot for the
+// trie key ST_Claim followed by the claim hash.
+type ClaimStatusInfo struct {
+	ClaimHash    string
+	IssuerID     string
+	SubjectID    string
+	Expiry       uint32
+	CommitHeight uint32
+	RevokeHeight uint32 `json:",omitempty"`
+	Status       string
+	State        string
+	StateRoot    string
+	Proof        []string
+}
+
+func transAttestationPayload(p *payload.Attestation) *AttestationInfo {
+	obj := &AttestationInfo{
+		Action:    "commit",
+		ClaimHash: ToHexString(p.ClaimHash.ToArray()),
+		IssuerID:  string(p.IssuerID),
+		SubjectID: string(p.SubjectID),
+		Expiry:    p.Expiry,
+		Signer:    pubKeyToHex(p.Signer),
+		Signature: ToHexString(p.Signature),
+	}
+	if p.Action == payload.AttestationAction_REVOKE {
+		obj.Action = "revoke"
+	}
+	return obj
+}
+
+// GetClaimStatusInfo returns the status of the claim, nil if it was never
+// committed.
+func GetClaimStatusInfo(hash Uint256) (*ClaimStatusInfo, error) {
+	store := ledger.DefaultLedger.Store
+	claim, err := store.GetClaim(hash)
+	if err != nil || claim == nil {
+		return nil, err
+	}
+	root, proof, err := store.GetClaimProof(hash)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	if err := claim.Serialize(buf); err != nil {
+		return nil, err
+	}
+	obj := &ClaimStatusInfo{
+		ClaimHash:    ToHexString(hash.ToArray()),
+		IssuerID:     string(claim.IssuerID),
+		SubjectID:    string(claim.SubjectID),
+		Expiry:       claim.Expiry,
+		CommitHeight: claim.CommitHeight,
+		RevokeHeight: claim.RevokeHeight,
+		Status:       claim.Status(store.GetHeight()),
+		State:        ToHexString(buf.Bytes()),
+		StateRoot:    ToHexString(root.ToArray()),
+		Proof:        make([]string, 0, len(proof)),
+	}
+	for _, n := range proof {
+		obj.Proof = append(obj.Proof, ToHexString(n))
+	}
+	return obj, nil
+}
@@ -54,8 +54,9 @@ type ServeMux struct {
 }
 
 type TxAttributeInfo struct {
-	Usage TransactionAttributeUsage
-	Data  string
+	Usage     TransactionAttributeUsage
+	UsageName string
+	Data      string
 }
 
 type UTXOTxInputInfo struct {
@@ -73,6 +74,18 @@ type TxoutputInfo struct {
 	AssetID     string
 	Value       Fixed64
 	ProgramHash string
+	LockType    string `json:",omitempty"`
+	LockValue   uint32 `json:",omitempty"`
+}
+
+var outputLockNames = map[utxo.OutputLockType]string{
+	utxo.OutputLock_HEIGHT: "height",
+	utxo.OutputLock_TIME:   "time",
+}
+
+// OutputLockName returns the name of a lock type, empty if unlocked.
+func OutputLockName(lockType utxo.OutputLockType) string {
+	return outputLockNames[lockType]
 }
 
 type TxoutputMap struct {
@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package httpjsonrpc
+
+import (
+	. "github.com/Ontology/common"
+	"github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/transaction/payload"
+)
+
+type FreezeInfo struct {
+	Target  string
+	AssetID string
+	Address string `json:",omitempty"`
+	Frozen  bool
+}
+
+// FreezeStatusInfo tells whether the asset is frozen and, when an address is
+// given, whether the address is frozen within the asset.
+type FreezeStatusInfo struct {
+	AssetID       string
+	Frozen        bool
+	Address       string `json:",omitempty"`
+	AddressFrozen bool   `json:",omitempty"`
+}
+
+func transFreezePayload(p *payload.Freeze) *FreezeInfo {
+	obj := &FreezeInfo{
+		Target:  "asset",
+		AssetID: ToHexString(p.AssetID.ToArray()),
+		Frozen:  p.Frozen,
+	}
+	if p.Target == payload.FreezeTarget_ACCOUNT {
+		obj.Target = "account"
+		obj.Address, _ = p.Account.ToAddress()
+	}
+	return obj
+}
+
+// GetFreezeStatusInfo returns the freeze status of the asset and, if
+// programHash is not nil, of the address within the asset.
+func GetFreezeStatusInfo(assetID Uint256, programHash *Uint160) (*FreezeStatusInfo, error) {
+	store := ledger.DefaultLedger.Store
+	asset, err := store.GetAsset(assetID)
+	if err != nil {
+		return nil, err
+	}
+	obj := &FreezeStatusInfo{
+		AssetID: ToHexString(assetID.ToArray()),
+		Frozen:  asset.IsFrozen,
+	}
+	if programHash == nil {
+		return obj, nil
+	}
+	obj.Address, _ = programHash.ToAddress()
+	state, err := store.GetFrozenAccount(assetID, *programHash)
+	if err != nil {
+		return nil, err
+	}
+	obj.AddressFrozen = state != nil && state.IsFrozen
+	return obj, nil
+}
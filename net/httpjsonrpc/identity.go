@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package httpjsonrpc
+
+import (
+	. "github.com/Ontology/common"
+	"github.com/Ontology/core/states"
+	"github.com/Ontology/core/transaction/payload"
+	"github.com/Ontology/crypto"
+)
+
+type IdentityAttributeInfo struct {
+	Key   string
+	Type  string
+	Value string
+}
+
+// DDOInfo is the resolved document of an ONT ID.
+type DDOInfo struct {
+	ID         string
+	PubKeys    []string
+	Attributes []IdentityAttributeInfo
+	Recovery   string `json:",omitempty"`
+}
+
+type IdentityInfo struct {
+	ID        string
+	Action    string
+	PubKey    string                 `json:",omitempty"`
+	Attribute *IdentityAttributeInfo `json:",omitempty"`
+	Recovery  string                 `json:",omitempty"`
+	Signer    string                 `json:",omitempty"`
+	Recoverer string                 `json:",omitempty"`
+}
+
+var identityActionNames = map[payload.IdentityAction]string{
+	payload.IdentityAction_REGISTER:         "register",
+	payload.IdentityAction_ADD_KEY:          "addkey",
+	payload.IdentityAction_REMOVE_KEY:       "removekey",
+	payload.IdentityAction_ADD_ATTRIBUTE:    "addattribute",
+	payload.IdentityAction_UPDATE_ATTRIBUTE: "updateattribute",
+	payload.IdentityAction_REMOVE_ATTRIBUTE: "removeattribute",
+	payload.IdentityAction_SET_RECOVERY:     "setrecovery",
+}
+
+func pubKeyToHex(pk *crypto.PubKey) string {
+	encoded, _ := pk.EncodePoint(true)
+	return ToHexString(encoded)
+}
+
+func transIdentityAttribute(a *payload.IdentityAttribute) IdentityAttributeInfo {
+	return IdentityAttributeInfo{
+		Key:   string(a.Key),
+		Type:  string(a.Type),
+		Value: string(a.Value),
+	}
+}
+
+func TransIdentityStateToDDO(ddo *states.IdentityState) *DDOInfo {
+	obj := &DDOInfo{
+		ID:         string(ddo.ID),
+		PubKeys:    make([]string, 0, len(ddo.PubKeys)),
+		Attributes: make([]IdentityAttributeInfo, 0, len(ddo.Attributes)),
+	}
+	for _, k := range ddo.PubKeys {
+		obj.PubKeys = append(obj.PubKeys, pubKeyToHex(k))
+	}
+	for _, a := range ddo.Attributes {
+		obj.Attributes = append(obj.Attributes, transIdentityAttribute(a))
+	}
+	if ddo.HasRecovery {
+		obj.Recovery, _ = ddo.Recovery.ToAddress()
+	}
+	return obj
+}
+
+func transIdentityPayload(p *payload.Identity) *IdentityInfo {
+	obj := &IdentityInfo{
+		ID:     string(p.ID),
+		Action: identityActionNames[p.Action],
+	}
+	if p.PubKey != nil {
+		obj.PubKey = pubKeyToHex(p.PubKey)
+	}
+	if p.Attribute != nil {
+		a := transIdentityAttribute(p.Attribute)
+		obj.Attribute = &a
+	}
+	if p.Action == payload.IdentityAction_SET_RECOVERY {
+		obj.Recovery, _ = p.Recovery.ToAddress()
+	}
+	if p.Action != payload.IdentityAction_REGISTER {
+		if p.Signer != nil {
+			obj.Signer = pubKeyToHex(p.Signer)
+		} else {
+			obj.Recoverer, _ = p.Recoverer.ToAddress()
+		}
+	}
+	return obj
+}
@@ -23,6 +23,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/Ontology/account"
 	. "github.com/Ontology/common"
@@ -32,6 +33,9 @@ import (
 	"github.com/Ontology/core/states"
 	tx "github.com/Ontology/core/transaction"
 	. "github.com/Ontology/errors"
+	"github.com/Ontology/smartcontract/native"
+	"github.com/Ontology/smartcontract/trace"
+	"github.com/Ontology/smartcontract/types"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -53,6 +57,7 @@ func TransArryByteToHexString(ptx *tx.Transaction) *Transactions {
 	trans.Attributes = make([]TxAttributeInfo, len(ptx.Attributes))
 	for _, v := range ptx.Attributes {
 		trans.Attributes[n].Usage = v.Usage
+		trans.Attributes[n].UsageName = v.Usage.String()
 		trans.Attributes[n].Data = ToHexString(v.Data)
 		n++
 	}
@@ -80,6 +85,8 @@ func TransArryByteToHexString(ptx *tx.Transaction) *Transactions {
 		trans.Outputs[n].AssetID = ToHexString(v.AssetID.ToArray())
 		trans.Outputs[n].Value = v.Value
 		trans.Outputs[n].ProgramHash = ToHexString(v.ProgramHash.ToArray())
+		trans.Outputs[n].LockType = OutputLockName(v.LockType)
+		trans.Outputs[n].LockValue = v.LockValue
 		n++
 	}
 
@@ -100,6 +107,8 @@ func TransArryByteToHexString(ptx *tx.Transaction) *Transactions {
 			trans.AssetOutputs[n].Txout[m].AssetID = ToHexString(v[m].AssetID.ToArray())
 			trans.AssetOutputs[n].Txout[m].Value = v[m].Value
 			trans.AssetOutputs[n].Txout[m].ProgramHash = ToHexString(v[m].ProgramHash.ToArray())
+			trans.AssetOutputs[n].Txout[m].LockType = OutputLockName(v[m].LockType)
+			trans.AssetOutputs[n].Txout[m].LockValue = v[m].LockValue
 		}
 		n += 1
 	}
@@ -244,6 +253,8 @@ func getRawMemPool(params []interface{}) map[string]interface{} {
 
 // A JSON example for getrawtransaction method as following:
 //   {"jsonrpc": "2.0", "method": "getrawtransaction", "params": ["transactioin hash in hex"], "id": 0}
+// An optional second parameter of true adds the disassembly of contract code:
+//   {"jsonrpc": "2.0", "method": "getrawtransaction", "params": ["transactioin hash in hex", true], "id": 0}
 func getRawTransaction(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return DnaRpcNil
@@ -265,12 +276,216 @@ func getRawTransaction(params []interface{}) map[string]interface{} {
 			return DnaRpcUnknownTransaction
 		}
 		tran := TransArryByteToHexString(tx)
+		if len(params) > 1 {
+			if disasm, ok := params[1].(bool); ok && disasm {
+				AddPayloadDisasm(tran.Payload, tx.Payload)
+			}
+		}
 		return DnaRpc(tran)
 	default:
 		return DnaRpcInvalidParameter
 	}
 }
 
+// A JSON example for getcontract method as following:
+//   {"jsonrpc": "2.0", "method": "getcontract", "params": ["contract code hash in hex", true], "id": 0}
+// the optional second parameter adds the disassembly of the code.
+func getContract(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return DnaRpcNil
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return DnaRpcInvalidParameter
+	}
+	hex, err := hex.DecodeString(str)
+	if err != nil {
+		return DnaRpcInvalidParameter
+	}
+	var hash Uint160
+	if err := hash.Deserialize(bytes.NewReader(hex)); err != nil {
+		return DnaRpcInvalidHash
+	}
+	contract, err := ledger.DefaultLedger.Store.GetContract(hash)
+	if err != nil || contract == nil {
+		nc := native.GetContract(hash)
+		if nc == nil {
+			return DnaRpcUnknownContract
+		}
+		contract = native.NewContractState(nc)
+	}
+	info := TransContractStateToInfo(hash, contract)
+	if len(params) > 1 {
+		if disasm, ok := params[1].(bool); ok && disasm && contract.VmType == types.NEOVM {
+			AddContractDisasm(info, contract)
+		}
+	}
+	return DnaRpc(info)
+}
+
+// getDDO resolves the document of the ONT ID given as the first parameter.
+func getDDO(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return DnaRpcNil
+	}
+	id, ok := params[0].(string)
+	if !ok {
+		return DnaRpcInvalidParameter
+	}
+	ddo, err := ledger.DefaultLedger.GetIdentity([]byte(id))
+	if err != nil {
+		return DnaRpcInternalError
+	}
+	if ddo == nil {
+		return DnaRpcUnknownIdentity
+	}
+	return DnaRpc(TransIdentityStateToDDO(ddo))
+}
+
+// getClaimStatus returns the status of the claim whose hash is given in hex,
+// with the proof of the claim against the current state root.
+func getClaimStatus(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return DnaRpcNil
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return DnaRpcInvalidParameter
+	}
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return DnaRpcInvalidParameter
+	}
+	var hash Uint256
+	if err := hash.Deserialize(bytes.NewReader(b)); err != nil {
+		return DnaRpcInvalidHash
+	}
+	info, err := GetClaimStatusInfo(hash)
+	if err != nil {
+		return DnaRpcInternalError
+	}
+	if info == nil {
+		return DnaRpcUnknownClaim
+	}
+	return DnaRpc(info)
+}
+
+// getFreezeStatus returns whether the asset is frozen, and whether the address
+// is frozen within the asset when it is given:
+//   {"jsonrpc": "2.0", "method": "getfreezestatus", "params": ["asset id", "address"], "id": 0}
+func getFreezeStatus(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return DnaRpcNil
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return DnaRpcInvalidParameter
+	}
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return DnaRpcInvalidParameter
+	}
+	var assetID Uint256
+	if err := assetID.Deserialize(bytes.NewReader(b)); err != nil {
+		return DnaRpcInvalidHash
+	}
+	var programHash *Uint160
+	if len(params) > 1 {
+		addr, ok := params[1].(string)
+		if !ok {
+			return DnaRpcInvalidParameter
+		}
+		ph, err := ToScriptHash(addr)
+		if err != nil {
+			return DnaRpcInvalidParameter
+		}
+		programHash = &ph
+	}
+	info, err := GetFreezeStatusInfo(assetID, programHash)
+	if err != nil {
+		return DnaRpcUnknownAsset
+	}
+	return DnaRpc(info)
+}
+
+// getAssetSupply returns the issued, burned and circulating amount of the asset:
+//   {"jsonrpc": "2.0", "method": "getassetsupply", "params": ["asset id"], "id": 0}
+func getAssetSupply(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return DnaRpcNil
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return DnaRpcInvalidParameter
+	}
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return DnaRpcInvalidParameter
+	}
+	var assetID Uint256
+	if err := assetID.Deserialize(bytes.NewReader(b)); err != nil {
+		return DnaRpcInvalidHash
+	}
+	info, err := GetAssetSupplyInfo(assetID)
+	if err != nil {
+		return DnaRpcUnknownAsset
+	}
+	return DnaRpc(info)
+}
+
+// buildtransaction takes the canonical JSON form of an unsigned transaction,
+// as nodectl tx decode prints it, and returns it serialized with the data to sign.
+func buildTransaction(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return DnaRpcNil
+	}
+	if _, ok := params[0].(map[string]interface{}); !ok {
+		return DnaRpcInvalidParameter
+	}
+	data, err := json.Marshal(params[0])
+	if err != nil {
+		return DnaRpcInvalidParameter
+	}
+	var obj tx.TransactionJson
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return DnaRpcInvalidParameter
+	}
+	info, err := BuildTransaction(&obj)
+	if err != nil {
+		return DnaRpcInvalidTransaction
+	}
+	return DnaRpc(info)
+}
+
+// A JSON example for getlogs method as following, all fields are optional:
+//   {"jsonrpc": "2.0", "method": "getlogs", "params": [{"contract": "code hash in hex", "event": "transfer",
+//     "fromheight": 100, "toheight": 200, "offset": 0, "limit": 100}], "id": 0}
+func getLogs(params []interface{}) map[string]interface{} {
+	query := map[string]interface{}{}
+	if len(params) > 0 {
+		var ok bool
+		if query, ok = params[0].(map[string]interface{}); !ok {
+			return DnaRpcInvalidParameter
+		}
+	}
+	contract, _ := query["contract"].(string)
+	event, _ := query["event"].(string)
+	number := func(key string) int {
+		v, _ := query[key].(float64)
+		return int(v)
+	}
+	filter, err := NewNotifyFilter(contract, event, uint32(number("fromheight")), uint32(number("toheight")),
+		number("offset"), number("limit"))
+	if err != nil {
+		return DnaRpcInvalidParameter
+	}
+	infos, err := GetNotifyInfos(filter)
+	if err != nil {
+		return DnaRpcInternalError
+	}
+	return DnaRpc(infos)
+}
+
 func getBalance(params []interface{}) map[string]interface{} {
 	if len(params) < 2 {
 		return DnaRpcNil
@@ -410,9 +625,12 @@ func getUnspendOutput(params []interface{}) map[string]interface{} {
 		return DnaRpcInvalidParameter
 	}
 	type UTXOUnspentInfo struct {
-		Txid  string
-		Index string
-		Value string
+		Txid      string
+		Index     string
+		Value     string
+		LockType  string `json:",omitempty"`
+		LockValue uint32 `json:",omitempty"`
+		Locked    bool
 	}
 	infos, err := ledger.DefaultLedger.Store.GetUnspentFromProgramHash(programHash, assetHash)
 	if err != nil {
@@ -422,7 +640,16 @@ func getUnspendOutput(params []interface{}) map[string]interface{} {
 	for _, v := range infos {
 		val := strconv.FormatInt(int64(v.Value), 10)
 		index := strconv.FormatInt(int64(v.Index), 10)
-		UTXOoutputs = append(UTXOoutputs, UTXOUnspentInfo{Txid: ToHexString(v.Txid.ToArray()), Index: index, Value: val})
+		output, err := ledger.DefaultLedger.GetUnspentLock(v)
+		if err != nil {
+			return DnaRpcInternalError
+		}
+		locked, err := ledger.DefaultLedger.IsLocked(output.LockType, output.LockValue)
+		if err != nil {
+			return DnaRpcInternalError
+		}
+		UTXOoutputs = append(UTXOoutputs, UTXOUnspentInfo{Txid: ToHexString(v.Txid.ToArray()), Index: index, Value: val,
+			LockType: OutputLockName(output.LockType), LockValue: output.LockValue, Locked: locked})
 	}
 	return DnaRpc(UTXOoutputs)
 }
@@ -549,6 +776,62 @@ func setDebugInfo(params []interface{}) map[string]interface{} {
 	return DnaRpcSuccess
 }
 
+// A JSON example for gettxtrace method as following, the node must run with EnableTrace:
+//   {"jsonrpc": "2.0", "method": "gettxtrace", "params": ["transaction hash in hex"], "id": 0}
+func getTxTrace(params []interface{}) map[string]interface{} {
+	if !config.Parameters.EnableTrace {
+		return DnaRpcTraceDisabled
+	}
+	if len(params) < 1 {
+		return DnaRpcNil
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return DnaRpcInvalidParameter
+	}
+	hex, err := hex.DecodeString(str)
+	if err != nil {
+		return DnaRpcInvalidParameter
+	}
+	var hash Uint256
+	if err := hash.Deserialize(bytes.NewReader(hex)); err != nil {
+		return DnaRpcInvalidHash
+	}
+	t := trace.DefaultStore.Get(hash)
+	if t == nil {
+		return DnaRpcUnknownTransaction
+	}
+	return DnaRpc(t)
+}
+
+// A JSON example for getcontractcoverage method as following, the node must run with EnableTrace:
+//   {"jsonrpc": "2.0", "method": "getcontractcoverage", "params": ["contract code hash in hex"], "id": 0}
+func getContractCoverage(params []interface{}) map[string]interface{} {
+	if !config.Parameters.EnableTrace {
+		return DnaRpcTraceDisabled
+	}
+	if len(params) < 1 {
+		return DnaRpcNil
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return DnaRpcInvalidParameter
+	}
+	hex, err := hex.DecodeString(str)
+	if err != nil {
+		return DnaRpcInvalidParameter
+	}
+	var hash Uint160
+	if err := hash.Deserialize(bytes.NewReader(hex)); err != nil {
+		return DnaRpcInvalidHash
+	}
+	contract, err := ledger.DefaultLedger.Store.GetContract(hash)
+	if err != nil || contract == nil {
+		return DnaRpcUnknownContract
+	}
+	return DnaRpc(trace.DefaultStore.Report(hash, contract.Code.Code))
+}
+
 func getVersion(params []interface{}) map[string]interface{} {
 	return DnaRpc(config.Version)
 }
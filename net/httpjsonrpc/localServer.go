@@ -41,6 +41,8 @@ func StartLocalServer() {
 	HandleFunc("stopconsensus", stopConsensus)
 	HandleFunc("sendsampletransaction", sendSampleTransaction)
 	HandleFunc("setdebuginfo", setDebugInfo)
+	HandleFunc("gettxtrace", getTxTrace)
+	HandleFunc("getcontractcoverage", getContractCoverage)
 
 	// TODO: only listen to local host
 	err := http.ListenAndServe(":" + strconv.Itoa(Parameters.HttpLocalPort), nil)
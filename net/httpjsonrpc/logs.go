@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package httpjsonrpc
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/core/ledger"
+	"github.com/Ontology/core/states"
+	"github.com/Ontology/errors"
+	sc "github.com/Ontology/smartcontract/common"
+	"github.com/Ontology/vm/neovm"
+	"github.com/Ontology/vm/neovm/types"
+)
+
+type NotifyInfo struct {
+	Height    uint32
+	TxHash    string
+	Index     uint32
+	CodeHash  string
+	EventName string
+	State     []sc.States
+	Event     *NotifyEventInfo `json:",omitempty"`
+}
+
+func TransNotifyStateToInfo(n *states.NotifyState) *NotifyInfo {
+	info := &NotifyInfo{
+		Height:    n.Height,
+		TxHash:    ToHexString(n.TxHash.ToArray()),
+		Index:     n.Index,
+		CodeHash:  ToHexString(n.CodeHash.ToArray()),
+		EventName: n.EventName,
+	}
+	item, err := types.DeserializeStackItem(n.State, int(neovm.MaxArraySize), int(neovm.MaxItemSize))
+	if err == nil {
+		info.State = sc.ConvertTypes(item)
+		info.Event = DecodeNotify(n.CodeHash, item)
+	}
+	return info
+}
+
+// NewNotifyFilter builds a notification filter, contract is the code hash in
+// hex or empty.
+func NewNotifyFilter(contract, event string, fromHeight, toHeight uint32, offset, limit int) (*ledger.NotifyFilter, error) {
+	filter := &ledger.NotifyFilter{
+		EventName:  event,
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+		Offset:     offset,
+		Limit:      limit,
+	}
+	if toHeight != 0 && toHeight < fromHeight {
+		return nil, errors.NewErr("[NewNotifyFilter] Invalid height range.")
+	}
+	if offset < 0 || limit < 0 {
+		return nil, errors.NewErr("[NewNotifyFilter] Invalid offset or limit.")
+	}
+	if contract != "" {
+		b, err := hex.DecodeString(contract)
+		if err != nil {
+			return nil, err
+		}
+		var hash Uint160
+		if err := hash.Deserialize(bytes.NewReader(b)); err != nil {
+			return nil, err
+		}
+		filter.CodeHash = &hash
+	}
+	return filter, nil
+}
+
+// GetNotifyInfos runs the filter against the ledger.
+func GetNotifyInfos(filter *ledger.NotifyFilter) ([]*NotifyInfo, error) {
+	notifications, err := ledger.DefaultLedger.Store.GetNotifications(filter)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]*NotifyInfo, 0, len(notifications))
+	for _, n := range notifications {
+		infos = append(infos, TransNotifyStateToInfo(n))
+	}
+	return infos, nil
+}
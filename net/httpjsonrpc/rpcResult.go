@@ -27,6 +27,11 @@ var (
 
 	DnaRpcUnknownBlock = responsePacking("unknown block")
 	DnaRpcUnknownTransaction = responsePacking("unknown transaction")
+	DnaRpcUnknownContract = responsePacking("unknown contract")
+	DnaRpcUnknownIdentity = responsePacking("unknown identity")
+	DnaRpcUnknownClaim = responsePacking("unknown claim")
+	DnaRpcUnknownAsset = responsePacking("unknown asset")
+	DnaRpcTraceDisabled = responsePacking("tracing is disabled")
 
 	DnaRpcNil = responsePacking(nil)
 	DnaRpcUnsupported = responsePacking("Unsupported")
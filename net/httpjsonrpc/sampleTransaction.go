@@ -22,6 +22,7 @@ package httpjsonrpc
 import (
 	. "github.com/Ontology/account"
 	. "github.com/Ontology/common"
+	"github.com/Ontology/common/config"
 	"github.com/Ontology/common/log"
 	. "github.com/Ontology/core/asset"
 	"github.com/Ontology/core/contract"
@@ -41,6 +42,7 @@ func NewRegTx(rand string, index int, admin, issuer *Account) *transaction.Trans
 	amount := Fixed64(1000)
 	controller, _ := contract.CreateSignatureContract(admin.PubKey())
 	tx, _ := transaction.NewRegisterAssetTransaction(asset, amount, issuer.PubKey(), controller.ProgramHash)
+	tx.SetNetworkMagic(uint32(config.Parameters.Magic))
 	return tx
 }
 
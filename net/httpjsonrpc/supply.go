@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package httpjsonrpc
+
+import (
+	. "github.com/Ontology/common"
+	"github.com/Ontology/core/ledger"
+)
+
+// AssetSupplyInfo breaks down the supply of an asset. Amount is the amount
+// registered, negative when unlimited.
+type AssetSupplyInfo struct {
+	AssetID     string
+	Amount      Fixed64
+	Issued      Fixed64
+	Burned      Fixed64
+	Circulating Fixed64
+}
+
+func GetAssetSupplyInfo(assetID Uint256) (*AssetSupplyInfo, error) {
+	asset, err := ledger.DefaultLedger.Store.GetAsset(assetID)
+	if err != nil {
+		return nil, err
+	}
+	return &AssetSupplyInfo{
+		AssetID:     ToHexString(assetID.ToArray()),
+		Amount:      asset.Amount,
+		Issued:      asset.Issued(),
+		Burned:      asset.Burned,
+		Circulating: asset.Circulating(),
+	}, nil
+}
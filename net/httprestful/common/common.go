@@ -100,12 +100,13 @@ func GetTotalIssued(cmd map[string]interface{}) map[string]interface{} {
 		resp["Error"] = Err.INVALID_PARAMS
 		return resp
 	}
-	amount, err := ledger.DefaultLedger.Store.GetQuantityIssued(assetHash)
+	// destroyed amounts are not counted
+	asset, err := ledger.DefaultLedger.Store.GetAsset(assetHash)
 	if err != nil {
 		resp["Error"] = Err.INVALID_PARAMS
 		return resp
 	}
-	val := float64(amount) / math.Pow(10, 8)
+	val := float64(asset.Circulating()) / math.Pow(10, 8)
 	//valStr := strconv.FormatFloat(val, 'f', -1, 64)
 	resp["Result"] = val
 	return resp
@@ -370,9 +371,12 @@ func GetUnspendOutput(cmd map[string]interface{}) map[string]interface{} {
 		return resp
 	}
 	type UTXOUnspentInfo struct {
-		Txid  string
-		Index uint32
-		Value string
+		Txid      string
+		Index     uint32
+		Value     string
+		LockType  string `json:",omitempty"`
+		LockValue uint32 `json:",omitempty"`
+		Locked    bool
 	}
 	infos, err := ledger.DefaultLedger.Store.GetUnspentFromProgramHash(programHash, assetHash)
 	if err != nil {
@@ -383,7 +387,18 @@ func GetUnspendOutput(cmd map[string]interface{}) map[string]interface{} {
 	var UTXOoutputs []UTXOUnspentInfo
 	for _, v := range infos {
 		val := strconv.FormatInt(int64(v.Value), 10)
-		UTXOoutputs = append(UTXOoutputs, UTXOUnspentInfo{Txid: ToHexString(v.Txid.ToArray()), Index: v.Index, Value: val})
+		output, err := ledger.DefaultLedger.GetUnspentLock(v)
+		if err != nil {
+			resp["Error"] = Err.INTERNAL_ERROR
+			return resp
+		}
+		locked, err := ledger.DefaultLedger.IsLocked(output.LockType, output.LockValue)
+		if err != nil {
+			resp["Error"] = Err.INTERNAL_ERROR
+			return resp
+		}
+		UTXOoutputs = append(UTXOoutputs, UTXOUnspentInfo{Txid: ToHexString(v.Txid.ToArray()), Index: v.Index, Value: val,
+			LockType: OutputLockName(output.LockType), LockValue: output.LockValue, Locked: locked})
 	}
 	resp["Result"] = UTXOoutputs
 	return resp
@@ -417,6 +432,9 @@ func GetTransactionByHash(cmd map[string]interface{}) map[string]interface{} {
 		return resp
 	}
 	tran := TransArryByteToHexString(tx)
+	if disasm, ok := cmd["Disasm"].(string); ok && disasm == "1" {
+		AddPayloadDisasm(tran.Payload, tx.Payload)
+	}
 	resp["Result"] = tran
 	return resp
 }
@@ -491,6 +509,145 @@ func GetSmartCodeEvent(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+// GetDDO resolves the document of the ONT ID Id.
+func GetDDO(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(Err.SUCCESS)
+	id, _ := cmd["Id"].(string)
+	if len(id) == 0 {
+		resp["Error"] = Err.INVALID_PARAMS
+		return resp
+	}
+	ddo, err := ledger.DefaultLedger.GetIdentity([]byte(id))
+	if err != nil {
+		resp["Error"] = Err.INTERNAL_ERROR
+		return resp
+	}
+	if ddo == nil {
+		resp["Error"] = Err.UNKNOWN_IDENTITY
+		return resp
+	}
+	resp["Result"] = TransIdentityStateToDDO(ddo)
+	return resp
+}
+
+// GetClaimStatus returns the status and the state proof of the claim Hash.
+func GetClaimStatus(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(Err.SUCCESS)
+	str, _ := cmd["Hash"].(string)
+	bys, err := HexToBytes(str)
+	if err != nil {
+		resp["Error"] = Err.INVALID_PARAMS
+		return resp
+	}
+	var hash Uint256
+	if err := hash.Deserialize(bytes.NewReader(bys)); err != nil {
+		resp["Error"] = Err.INVALID_PARAMS
+		return resp
+	}
+	info, err := GetClaimStatusInfo(hash)
+	if err != nil {
+		resp["Error"] = Err.INTERNAL_ERROR
+		return resp
+	}
+	if info == nil {
+		resp["Error"] = Err.UNKNOWN_CLAIM
+		return resp
+	}
+	resp["Result"] = info
+	return resp
+}
+
+// GetAssetSupply returns the issued, burned and circulating amount of the
+// asset Assetid.
+func GetAssetSupply(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(Err.SUCCESS)
+	str, _ := cmd["Assetid"].(string)
+	bys, err := HexToBytes(str)
+	if err != nil {
+		resp["Error"] = Err.INVALID_PARAMS
+		return resp
+	}
+	var assetID Uint256
+	if err := assetID.Deserialize(bytes.NewReader(bys)); err != nil {
+		resp["Error"] = Err.INVALID_PARAMS
+		return resp
+	}
+	info, err := GetAssetSupplyInfo(assetID)
+	if err != nil {
+		resp["Error"] = Err.UNKNOWN_ASSET
+		return resp
+	}
+	resp["Result"] = info
+	return resp
+}
+
+// GetFreezeStatus returns whether the asset Assetid is frozen, and whether
+// the address Addr is frozen within it when given.
+func GetFreezeStatus(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(Err.SUCCESS)
+	str, _ := cmd["Assetid"].(string)
+	bys, err := HexToBytes(str)
+	if err != nil {
+		resp["Error"] = Err.INVALID_PARAMS
+		return resp
+	}
+	var assetID Uint256
+	if err := assetID.Deserialize(bytes.NewReader(bys)); err != nil {
+		resp["Error"] = Err.INVALID_PARAMS
+		return resp
+	}
+	var programHash *Uint160
+	if addr, _ := cmd["Addr"].(string); addr != "" {
+		ph, err := ToScriptHash(addr)
+		if err != nil {
+			resp["Error"] = Err.INVALID_PARAMS
+			return resp
+		}
+		programHash = &ph
+	}
+	info, err := GetFreezeStatusInfo(assetID, programHash)
+	if err != nil {
+		resp["Error"] = Err.UNKNOWN_ASSET
+		return resp
+	}
+	resp["Result"] = info
+	return resp
+}
+
+// GetLogs returns persisted notifications. The optional Contract, Event,
+// From, To, Offset and Limit parameters filter and page them.
+func GetLogs(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(Err.SUCCESS)
+	numbers := make(map[string]int64)
+	for _, key := range []string{"From", "To", "Offset", "Limit"} {
+		str, _ := cmd[key].(string)
+		if len(str) == 0 {
+			continue
+		}
+		n, err := strconv.ParseInt(str, 10, 64)
+		if err != nil || n < 0 || n > math.MaxUint32 {
+			resp["Error"] = Err.INVALID_PARAMS
+			return resp
+		}
+		numbers[key] = n
+	}
+	contract, _ := cmd["Contract"].(string)
+	event, _ := cmd["Event"].(string)
+	filter, err := NewNotifyFilter(contract, event, uint32(numbers["From"]), uint32(numbers["To"]),
+		int(numbers["Offset"]), int(numbers["Limit"]))
+	if err != nil {
+		resp["Error"] = Err.INVALID_PARAMS
+		return resp
+	}
+	infos, err := GetNotifyInfos(filter)
+	if err != nil {
+		resp["Error"] = Err.INTERNAL_ERROR
+		return resp
+	}
+	resp["Result"] = infos
+	return resp
+}
+
 func ResponsePack(errCode int64) map[string]interface{} {
 	resp := map[string]interface{}{
 		"Action":  "",
@@ -515,12 +672,21 @@ func GetContract(cmd map[string]interface{}) map[string]interface{} {
 		resp["Error"] = Err.INVALID_PARAMS
 		return resp
 	}
-	//TODO GetContract from store
-	//contract, err := ledger.DefaultLedger.Store.GetContract(hash)
-	//if err != nil {
-	//	resp["Error"] = Err.INVALID_PARAMS
-	//	return resp
-	//}
-	//resp["Result"] = string(contract)
+	contract, err := ledger.DefaultLedger.Store.GetContract(hash)
+	if err != nil || contract == nil {
+		resp["Error"] = Err.UNKNOWN_CONTRACT
+		return resp
+	}
+	if raw, ok := cmd["Raw"].(string); ok && raw == "1" {
+		w := bytes.NewBuffer(nil)
+		contract.Serialize(w)
+		resp["Result"] = ToHexString(w.Bytes())
+		return resp
+	}
+	info := TransContractStateToInfo(hash, contract)
+	if disasm, ok := cmd["Disasm"].(string); ok && disasm == "1" {
+		AddContractDisasm(info, contract)
+	}
+	resp["Result"] = info
 	return resp
 }
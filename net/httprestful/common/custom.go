@@ -21,6 +21,7 @@ package common
 
 import (
 	. "github.com/Ontology/common"
+	"github.com/Ontology/common/config"
 	tx "github.com/Ontology/core/transaction"
 	. "github.com/Ontology/errors"
 	. "github.com/Ontology/net/httpjsonrpc"
@@ -110,6 +111,7 @@ func SendRecord(cmd map[string]interface{}) map[string]interface{} {
 	var outputs []*utxo.TxOutput
 
 	transferTx, _ := tx.NewTransferAssetTransaction(inputs, outputs)
+	transferTx.SetNetworkMagic(uint32(config.Parameters.Magic))
 
 	rcdInner := tx.NewTxAttribute(tx.Description, innerTime)
 	transferTx.Attributes = append(transferTx.Attributes, &rcdInner)
@@ -145,6 +147,7 @@ func SendRecordTransaction(cmd map[string]interface{}) map[string]interface{} {
 	}
 	recordType := "record"
 	recordTx, _ := tx.NewRecordTransaction(recordType, recordData)
+	recordTx.SetNetworkMagic(uint32(config.Parameters.Magic))
 
 	hash := recordTx.Hash()
 	resp["Result"] = ToHexString(hash.ToArray())
@@ -40,6 +40,9 @@ const (
 	UNKNOWN_TRANSACTION int64 = 44001
 	UNKNOWN_ASSET int64 = 44002
 	UNKNOWN_BLOCK int64 = 44003
+	UNKNOWN_CONTRACT int64 = 44005
+	UNKNOWN_IDENTITY int64 = 44006
+	UNKNOWN_CLAIM int64 = 44007
 
 	INVALID_VERSION int64 = 45001
 	INTERNAL_ERROR int64 = 45002
@@ -67,6 +70,9 @@ var ErrMap = map[int64]string{
 	UNKNOWN_TRANSACTION: "UNKNOWN TRANSACTION",
 	UNKNOWN_ASSET:       "UNKNOWN ASSET",
 	UNKNOWN_BLOCK:       "UNKNOWN BLOCK",
+	UNKNOWN_CONTRACT:    "UNKNOWN CONTRACT",
+	UNKNOWN_IDENTITY:    "UNKNOWN IDENTITY",
+	UNKNOWN_CLAIM:       "UNKNOWN CLAIM",
 
 	INVALID_VERSION:                "INVALID VERSION",
 	INTERNAL_ERROR:                 "INTERNAL ERROR",
@@ -82,4 +88,6 @@ var ErrMap = map[int64]string{
 	int64(ErrStateUpdaterVaild):    "INTERNAL ERROR, ErrStateUpdaterVaild",
 	int64(ErrSummaryAsset):         "INTERNAL ERROR, ErrSummaryAsset",
 	int64(ErrXmitFail):             "INTERNAL ERROR, ErrXmitFail",
+	int64(ErrTxExpired):            "INTERNAL ERROR, ErrTxExpired",
+	int64(ErrNetworkMagic):         "INTERNAL ERROR, ErrNetworkMagic",
 }
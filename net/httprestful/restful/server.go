@@ -76,6 +76,11 @@ const (
 	Api_Restart = "/api/v1/restart"
 	Api_GetContract = "/api/v1/contract/:hash"
 	Api_GetSmartCodeEvent = "/api/v1/smartcode/event/:height"
+	Api_GetLogs = "/api/v1/logs"
+	Api_GetDDO = "/api/v1/identity/ddo/:id"
+	Api_GetClaimStatus = "/api/v1/claim/status/:hash"
+	Api_GetFreezeStatus = "/api/v1/freeze/:assetid"
+	Api_GetAssetSupply = "/api/v1/asset/supply/:assetid"
 )
 
 func InitRestServer(checkAccessToken func(string, string) (string, int64, interface{})) ApiServer {
@@ -175,6 +180,11 @@ func (rt *restServer) registryMethod() {
 		Api_Restart:             {name: "restart", handler: rt.Restart},
 		Api_GetStateUpdate:      {name: "getstateupdate", handler: GetStateUpdate},
 		Api_GetSmartCodeEvent:{name: "getsmartcodeevent", handler: GetSmartCodeEvent},
+		Api_GetLogs:             {name: "getlogs", handler: GetLogs},
+		Api_GetDDO:              {name: "getddo", handler: GetDDO},
+		Api_GetClaimStatus:      {name: "getclaimstatus", handler: GetClaimStatus},
+		Api_GetFreezeStatus:     {name: "getfreezestatus", handler: GetFreezeStatus},
+		Api_GetAssetSupply:      {name: "getassetsupply", handler: GetAssetSupply},
 	}
 
 	sendRawTransaction := func(cmd map[string]interface{}) map[string]interface{} {
@@ -222,12 +232,20 @@ func (rt *restServer) getPath(url string) string {
 		return Api_GetUTXObyAddr
 	} else if strings.Contains(url, strings.TrimRight(Api_GetUTXObyAsset, ":addr/:assetid")) {
 		return Api_GetUTXObyAsset
+	} else if strings.Contains(url, strings.TrimRight(Api_GetAssetSupply, ":assetid")) {
+		return Api_GetAssetSupply
 	} else if strings.Contains(url, strings.TrimRight(Api_Getasset, ":hash")) {
 		return Api_Getasset
 	} else if strings.Contains(url, strings.TrimRight(Api_GetStateUpdate, ":namespace/:key")) {
 		return Api_GetStateUpdate
 	} else if strings.Contains(url, strings.TrimRight(Api_GetSmartCodeEvent, ":height")) {
 		return Api_GetSmartCodeEvent
+	} else if strings.Contains(url, strings.TrimRight(Api_GetDDO, ":id")) {
+		return Api_GetDDO
+	} else if strings.Contains(url, strings.TrimRight(Api_GetClaimStatus, ":hash")) {
+		return Api_GetClaimStatus
+	} else if strings.Contains(url, strings.TrimRight(Api_GetFreezeStatus, ":assetid")) {
+		return Api_GetFreezeStatus
 	}
 	return url
 }
@@ -257,10 +275,12 @@ func (rt *restServer) getParams(r *http.Request, url string, req map[string]inte
 	case Api_Gettransaction:
 		req["Hash"] = getParam(r, "hash")
 		req["Raw"] = r.FormValue("raw")
+		req["Disasm"] = r.FormValue("disasm")
 		break
 	case Api_GetContract:
 		req["Hash"] = getParam(r, "hash")
 		req["Raw"] = r.FormValue("raw")
+		req["Disasm"] = r.FormValue("disasm")
 		break
 	case Api_Getasset:
 		req["Hash"] = getParam(r, "hash")
@@ -299,6 +319,27 @@ func (rt *restServer) getParams(r *http.Request, url string, req map[string]inte
 	case Api_GetSmartCodeEvent:
 		req["Height"] = getParam(r, "height")
 		break
+	case Api_GetDDO:
+		req["Id"] = getParam(r, "id")
+		break
+	case Api_GetClaimStatus:
+		req["Hash"] = getParam(r, "hash")
+		break
+	case Api_GetAssetSupply:
+		req["Assetid"] = getParam(r, "assetid")
+		break
+	case Api_GetFreezeStatus:
+		req["Assetid"] = getParam(r, "assetid")
+		req["Addr"] = r.FormValue("addr")
+		break
+	case Api_GetLogs:
+		req["Contract"] = r.FormValue("contract")
+		req["Event"] = r.FormValue("event")
+		req["From"] = r.FormValue("from")
+		req["To"] = r.FormValue("to")
+		req["Offset"] = r.FormValue("offset")
+		req["Limit"] = r.FormValue("limit")
+		break
 	case Api_OauthServerUrl:
 	case Api_NoticeServerUrl:
 	case Api_NoticeServerState:
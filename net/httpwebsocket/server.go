@@ -25,6 +25,7 @@ import (
 	. "github.com/Ontology/common/config"
 	"github.com/Ontology/core/ledger"
 	"github.com/Ontology/events"
+	"github.com/Ontology/net/httpjsonrpc"
 	"github.com/Ontology/net/httprestful/common"
 	Err "github.com/Ontology/net/httprestful/error"
 	"github.com/Ontology/net/httpwebsocket/websocket"
@@ -128,12 +129,14 @@ func PushSmartCodeEvent(v interface{}) {
 					Container string
 					CodeHash  string
 					State     []sc.States
+					Event     *httpjsonrpc.NotifyEventInfo `json:",omitempty"`
 					BlockHeight uint32
 				}
 				msg := NotifyEventArgsInfo{
 					Container: ToHexString(object.Container.ToArray()),
 					CodeHash:  ToHexString(object.CodeHash.ToArray()),
 					State:   sc.ConvertTypes(object.State),
+					Event:   httpjsonrpc.DecodeNotify(object.CodeHash, object.State),
 					BlockHeight: ledger.DefaultLedger.Store.GetHeight(),
 				}
 				PushEvent(rs["TxHash"].(string),rs["Error"].(int64),rs["Action"].(string),msg)
@@ -99,6 +99,8 @@ func (this *TXNPool) CleanSubmittedTransactions(block *ledger.Block) error {
 	this.cleanTransactionList(block.Transactions)
 	this.cleanUTXOList(block.Transactions)
 	this.cleanIssueSummary(block.Transactions)
+	this.cleanFrozenTransactions(block.Transactions)
+	this.cleanExpiredTransactions(block.Header.Height + 1)
 	return nil
 }
 
@@ -238,6 +240,26 @@ func (this *TXNPool) cleanTransactionList(txns []*transaction.Transaction) error
 	return nil
 }
 
+// clean the transactions moving coins frozen by the committed transactions.
+func (this *TXNPool) cleanFrozenTransactions(txns []*transaction.Transaction) {
+	frozen := false
+	for _, txn := range txns {
+		if txn.TxType == transaction.Freeze && txn.Payload.(*payload.Freeze).Frozen {
+			frozen = true
+			break
+		}
+	}
+	if !frozen {
+		return
+	}
+	for _, txn := range this.copytxnList() {
+		if err := va.CheckTransactionBalance(txn); err != nil {
+			log.Info(fmt.Sprintf("Transaction =%x removed from TxPool: %v", txn.Hash(), err))
+			this.removeTransaction(txn)
+		}
+	}
+}
+
 func (this *TXNPool) addtxnList(txn *transaction.Transaction) bool {
 	this.Lock()
 	defer this.Unlock()
@@ -343,3 +365,14 @@ func (this *TXNPool) getAssetIssueAmount(assetId common.Uint256) common.Fixed64
 	defer this.RUnlock()
 	return this.issueSummary[assetId]
 }
+
+// cleanExpiredTransactions removes the transactions which can not be included
+// in the block of the given height anymore.
+func (this *TXNPool) cleanExpiredTransactions(height uint32) {
+	for _, txn := range this.copytxnList() {
+		if err := va.CheckTransactionExpiry(txn, height); err != nil {
+			log.Info(fmt.Sprintf("Transaction =%x removed from TxPool: %v", txn.Hash(), err))
+			this.removeTransaction(txn)
+		}
+	}
+}
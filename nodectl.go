@@ -27,11 +27,15 @@ import (
 	"github.com/Ontology/cli/asset"
 	"github.com/Ontology/cli/bookkeeper"
 	. "github.com/Ontology/cli/common"
+	"github.com/Ontology/cli/contract"
 	"github.com/Ontology/cli/data"
+	"github.com/Ontology/cli/db"
 	"github.com/Ontology/cli/debug"
 	"github.com/Ontology/cli/info"
 	"github.com/Ontology/cli/privpayload"
 	"github.com/Ontology/cli/test"
+	"github.com/Ontology/cli/tx"
+	"github.com/Ontology/cli/vm"
 	"github.com/Ontology/cli/wallet"
 
 	"github.com/urfave/cli"
@@ -63,6 +67,10 @@ func main() {
 		*privpayload.NewCommand(),
 		*data.NewCommand(),
 		*bookkeeper.NewCommand(),
+		*db.NewCommand(),
+		*vm.NewCommand(),
+		*contract.NewCommand(),
+		*tx.NewCommand(),
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 	sort.Sort(cli.FlagsByName(app.Flags))
@@ -22,6 +22,8 @@ package common
 import (
 	"github.com/Ontology/vm/neovm/types"
 	"github.com/Ontology/common"
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract"
 	"fmt"
 	"reflect"
 )
@@ -56,6 +58,13 @@ func ConvertTypes(item types.StackItemInterface) (results []States) {
 			arr = append(arr, ConvertTypes(val)...)
 		}
 		results = append(results, States{"Array", arr})
+	case *types.Map:
+		m := make(map[string][]States)
+		for _, key := range v.GetKeys() {
+			value, _ := v.TryGetValue(key)
+			m[common.ToHexString(key.GetByteArray())] = ConvertTypes(value)
+		}
+		results = append(results, States{"Map", m})
 	case *types.InteropInterface:
 		results = append(results, States{"InteropInterface", common.ToHexString(v.GetInterface().ToArray())})
 	case types.StackItemInterface:
@@ -83,6 +92,13 @@ func ConvertReturnTypes(item types.StackItemInterface) (results []interface{}) {
 			arr = append(arr, ConvertReturnTypes(val)...)
 		}
 		results = append(results, arr)
+	case *types.Map:
+		m := make(map[string]interface{})
+		for _, key := range v.GetKeys() {
+			value, _ := v.TryGetValue(key)
+			m[common.ToHexString(key.GetByteArray())] = ConvertReturnTypes(value)
+		}
+		results = append(results, m)
 	case *types.InteropInterface:
 		results = append(results, common.ToHexString(v.GetInterface().ToArray()))
 	case types.StackItemInterface:
@@ -91,4 +107,52 @@ func ConvertReturnTypes(item types.StackItemInterface) (results []interface{}) {
 		panic("[ConvertTypes] Invalid Types!")
 	}
 	return
-}
\ No newline at end of file
+}
+// ConvertTypedValue converts a stack item to the JSON value of the declared
+// ABI parameter type.
+func ConvertTypedValue(t contract.ContractParameterType, item types.StackItemInterface) interface{} {
+	if item == nil {
+		return nil
+	}
+	if _, ok := item.(*types.InteropInterface); ok {
+		t = contract.InteropInterface
+	}
+	switch t {
+	case contract.Boolean:
+		return item.GetBoolean()
+	case contract.Integer:
+		return item.GetBigInteger()
+	case contract.String:
+		return string(item.GetByteArray())
+	case contract.Signature, contract.Hash160, contract.Hash256, contract.ByteArray, contract.PublicKey:
+		return common.ToHexString(item.GetByteArray())
+	}
+	if results := ConvertReturnTypes(item); len(results) > 0 {
+		return results[0]
+	}
+	return nil
+}
+
+// DecodeEvent matches the state of a Runtime.Notify, an array of the event
+// name followed by one item per parameter, with the events declared by abi.
+// It returns the event and the values of its parameters converted to their
+// declared types, or nil if abi declares no such event.
+func DecodeEvent(abi *code.ABI, state types.StackItemInterface) (*code.ABIEvent, []interface{}) {
+	arr, ok := state.(*types.Array)
+	if !ok || abi == nil || len(arr.GetArray()) == 0 {
+		return nil, nil
+	}
+	name, ok := arr.GetArray()[0].(*types.ByteArray)
+	if !ok {
+		return nil, nil
+	}
+	event := abi.GetEvent(string(name.GetByteArray()))
+	if event == nil || len(event.Parameters) != len(arr.GetArray())-1 {
+		return nil, nil
+	}
+	values := make([]interface{}, 0, len(event.Parameters))
+	for i, p := range event.Parameters {
+		values = append(values, ConvertTypedValue(p.Type, arr.GetArray()[i+1]))
+	}
+	return event, values
+}
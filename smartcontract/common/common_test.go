@@ -23,6 +23,10 @@ import (
 	"testing"
 	"github.com/Ontology/vm/neovm/types"
 	"math/big"
+	"reflect"
+
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract"
 )
 
 func TestConvertTypes(t *testing.T) {
@@ -42,3 +46,49 @@ func TestConvertReturnTypes(t *testing.T) {
 	}
 	t.Log("result:", states)
 }
+
+func TestDecodeEvent(t *testing.T) {
+	abi := &code.ABI{
+		Events: []*code.ABIEvent{{
+			Name: "transfer",
+			Parameters: []*code.ABIParameter{
+				{Name: "from", Type: contract.Hash160},
+				{Name: "memo", Type: contract.String},
+				{Name: "amount", Type: contract.Integer},
+				{Name: "ok", Type: contract.Boolean},
+			},
+		}},
+	}
+	notify := func(items ...types.StackItemInterface) types.StackItemInterface {
+		return types.NewArray(append([]types.StackItemInterface{types.NewByteArray([]byte("transfer"))}, items...))
+	}
+	from := types.NewByteArray([]byte{0xab, 0xcd})
+	memo := types.NewByteArray([]byte("hi"))
+	// values are pushed as byte arrays and converted to the declared types
+	amount := types.NewByteArray(types.ConvertBigIntegerToBytes(big.NewInt(1000)))
+	ok := types.NewInteger(big.NewInt(1))
+
+	event, values := DecodeEvent(abi, notify(from, memo, amount, ok))
+	if event == nil || event.Name != "transfer" {
+		t.Fatalf("got event %v", event)
+	}
+	want := []interface{}{"abcd", "hi", big.NewInt(1000), true}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("got %v, want %v", values, want)
+	}
+
+	undeclared := []types.StackItemInterface{
+		notify(from, memo, amount),
+		types.NewArray([]types.StackItemInterface{types.NewByteArray([]byte("approve")), from, memo, amount, ok}),
+		types.NewArray([]types.StackItemInterface{}),
+		types.NewByteArray([]byte("transfer")),
+	}
+	for _, state := range undeclared {
+		if event, _ := DecodeEvent(abi, state); event != nil {
+			t.Errorf("%v: got event %s", state, event.Name)
+		}
+	}
+	if event, _ := DecodeEvent(nil, notify(from, memo, amount, ok)); event != nil {
+		t.Errorf("no ABI: got event %s", event.Name)
+	}
+}
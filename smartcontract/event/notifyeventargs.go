@@ -30,3 +30,20 @@ type NotifyEventArgs struct {
 	State     types.StackItemInterface
 }
 
+
+// EventName returns the first notified item as a string, by convention the
+// name of the event. It is empty if that item is not a byte array.
+func (n *NotifyEventArgs) EventName() string {
+	item := n.State
+	switch item.(type) {
+	case *types.Array, *types.Struct:
+		if len(item.GetArray()) == 0 {
+			return ""
+		}
+		item = item.GetArray()[0]
+	}
+	if b, ok := item.(*types.ByteArray); ok {
+		return string(b.GetByteArray())
+	}
+	return ""
+}
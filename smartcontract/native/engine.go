@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package native
+
+import (
+	"github.com/Ontology/common"
+	sig "github.com/Ontology/core/signature"
+	"github.com/Ontology/errors"
+	"github.com/Ontology/smartcontract/service"
+	"github.com/Ontology/vm/neovm"
+	vmtypes "github.com/Ontology/vm/neovm/types"
+)
+
+// NativeEngine runs a native contract invoked by an InvokeCode transaction.
+type NativeEngine struct {
+	stateMachine *service.StateMachine
+	container    sig.SignableData
+	result       vmtypes.StackItemInterface
+}
+
+func NewNativeEngine(stateMachine *service.StateMachine, container sig.SignableData) *NativeEngine {
+	return &NativeEngine{
+		stateMachine: stateMachine,
+		container:    container,
+	}
+}
+
+// Create fails, native contracts are registered at startup and never deployed.
+func (e *NativeEngine) Create(caller common.Uint160, code []byte) ([]byte, error) {
+	return nil, errors.NewErr("[NativeEngine] Native contract can not be deployed!")
+}
+
+// Call runs the method encoded in input on the native contract whose code
+// hash is code.
+func (e *NativeEngine) Call(caller common.Uint160, code, input []byte) ([]byte, error) {
+	codeHash, err := common.Uint160ParseFromBytes(code)
+	if err != nil {
+		return nil, err
+	}
+	c := GetContract(codeHash)
+	if c == nil {
+		return nil, errors.NewErr("[NativeEngine] Native contract not found!")
+	}
+	method, args, err := DecodeInput(input)
+	if err != nil {
+		return nil, err
+	}
+	ctx := &Context{
+		StateMachine: e.stateMachine,
+		Container:    e.container,
+		CodeHash:     codeHash,
+		Caller:       caller,
+	}
+	ret, err := c.Invoke(ctx, method, args)
+	if err != nil {
+		return nil, err
+	}
+	e.result = ret
+	return nil, nil
+}
+
+// Result returns the value returned by the last call.
+func (e *NativeEngine) Result() vmtypes.StackItemInterface {
+	return e.result
+}
+
+// Invoker dispatches APPCALL and TAILCALL from NeoVM to native contracts. The
+// caller pushes the argument array and then the method name, like a NeoVM
+// contract entry point expects them, and gets the result pushed back.
+type Invoker struct {
+	stateMachine *service.StateMachine
+	container    sig.SignableData
+}
+
+func NewInvoker(stateMachine *service.StateMachine, container sig.SignableData) *Invoker {
+	return &Invoker{
+		stateMachine: stateMachine,
+		container:    container,
+	}
+}
+
+func (i *Invoker) IsNative(codeHash []byte) bool {
+	hash, err := common.Uint160ParseFromBytes(codeHash)
+	if err != nil {
+		return false
+	}
+	return GetContract(hash) != nil
+}
+
+func (i *Invoker) Invoke(e *neovm.ExecutionEngine, codeHash []byte) error {
+	hash, err := common.Uint160ParseFromBytes(codeHash)
+	if err != nil {
+		return err
+	}
+	c := GetContract(hash)
+	if c == nil {
+		return errors.NewErr("[Invoker] Native contract not found!")
+	}
+	if neovm.EvaluationStackCount(e) < 2 {
+		return errors.NewErr("[Invoker] Too few input parameters!")
+	}
+	method := neovm.PopByteArray(e)
+	args := neovm.PopStackItem(e)
+	if _, ok := args.(*vmtypes.Array); !ok {
+		return errors.NewErr("[Invoker] Arguments must be an array!")
+	}
+	ctx := &Context{
+		StateMachine: i.stateMachine,
+		Container:    i.container,
+		CodeHash:     hash,
+	}
+	if context, err := e.CurrentContext(); err == nil {
+		if ctx.Caller, err = context.GetCodeHash(); err != nil {
+			return err
+		}
+	}
+	ret, err := c.Invoke(ctx, string(method), args.GetArray())
+	if err != nil {
+		return err
+	}
+	neovm.PushData(e, ret)
+	return nil
+}
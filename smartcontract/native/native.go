@@ -0,0 +1,230 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package native runs contracts implemented in Go. They live at reserved code
+// hashes, are registered at startup and are invoked either by an InvokeCode
+// transaction or with APPCALL from NeoVM.
+package native
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/contract"
+	sig "github.com/Ontology/core/signature"
+	"github.com/Ontology/core/states"
+	"github.com/Ontology/core/store"
+	"github.com/Ontology/errors"
+	"github.com/Ontology/smartcontract/service"
+	"github.com/Ontology/smartcontract/types"
+	"github.com/Ontology/vm/neovm"
+	vmtypes "github.com/Ontology/vm/neovm/types"
+)
+
+// Context is the invocation a native method runs in. Storage goes through
+// StateMachine.CloneCache, which is committed when the invocation succeeds.
+type Context struct {
+	StateMachine *service.StateMachine
+	Container    sig.SignableData
+	CodeHash     common.Uint160
+	Caller       common.Uint160
+}
+
+// Get returns the value stored under key by the contract, nil if there is none.
+func (ctx *Context) Get(key []byte) ([]byte, error) {
+	k, err := ctx.storageKey(key)
+	if err != nil {
+		return nil, err
+	}
+	item, err := ctx.StateMachine.CloneCache.Get(store.ST_Storage, k)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	return item.(*states.StorageItem).Value, nil
+}
+
+// Put stores value under key for the contract.
+func (ctx *Context) Put(key, value []byte) error {
+	k, err := ctx.storageKey(key)
+	if err != nil {
+		return err
+	}
+	ctx.StateMachine.CloneCache.Add(store.ST_Storage, k, &states.StorageItem{Value: value})
+	return nil
+}
+
+// Delete removes the value stored under key by the contract.
+func (ctx *Context) Delete(key []byte) error {
+	k, err := ctx.storageKey(key)
+	if err != nil {
+		return err
+	}
+	ctx.StateMachine.CloneCache.Delete(store.ST_Storage, k)
+	return nil
+}
+
+func (ctx *Context) storageKey(key []byte) ([]byte, error) {
+	bf := new(bytes.Buffer)
+	storageKey := &states.StorageKey{CodeHash: ctx.CodeHash, Key: key}
+	if _, err := storageKey.Serialize(bf); err != nil {
+		return nil, errors.NewErr("[Context] StorageKey serialize error!")
+	}
+	return bf.Bytes(), nil
+}
+
+// CheckWitness reports whether the container is signed by the program hash.
+func (ctx *Context) CheckWitness(programHash common.Uint160) (bool, error) {
+	if ctx.Container == nil {
+		return false, nil
+	}
+	hashes, err := ctx.Container.GetProgramHashes()
+	if err != nil {
+		return false, err
+	}
+	for _, h := range hashes {
+		if h == programHash {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Method implements one operation of a native contract. A nil result pushes
+// an empty byte array.
+type Method func(ctx *Context, args []vmtypes.StackItemInterface) (vmtypes.StackItemInterface, error)
+
+type Contract struct {
+	Name     string
+	CodeHash common.Uint160
+	Methods  map[string]Method
+	// ABI is optional, it lets clients encode calls and decode events
+	ABI *code.ABI
+}
+
+var (
+	lock      sync.RWMutex
+	contracts = make(map[common.Uint160]*Contract)
+)
+
+// CodeHash returns the reserved code hash of the native contract with the
+// given id: nineteen zero bytes followed by the id. Id 0 is the zero hash
+// APPCALL uses for dynamic invoke and is not available.
+func CodeHash(id byte) common.Uint160 {
+	var hash common.Uint160
+	hash[len(hash)-1] = id
+	return hash
+}
+
+// IsReserved reports whether hash lies in the native code hash range.
+func IsReserved(hash common.Uint160) bool {
+	for _, b := range hash[:len(hash)-1] {
+		if b != 0 {
+			return false
+		}
+	}
+	return hash[len(hash)-1] != 0
+}
+
+// Register makes a native contract callable. It is meant to be called at
+// startup, before blocks are persisted.
+func Register(c *Contract) error {
+	if !IsReserved(c.CodeHash) {
+		return errors.NewErr(fmt.Sprintf("[Register] Native contract %s code hash not reserved", c.Name))
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if _, ok := contracts[c.CodeHash]; ok {
+		return errors.NewErr(fmt.Sprintf("[Register] Native contract %s code hash already registered", c.Name))
+	}
+	contracts[c.CodeHash] = c
+	return nil
+}
+
+// GetContract returns the native contract at hash, nil if there is none.
+func GetContract(hash common.Uint160) *Contract {
+	lock.RLock()
+	defer lock.RUnlock()
+	return contracts[hash]
+}
+
+// NewContractState describes a registered native contract the way deployed
+// contracts are stored, so that it goes through the same invoke path. The
+// code of a native contract is its code hash.
+func NewContractState(c *Contract) *states.ContractState {
+	return &states.ContractState{
+		Code:        &code.FunctionCode{Code: c.CodeHash.ToArray(), ReturnType: contract.InteropInterface},
+		VmType:      types.Native,
+		NeedStorage: true,
+		Name:        c.Name,
+		ABI:         c.ABI,
+	}
+}
+
+// Invoke runs the named method.
+func (c *Contract) Invoke(ctx *Context, method string, args []vmtypes.StackItemInterface) (vmtypes.StackItemInterface, error) {
+	m, ok := c.Methods[method]
+	if !ok {
+		return nil, errors.NewErr(fmt.Sprintf("[Invoke] Native contract %s has no method %s", c.Name, method))
+	}
+	ret, err := m(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if ret == nil {
+		ret = vmtypes.NewByteArray([]byte{})
+	}
+	return ret, nil
+}
+
+// EncodeInput builds the InvokeCode input of a native call: the serialized
+// array of the method name and the argument array.
+func EncodeInput(method string, args ...vmtypes.StackItemInterface) ([]byte, error) {
+	if args == nil {
+		args = []vmtypes.StackItemInterface{}
+	}
+	input := vmtypes.NewArray([]vmtypes.StackItemInterface{
+		vmtypes.NewByteArray([]byte(method)),
+		vmtypes.NewArray(args),
+	})
+	return vmtypes.SerializeStackItem(input, int(neovm.MaxItemSize))
+}
+
+// DecodeInput parses an input built by EncodeInput.
+func DecodeInput(input []byte) (string, []vmtypes.StackItemInterface, error) {
+	item, err := vmtypes.DeserializeStackItem(input, int(neovm.MaxArraySize), int(neovm.MaxItemSize))
+	if err != nil {
+		return "", nil, err
+	}
+	arr, ok := item.(*vmtypes.Array)
+	if !ok || len(arr.GetArray()) != 2 {
+		return "", nil, errors.NewErr("[DecodeInput] Input must be an array of method and arguments")
+	}
+	method, ok := arr.GetArray()[0].(*vmtypes.ByteArray)
+	if !ok {
+		return "", nil, errors.NewErr("[DecodeInput] Method must be a byte array")
+	}
+	args, ok := arr.GetArray()[1].(*vmtypes.Array)
+	if !ok {
+		return "", nil, errors.NewErr("[DecodeInput] Arguments must be an array")
+	}
+	return string(method.GetByteArray()), args.GetArray(), nil
+}
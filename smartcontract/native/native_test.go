@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/log"
+	"github.com/Ontology/core/transaction"
+	"github.com/Ontology/core/transaction/payload"
+	"github.com/Ontology/smartcontract"
+	"github.com/Ontology/smartcontract/native"
+	"github.com/Ontology/smartcontract/service"
+	"github.com/Ontology/smartcontract/storage"
+	"github.com/Ontology/smartcontract/types"
+	"github.com/Ontology/vm/neovm"
+	vmtypes "github.com/Ontology/vm/neovm/types"
+)
+
+func init() {
+	log.Init()
+}
+
+// codeTable holds no NeoVM contracts.
+type codeTable struct{}
+
+func (codeTable) GetCode(codeHash []byte) ([]byte, error) {
+	return nil, errors.New("contract not found")
+}
+
+// invokeTransaction is an InvokeCode transaction of the registry signed by
+// the given program hashes.
+func invokeTransaction(signers ...common.Uint160) *transaction.Transaction {
+	tx := &transaction.Transaction{
+		TxType:  transaction.Invoke,
+		Payload: &payload.InvokeCode{CodeHash: native.RegistryCodeHash},
+	}
+	for _, s := range signers {
+		attr := transaction.NewTxAttribute(transaction.Script, s.ToArray())
+		tx.Attributes = append(tx.Attributes, &attr)
+	}
+	return tx
+}
+
+// appCall runs a NeoVM script calling method of the native contract at
+// codeHash with args.
+func appCall(sm *service.StateMachine, tx *transaction.Transaction, op neovm.OpCode, codeHash common.Uint160, method string, args ...[]byte) (*neovm.ExecutionEngine, []byte, error) {
+	builder := neovm.NewParamsBuilder(new(bytes.Buffer))
+	for i := len(args) - 1; i >= 0; i-- {
+		builder.EmitPushByteArray(args[i])
+	}
+	builder.EmitPushInteger(big.NewInt(int64(len(args))))
+	builder.Emit(neovm.PACK)
+	builder.EmitPushByteArray([]byte(method))
+	builder.Emit(op)
+	script := append(builder.ToArray(), codeHash.ToArray()...)
+
+	engine := neovm.NewExecutionEngine(tx, new(neovm.ECDsaCrypto), codeTable{}, sm)
+	engine.SetNativeInvoker(native.NewInvoker(sm, tx))
+	engine.LoadCode(script, false)
+	return engine, script, engine.Execute()
+}
+
+func TestRegistryAppCall(t *testing.T) {
+	owner := common.Uint160{1}
+	name := []byte("alice")
+	sm := service.NewStateMachine(storage.MemoryStore{}, types.Application, nil)
+
+	engine, _, err := appCall(sm, invokeTransaction(owner), neovm.APPCALL, native.RegistryCodeHash, "register", name, owner.ToArray())
+	if err != nil || engine.GetState() == neovm.FAULT {
+		t.Fatalf("register: got %v", err)
+	}
+	if !neovm.PopBoolean(engine) {
+		t.Errorf("register: got false")
+	}
+	if _, _, err := appCall(sm, invokeTransaction(owner), neovm.APPCALL, native.RegistryCodeHash, "register", name, owner.ToArray()); err == nil {
+		t.Errorf("register twice: expected error")
+	}
+	if _, _, err := appCall(sm, invokeTransaction(), neovm.APPCALL, native.RegistryCodeHash, "register", []byte("bob"), owner.ToArray()); err == nil {
+		t.Errorf("register unsigned: expected error")
+	}
+
+	engine, _, err = appCall(sm, invokeTransaction(), neovm.APPCALL, native.RegistryCodeHash, "resolve", name)
+	if err != nil {
+		t.Fatalf("resolve: got %v", err)
+	}
+	if got := neovm.PopByteArray(engine); !bytes.Equal(got, owner.ToArray()) {
+		t.Errorf("resolve: got %x", got)
+	}
+}
+
+func TestRegistryInvokeTransaction(t *testing.T) {
+	owner, next := common.Uint160{1}, common.Uint160{2}
+	name := []byte("alice")
+	db := storage.MemoryStore{}
+
+	invoke := func(tx *transaction.Transaction, method string, args ...[]byte) (interface{}, error) {
+		items := make([]vmtypes.StackItemInterface, 0, len(args))
+		for _, a := range args {
+			items = append(items, vmtypes.NewByteArray(a))
+		}
+		input, err := native.EncodeInput(method, items...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sm := service.NewStateMachine(db, types.Application, nil)
+		smc, err := smartcontract.NewSmartContract(&smartcontract.Context{
+			VmType:       types.Native,
+			StateMachine: sm,
+			SignableData: tx,
+			Code:         native.RegistryCodeHash.ToArray(),
+			Input:        input,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret, err := smc.InvokeContract()
+		if err == nil {
+			sm.CloneCache.Commit()
+		}
+		return ret, err
+	}
+
+	if ret, err := invoke(invokeTransaction(owner), "register", name, owner.ToArray()); err != nil || ret != true {
+		t.Fatalf("register: got %v, %v", ret, err)
+	}
+	if _, err := invoke(invokeTransaction(next), "transfer", name, next.ToArray()); err == nil {
+		t.Errorf("transfer by stranger: expected error")
+	}
+	if _, err := invoke(invokeTransaction(owner), "transfer", name, next.ToArray()); err != nil {
+		t.Errorf("transfer: got %v", err)
+	}
+	if ret, err := invoke(invokeTransaction(), "resolve", name); err != nil || ret != common.ToHexString(next.ToArray()) {
+		t.Errorf("resolve: got %v, %v", ret, err)
+	}
+	if _, err := invoke(invokeTransaction(), "unknown"); err == nil {
+		t.Errorf("unknown method: expected error")
+	}
+}
+
+func TestTailCallCaller(t *testing.T) {
+	codeHash := native.CodeHash(0xfe)
+	err := native.Register(&native.Contract{
+		Name:     "Caller",
+		CodeHash: codeHash,
+		Methods: map[string]native.Method{
+			"caller": func(ctx *native.Context, args []vmtypes.StackItemInterface) (vmtypes.StackItemInterface, error) {
+				return vmtypes.NewByteArray(ctx.Caller.ToArray()), nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm := service.NewStateMachine(storage.MemoryStore{}, types.Application, nil)
+	for _, op := range []neovm.OpCode{neovm.APPCALL, neovm.TAILCALL} {
+		engine, script, err := appCall(sm, invokeTransaction(), op, codeHash, "caller")
+		if err != nil {
+			t.Fatalf("%x: got %v", op, err)
+		}
+		caller, err := common.ToCodeHash(script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := neovm.PopByteArray(engine); !bytes.Equal(got, caller.ToArray()) {
+			t.Errorf("%x: caller %x, expected %x", op, got, caller.ToArray())
+		}
+	}
+}
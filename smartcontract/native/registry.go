@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package native
+
+import (
+	"github.com/Ontology/common"
+	"github.com/Ontology/errors"
+	vmtypes "github.com/Ontology/vm/neovm/types"
+)
+
+// RegistryCodeHash is the code hash of the name registry.
+var RegistryCodeHash = CodeHash(1)
+
+// The name registry maps names to the program hash owning them. Only the
+// owner can transfer a name, the owner of a new name signs its registration.
+func init() {
+	if err := Register(&Contract{
+		Name:     "NameRegistry",
+		CodeHash: RegistryCodeHash,
+		Methods: map[string]Method{
+			"register": registryRegister,
+			"resolve":  registryResolve,
+			"transfer": registryTransfer,
+		},
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// registryRegister takes the name and the owner.
+func registryRegister(ctx *Context, args []vmtypes.StackItemInterface) (vmtypes.StackItemInterface, error) {
+	if len(args) != 2 {
+		return nil, errors.NewErr("[NameRegistry] register takes a name and an owner")
+	}
+	name := args[0].GetByteArray()
+	owner, err := registryOwner(ctx, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if current, err := ctx.Get(name); err != nil {
+		return nil, err
+	} else if current != nil {
+		return nil, errors.NewErr("[NameRegistry] Name already registered")
+	}
+	if err := ctx.Put(name, owner.ToArray()); err != nil {
+		return nil, err
+	}
+	return vmtypes.NewBoolean(true), nil
+}
+
+// registryResolve takes the name and returns its owner, empty if the name is
+// not registered.
+func registryResolve(ctx *Context, args []vmtypes.StackItemInterface) (vmtypes.StackItemInterface, error) {
+	if len(args) != 1 {
+		return nil, errors.NewErr("[NameRegistry] resolve takes a name")
+	}
+	owner, err := ctx.Get(args[0].GetByteArray())
+	if err != nil {
+		return nil, err
+	}
+	return vmtypes.NewByteArray(owner), nil
+}
+
+// registryTransfer takes the name and the new owner, the current owner signs.
+func registryTransfer(ctx *Context, args []vmtypes.StackItemInterface) (vmtypes.StackItemInterface, error) {
+	if len(args) != 2 {
+		return nil, errors.NewErr("[NameRegistry] transfer takes a name and an owner")
+	}
+	name := args[0].GetByteArray()
+	current, err := ctx.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, errors.NewErr("[NameRegistry] Name not registered")
+	}
+	currentOwner, err := common.Uint160ParseFromBytes(current)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := ctx.CheckWitness(currentOwner); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.NewErr("[NameRegistry] Transfer not signed by the owner")
+	}
+	to, err := common.Uint160ParseFromBytes(args[1].GetByteArray())
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Put(name, to.ToArray()); err != nil {
+		return nil, err
+	}
+	return vmtypes.NewBoolean(true), nil
+}
+
+// registryOwner parses an owner argument and checks it signed the container.
+func registryOwner(ctx *Context, arg vmtypes.StackItemInterface) (common.Uint160, error) {
+	owner, err := common.Uint160ParseFromBytes(arg.GetByteArray())
+	if err != nil {
+		return owner, err
+	}
+	if ok, err := ctx.CheckWitness(owner); err != nil {
+		return owner, err
+	} else if !ok {
+		return owner, errors.NewErr("[NameRegistry] Registration not signed by the owner")
+	}
+	return owner, nil
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"math/big"
+
+	"github.com/Ontology/vm/neovm/interfaces"
+	"github.com/Ontology/vm/neovm/types"
+)
+
+// Enumerator walks a sequence of stack items. Next must be called before the
+// first Value.
+type Enumerator interface {
+	interfaces.IInteropInterface
+	Next() bool
+	Value() types.StackItemInterface
+}
+
+// Iterator is an Enumerator whose items also have a key.
+type Iterator interface {
+	Enumerator
+	Key() types.StackItemInterface
+}
+
+type storageEntry struct {
+	key   []byte
+	value []byte
+}
+
+// StorageIterator iterates the storage items of a contract in key order.
+type StorageIterator struct {
+	entries []*storageEntry
+	index   int
+}
+
+func NewStorageIterator(entries []*storageEntry) *StorageIterator {
+	return &StorageIterator{entries: entries, index: -1}
+}
+
+func (si *StorageIterator) Next() bool {
+	if si.index < len(si.entries) {
+		si.index++
+	}
+	return si.index < len(si.entries)
+}
+
+func (si *StorageIterator) Key() types.StackItemInterface {
+	if si.index < 0 || si.index >= len(si.entries) {
+		return nil
+	}
+	return types.NewByteArray(si.entries[si.index].key)
+}
+
+func (si *StorageIterator) Value() types.StackItemInterface {
+	if si.index < 0 || si.index >= len(si.entries) {
+		return nil
+	}
+	return types.NewByteArray(si.entries[si.index].value)
+}
+
+func (si *StorageIterator) ToArray() []byte {
+	return []byte{}
+}
+
+// ArrayIterator iterates the items of an array, the keys being their indexes.
+type ArrayIterator struct {
+	items []types.StackItemInterface
+	index int
+}
+
+func NewArrayIterator(items []types.StackItemInterface) *ArrayIterator {
+	return &ArrayIterator{items: items, index: -1}
+}
+
+func (ai *ArrayIterator) Next() bool {
+	if ai.index < len(ai.items) {
+		ai.index++
+	}
+	return ai.index < len(ai.items)
+}
+
+func (ai *ArrayIterator) Key() types.StackItemInterface {
+	if ai.index < 0 || ai.index >= len(ai.items) {
+		return nil
+	}
+	return types.NewInteger(big.NewInt(int64(ai.index)))
+}
+
+func (ai *ArrayIterator) Value() types.StackItemInterface {
+	if ai.index < 0 || ai.index >= len(ai.items) {
+		return nil
+	}
+	return ai.items[ai.index]
+}
+
+func (ai *ArrayIterator) ToArray() []byte {
+	return []byte{}
+}
+
+// IteratorKeysWrapper enumerates the keys of an iterator.
+type IteratorKeysWrapper struct {
+	iterator Iterator
+}
+
+func (kw *IteratorKeysWrapper) Next() bool {
+	return kw.iterator.Next()
+}
+
+func (kw *IteratorKeysWrapper) Value() types.StackItemInterface {
+	return kw.iterator.Key()
+}
+
+func (kw *IteratorKeysWrapper) ToArray() []byte {
+	return []byte{}
+}
+
+// IteratorValuesWrapper enumerates the values of an iterator.
+type IteratorValuesWrapper struct {
+	iterator Iterator
+}
+
+func (vw *IteratorValuesWrapper) Next() bool {
+	return vw.iterator.Next()
+}
+
+func (vw *IteratorValuesWrapper) Value() types.StackItemInterface {
+	return vw.iterator.Value()
+}
+
+func (vw *IteratorValuesWrapper) ToArray() []byte {
+	return []byte{}
+}
+
+// ConcatenatedEnumerator enumerates the items of first, then the ones of second.
+type ConcatenatedEnumerator struct {
+	first   Enumerator
+	second  Enumerator
+	current Enumerator
+}
+
+func NewConcatenatedEnumerator(first, second Enumerator) *ConcatenatedEnumerator {
+	return &ConcatenatedEnumerator{first: first, second: second, current: first}
+}
+
+func (ce *ConcatenatedEnumerator) Next() bool {
+	if ce.current.Next() {
+		return true
+	}
+	if ce.current == ce.first {
+		ce.current = ce.second
+		return ce.current.Next()
+	}
+	return false
+}
+
+func (ce *ConcatenatedEnumerator) Value() types.StackItemInterface {
+	return ce.current.Value()
+}
+
+func (ce *ConcatenatedEnumerator) ToArray() []byte {
+	return []byte{}
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/Ontology/smartcontract/types"
 	vm "github.com/Ontology/vm/neovm"
 	"math"
+	"sort"
 )
 
 type StateMachine struct {
@@ -69,6 +70,8 @@ func NewStateMachine(dbCache store.IStateStore, trigger types.TriggerType, block
 	stateMachine.StateReader.Register("Neo.Storage.Get", stateMachine.StorageGet)
 	stateMachine.StateReader.Register("Neo.Storage.Put", stateMachine.StoragePut)
 	stateMachine.StateReader.Register("Neo.Storage.Delete", stateMachine.StorageDelete)
+	stateMachine.StateReader.Register("Neo.Storage.Find", stateMachine.StorageFind)
+	stateMachine.StateReader.Register("Neo.StorageContext.Delegate", stateMachine.StorageContextDelegate)
 	return &stateMachine
 }
 
@@ -143,7 +146,7 @@ func (s *StateMachine) CreateAsset(engine *vm.ExecutionEngine) (bool, error) {
 		Owner:      owner,
 		Admin:      admin,
 		Issuer:     admin,
-		Expiration: ledger.DefaultLedger.Store.GetHeight() + 1 + 2000000,
+		Expiration: ledger.DefaultLedger.Store.GetHeight() + 1 + states.BlocksPerYear,
 		IsFrozen:   false,
 	}
 	state, err := s.CloneCache.GetOrAdd(store.ST_Asset, assetId.ToArray(), assetState)
@@ -308,10 +311,7 @@ func (s *StateMachine) AssetRenew(engine *vm.ExecutionEngine) (bool, error) {
 	}
 	years := vm.PopInt(engine)
 	assetState := data.(*states.AssetState)
-	if assetState.Expiration < ledger.DefaultLedger.Store.GetHeight()+1 {
-		assetState.Expiration = ledger.DefaultLedger.Store.GetHeight() + 1
-	}
-	assetState.Expiration += uint32(years) * 2000000
+	assetState.Renew(ledger.DefaultLedger.Store.GetHeight()+1, uint32(years))
 	vm.PushData(engine, assetState.Expiration)
 	return true, nil
 }
@@ -343,7 +343,10 @@ func (s *StateMachine) ContractDestory(engine *vm.ExecutionEngine) (bool, error)
 	return true, nil
 }
 
-func (s *StateMachine) CheckStorageContext(context *StorageContext) (bool, error) {
+// CheckStorageContext checks the contract owning the storage exists and the
+// running contract may use the context: it holds it, or the context is
+// read-only.
+func (s *StateMachine) CheckStorageContext(engine *vm.ExecutionEngine, context *StorageContext) (bool, error) {
 	item, err := s.CloneCache.Get(store.ST_Contract, context.codeHash.ToArray())
 	if err != nil {
 		return false, err
@@ -351,9 +354,23 @@ func (s *StateMachine) CheckStorageContext(context *StorageContext) (bool, error
 	if item == nil {
 		return false, errors.NewErr(fmt.Sprintf("get contract by codehash=%v nil", context.codeHash))
 	}
+	if err := checkStorageHolder(engine, context); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
+// checkStorageWrite checks the context may be used to put or delete.
+func (s *StateMachine) checkStorageWrite(engine *vm.ExecutionEngine, context *StorageContext) error {
+	if context.IsReadOnly() {
+		return errors.NewErr("[CheckStorageContext] StorageContext is read only!")
+	}
+	if _, err := s.CheckStorageContext(engine, context); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (s *StateMachine) StoragePut(engine *vm.ExecutionEngine) (bool, error) {
 	if vm.EvaluationStackCount(engine) < 3 {
 		return false, errors.NewErr("[StoragePut] Too few input parameters ")
@@ -362,8 +379,14 @@ func (s *StateMachine) StoragePut(engine *vm.ExecutionEngine) (bool, error) {
 	if opInterface == nil {
 		return false, errors.NewErr("[StoragePut] Get StorageContext nil")
 	}
-	context := opInterface.(*StorageContext)
-	key := vm.PopByteArray(engine)
+	context, ok := opInterface.(*StorageContext)
+	if ok == false {
+		return false, errors.NewErr("[StoragePut] Wrong type!")
+	}
+	if err := s.checkStorageWrite(engine, context); err != nil {
+		return false, err
+	}
+	key := context.Key(vm.PopByteArray(engine))
 	if len(key) > 1024 {
 		return false, errors.NewErr("[StoragePut] Get Storage key to long")
 	}
@@ -384,8 +407,14 @@ func (s *StateMachine) StorageDelete(engine *vm.ExecutionEngine) (bool, error) {
 	if opInterface == nil {
 		return false, errors.NewErr("[StorageDelete] Get StorageContext nil")
 	}
-	context := opInterface.(*StorageContext)
-	key := vm.PopByteArray(engine)
+	context, ok := opInterface.(*StorageContext)
+	if ok == false {
+		return false, errors.NewErr("[StorageDelete] Wrong type!")
+	}
+	if err := s.checkStorageWrite(engine, context); err != nil {
+		return false, err
+	}
+	key := context.Key(vm.PopByteArray(engine))
 	k, err := serializeStorageKey(context.codeHash, key)
 	if err != nil {
 		return false, err
@@ -402,11 +431,14 @@ func (s *StateMachine) StorageGet(engine *vm.ExecutionEngine) (bool, error) {
 	if opInterface == nil {
 		return false, errors.NewErr("[StorageGet] Get StorageContext error!")
 	}
-	context := opInterface.(*StorageContext)
-	if exist, err := s.CheckStorageContext(context); !exist {
+	context, ok := opInterface.(*StorageContext)
+	if ok == false {
+		return false, errors.NewErr("[StorageGet] Wrong type!")
+	}
+	if exist, err := s.CheckStorageContext(engine, context); !exist {
 		return false, err
 	}
-	key := vm.PopByteArray(engine)
+	key := context.Key(vm.PopByteArray(engine))
 	k, err := serializeStorageKey(context.codeHash, key)
 	if err != nil {
 		return false, err
@@ -423,6 +455,44 @@ func (s *StateMachine) StorageGet(engine *vm.ExecutionEngine) (bool, error) {
 	return true, nil
 }
 
+func (s *StateMachine) StorageFind(engine *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(engine) < 2 {
+		return false, errors.NewErr("[StorageFind] Too few input parameters ")
+	}
+	opInterface := vm.PopInteropInterface(engine)
+	if opInterface == nil {
+		return false, errors.NewErr("[StorageFind] Get StorageContext error!")
+	}
+	context, ok := opInterface.(*StorageContext)
+	if ok == false {
+		return false, errors.NewErr("[StorageFind] Wrong type!")
+	}
+	if exist, err := s.CheckStorageContext(engine, context); !exist {
+		return false, err
+	}
+	prefix := context.Key(vm.PopByteArray(engine))
+	stateValues, err := s.CloneCache.Find(store.ST_Storage, context.codeHash.ToArray())
+	if err != nil {
+		return false, errors.NewDetailErr(err, errors.ErrNoCode, "[StorageFind] Find error!")
+	}
+	entries := make([]*storageEntry, 0, len(stateValues))
+	for _, v := range stateValues {
+		key := new(states.StorageKey)
+		if err := key.Deserialize(bytes.NewBuffer([]byte(v.Key))); err != nil {
+			return false, errors.NewErr("[StorageFind] Key deserialize error!")
+		}
+		if key.CodeHash.CompareTo(context.codeHash) != 0 || !bytes.HasPrefix(key.Key, prefix) {
+			continue
+		}
+		// keys of a delegated context are relative to its prefix
+		entries = append(entries, &storageEntry{key: key.Key[len(context.prefix):], value: v.Value.(*states.StorageItem).Value})
+	}
+	// storage keys are length prefixed, so order them by the contract key itself
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+	vm.PushData(engine, NewStorageIterator(entries))
+	return true, nil
+}
+
 func (s *StateMachine) GetStorageContext(engine *vm.ExecutionEngine) (bool, error) {
 	if vm.EvaluationStackCount(engine) < 1 {
 		return false, errors.NewErr("[GetStorageContext] Too few input parameters ")
@@ -455,6 +525,36 @@ func (s *StateMachine) GetStorageContext(engine *vm.ExecutionEngine) (bool, erro
 	return true, nil
 }
 
+// StorageContextDelegate hands the child contract a context for the keys of
+// the given context starting with prefix. Only the holder of the context may
+// delegate it, a read-only context stays read-only.
+func (s *StateMachine) StorageContextDelegate(engine *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(engine) < 3 {
+		return false, errors.NewErr("[StorageContextDelegate] Too few input parameters ")
+	}
+	context, ok := vm.PopInteropInterface(engine).(*StorageContext)
+	if !ok {
+		return false, errors.NewErr("[StorageContextDelegate] Wrong type!")
+	}
+	child, err := common.Uint160ParseFromBytes(vm.PopByteArray(engine))
+	if err != nil {
+		return false, errors.NewDetailErr(err, errors.ErrNoCode, "[StorageContextDelegate] Invalid child code hash!")
+	}
+	prefix := vm.PopByteArray(engine)
+	if len(context.prefix)+len(prefix) > 1024 {
+		return false, errors.NewErr("[StorageContextDelegate] Prefix too long!")
+	}
+	current, err := currentCodeHash(engine)
+	if err != nil {
+		return false, err
+	}
+	if holder := context.Holder(); holder.CompareTo(current) != 0 {
+		return false, errors.NewErr("[StorageContextDelegate] StorageContext not held by the calling contract!")
+	}
+	vm.PushData(engine, context.Delegate(child, prefix))
+	return true, nil
+}
+
 func contains(programHashes []common.Uint160, programHash common.Uint160) bool {
 	for _, v := range programHashes {
 		if v.CompareTo(programHash) == 0 {
@@ -0,0 +1,184 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Ontology/common"
+	"github.com/Ontology/common/log"
+	"github.com/Ontology/core/code"
+	"github.com/Ontology/core/states"
+	"github.com/Ontology/core/store"
+	"github.com/Ontology/smartcontract/service"
+	"github.com/Ontology/smartcontract/storage"
+	"github.com/Ontology/smartcontract/types"
+	"github.com/Ontology/vm/neovm"
+)
+
+func init() {
+	log.Init()
+}
+
+// memoryStore is the state store of a chain with no blocks and the code
+// table of its contracts.
+type memoryStore struct {
+	storage.MemoryStore
+}
+
+func newMemoryStore() memoryStore {
+	return memoryStore{storage.MemoryStore{}}
+}
+
+// deploy adds the contract of script to the store and the code table.
+func (m memoryStore) deploy(script []byte) common.Uint160 {
+	fc := &code.FunctionCode{Code: script}
+	codeHash := fc.CodeHash()
+	m.TryAdd(store.ST_Contract, codeHash.ToArray(), &states.ContractState{Code: fc, NeedStorage: true}, false)
+	return codeHash
+}
+
+func (m memoryStore) GetCode(codeHash []byte) ([]byte, error) {
+	item, _ := m.TryGet(store.ST_Contract, codeHash)
+	if item == nil {
+		return nil, errors.New("contract not found")
+	}
+	return item.Value.(*states.ContractState).Code.Code, nil
+}
+
+type scriptBuilder struct {
+	buf *bytes.Buffer
+	*neovm.ParamsBuilder
+}
+
+func newScriptBuilder() *scriptBuilder {
+	buf := new(bytes.Buffer)
+	return &scriptBuilder{buf, neovm.NewParamsBuilder(buf)}
+}
+
+func (b *scriptBuilder) syscall(name string) *scriptBuilder {
+	b.Emit(neovm.SYSCALL)
+	b.buf.WriteByte(byte(len(name)))
+	b.buf.WriteString(name)
+	return b
+}
+
+func (b *scriptBuilder) push(data []byte) *scriptBuilder {
+	b.EmitPushByteArray(data)
+	return b
+}
+
+func (b *scriptBuilder) appCall(codeHash common.Uint160) *scriptBuilder {
+	b.EmitPushCall(codeHash.ToArray())
+	return b
+}
+
+// run executes the contract at codeHash as the entry script.
+func run(db memoryStore, sm *service.StateMachine, codeHash common.Uint160) (*neovm.ExecutionEngine, error) {
+	script, err := db.GetCode(codeHash.ToArray())
+	if err != nil {
+		return nil, err
+	}
+	engine := neovm.NewExecutionEngine(nil, new(neovm.ECDsaCrypto), db, sm)
+	engine.LoadCode(script, false)
+	err = engine.Execute()
+	return engine, err
+}
+
+func storageValue(sm *service.StateMachine, codeHash common.Uint160, key []byte) []byte {
+	k := &states.StorageKey{CodeHash: codeHash, Key: key}
+	item, err := sm.CloneCache.Get(store.ST_Storage, k.ToArray())
+	if err != nil || item == nil {
+		return nil
+	}
+	return item.(*states.StorageItem).Value
+}
+
+func TestStorageContextPermissions(t *testing.T) {
+	db := newMemoryStore()
+	key, value, prefix := []byte("key"), []byte("value"), []byte("p/")
+
+	// put is the callee writing with the context it is handed
+	put := db.deploy(newScriptBuilder().syscall("Neo.Storage.Put").ToArray())
+	// get reads with the context it is handed
+	get := db.deploy(newScriptBuilder().syscall("Neo.Storage.Get").ToArray())
+
+	cases := []struct {
+		name   string
+		script *scriptBuilder
+		ok     bool
+	}{
+		{"own context", newScriptBuilder().push(value).push(key).
+			syscall("Neo.Storage.GetContext").syscall("Neo.Storage.Put"), true},
+		{"read-only context", newScriptBuilder().push(value).push(key).
+			syscall("Neo.Storage.GetContext").syscall("Neo.StorageContext.AsReadOnly").syscall("Neo.Storage.Put"), false},
+		{"read-only context of the storage", newScriptBuilder().push(value).push(key).
+			syscall("Neo.Storage.GetReadOnlyContext").syscall("Neo.Storage.Delete"), false},
+		{"context handed to a callee", newScriptBuilder().push(value).push(key).
+			syscall("Neo.Storage.GetContext").appCall(put), false},
+		{"read-only context read by a callee", newScriptBuilder().push(key).
+			syscall("Neo.Storage.GetContext").syscall("Neo.StorageContext.AsReadOnly").appCall(get), true},
+		{"context delegated to the callee", newScriptBuilder().push(value).push(key).push(prefix).push(put.ToArray()).
+			syscall("Neo.Storage.GetContext").syscall("Neo.StorageContext.Delegate").appCall(put), true},
+		{"delegated context used by the delegator", newScriptBuilder().push(value).push(key).push(prefix).push(put.ToArray()).
+			syscall("Neo.Storage.GetContext").syscall("Neo.StorageContext.Delegate").syscall("Neo.Storage.Put"), false},
+		{"read-only delegated context written by the callee", newScriptBuilder().push(value).push(key).push(prefix).push(put.ToArray()).
+			syscall("Neo.Storage.GetContext").syscall("Neo.StorageContext.AsReadOnly").
+			syscall("Neo.StorageContext.Delegate").appCall(put), false},
+	}
+	for _, c := range cases {
+		codeHash := db.deploy(c.script.ToArray())
+		sm := service.NewStateMachine(db, types.Application, nil)
+		engine, err := run(db, sm, codeHash)
+		if ok := err == nil && engine.GetState() != neovm.FAULT; ok != c.ok {
+			t.Errorf("%s: got %v", c.name, err)
+		}
+		if !c.ok {
+			// nothing is written by a faulted script
+			for _, k := range [][]byte{key, append(prefix, key...)} {
+				if v := storageValue(sm, codeHash, k); v != nil {
+					t.Errorf("%s: %s was written", c.name, k)
+				}
+			}
+		}
+	}
+}
+
+func TestStorageContextDelegateKeys(t *testing.T) {
+	db := newMemoryStore()
+	key, value, prefix := []byte("key"), []byte("value"), []byte("p/")
+	put := db.deploy(newScriptBuilder().syscall("Neo.Storage.Put").ToArray())
+	caller := db.deploy(newScriptBuilder().push(value).push(key).push(prefix).push(put.ToArray()).
+		syscall("Neo.Storage.GetContext").syscall("Neo.StorageContext.Delegate").appCall(put).ToArray())
+
+	sm := service.NewStateMachine(db, types.Application, nil)
+	if engine, err := run(db, sm, caller); err != nil || engine.GetState() == neovm.FAULT {
+		t.Fatalf("delegated put: got %v", err)
+	}
+	// the callee writes to the storage of the delegator, under the prefix
+	if v := storageValue(sm, caller, append(prefix, key...)); !bytes.Equal(v, value) {
+		t.Errorf("delegator storage: got %q", v)
+	}
+	if v := storageValue(sm, put, key); v != nil {
+		t.Errorf("callee storage: got %q", v)
+	}
+}
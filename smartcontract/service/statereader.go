@@ -29,6 +29,7 @@ import (
 	tx "github.com/Ontology/core/transaction"
 	"github.com/Ontology/core/transaction/utxo"
 	"github.com/Ontology/crypto"
+	"github.com/Ontology/crypto/sm3"
 	"github.com/Ontology/errors"
 	"github.com/Ontology/smartcontract/event"
 	trigger "github.com/Ontology/smartcontract/types"
@@ -45,8 +46,9 @@ var (
 )
 
 type StateReader struct {
-	serviceMap map[string]func(*vm.ExecutionEngine) (bool, error)
-	trigger    trigger.TriggerType
+	serviceMap    map[string]func(*vm.ExecutionEngine) (bool, error)
+	trigger       trigger.TriggerType
+	notifications []*event.NotifyEventArgs
 }
 
 func NewStateReader(trigger trigger.TriggerType) *StateReader {
@@ -59,6 +61,8 @@ func NewStateReader(trigger trigger.TriggerType) *StateReader {
 	stateReader.Register("Neo.Runtime.CheckWitness", stateReader.RuntimeCheckWitness)
 	stateReader.Register("Neo.Runtime.Notify", stateReader.RuntimeNotify)
 	stateReader.Register("Neo.Runtime.Log", stateReader.RuntimeLog)
+	stateReader.Register("Neo.Runtime.Serialize", stateReader.RuntimeSerialize)
+	stateReader.Register("Neo.Runtime.Deserialize", stateReader.RuntimeDeserialize)
 
 	stateReader.Register("Neo.Blockchain.GetHeight", stateReader.BlockChainGetHeight)
 	stateReader.Register("Neo.Blockchain.GetHeader", stateReader.BlockChainGetHeader)
@@ -111,8 +115,27 @@ func NewStateReader(trigger trigger.TriggerType) *StateReader {
 
 	stateReader.Register("Neo.Storage.GetScript", stateReader.ContractGetCode)
 	stateReader.Register("Neo.Storage.GetContext", stateReader.StorageGetContext)
+	stateReader.Register("Neo.Storage.GetReadOnlyContext", stateReader.StorageGetReadOnlyContext)
+	stateReader.Register("Neo.StorageContext.AsReadOnly", stateReader.StorageContextAsReadOnly)
 	stateReader.Register("Neo.Storage.Get", stateReader.StorageGet)
 
+	stateReader.Register("Neo.Iterator.Create", stateReader.IteratorCreate)
+	stateReader.Register("Neo.Iterator.Next", stateReader.EnumeratorNext)
+	stateReader.Register("Neo.Iterator.Key", stateReader.IteratorKey)
+	stateReader.Register("Neo.Iterator.Value", stateReader.EnumeratorValue)
+	stateReader.Register("Neo.Iterator.Keys", stateReader.IteratorKeys)
+	stateReader.Register("Neo.Iterator.Values", stateReader.IteratorValues)
+
+	stateReader.Register("Neo.Enumerator.Create", stateReader.EnumeratorCreate)
+	stateReader.Register("Neo.Enumerator.Next", stateReader.EnumeratorNext)
+	stateReader.Register("Neo.Enumerator.Value", stateReader.EnumeratorValue)
+	stateReader.Register("Neo.Enumerator.Concat", stateReader.EnumeratorConcat)
+
+	stateReader.Register("Neo.Crypto.SM3", stateReader.CryptoSM3)
+	stateReader.Register("Neo.Crypto.VerifySM2", stateReader.CryptoVerifySM2)
+	stateReader.Register("Neo.Crypto.VerifyP256", stateReader.CryptoVerifyP256)
+	stateReader.Register("Neo.Crypto.VerifyWithAlgorithm", stateReader.CryptoVerifyWithAlgorithm)
+
 	return &stateReader
 }
 
@@ -163,10 +186,16 @@ func (s *StateReader) RuntimeNotify(e *vm.ExecutionEngine) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	event.PushSmartCodeEvent(tran.Hash(), 0, Notify, event.NotifyEventArgs{tran.Hash(), hash, item})
+	s.notifications = append(s.notifications, &event.NotifyEventArgs{Container: tran.Hash(), CodeHash: hash, State: item})
+	event.PushSmartCodeEvent(tran.Hash(), 0, Notify, event.NotifyEventArgs{Container: tran.Hash(), CodeHash: hash, State: item})
 	return true, nil
 }
 
+// Notifications returns the Runtime.Notify calls made so far, in order.
+func (s *StateReader) Notifications() []*event.NotifyEventArgs {
+	return s.notifications
+}
+
 func (s *StateReader) RuntimeLog(e *vm.ExecutionEngine) (bool, error) {
 	item := vm.PopByteArray(e)
 	container := e.GetCodeContainer()
@@ -187,7 +216,31 @@ func (s *StateReader) RuntimeLog(e *vm.ExecutionEngine) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	event.PushSmartCodeEvent(tran.Hash(), 0, Log, event.LogEventArgs{tran.Hash(), hash, string(item)})
+	event.PushSmartCodeEvent(tran.Hash(), 0, Log, event.LogEventArgs{Container: tran.Hash(), CodeHash: hash, Message: string(item)})
+	return true, nil
+}
+
+func (s *StateReader) RuntimeSerialize(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 1 {
+		return false, errors.NewErr("[RuntimeSerialize] Too few input parameters ")
+	}
+	data, err := types.SerializeStackItem(vm.PopStackItem(e), int(vm.MaxItemSize))
+	if err != nil {
+		return false, err
+	}
+	vm.PushData(e, data)
+	return true, nil
+}
+
+func (s *StateReader) RuntimeDeserialize(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 1 {
+		return false, errors.NewErr("[RuntimeDeserialize] Too few input parameters ")
+	}
+	item, err := types.DeserializeStackItem(vm.PopByteArray(e), int(vm.MaxArraySize), int(vm.MaxItemSize))
+	if err != nil {
+		return false, err
+	}
+	vm.PushData(e, item)
 	return true, nil
 }
 
@@ -770,7 +823,7 @@ func (s *StateReader) AttributeGetData(e *vm.ExecutionEngine) (bool, error) {
 	}
 	attribute, ok := d.(*tx.TxAttribute)
 	if ok == false {
-		return false, errors.NewErr("[AttributeGetUsage] Wrong type!")
+		return false, errors.NewErr("[AttributeGetData] Wrong type!")
 	}
 	vm.PushData(e, attribute.Data)
 	return true, nil
@@ -1042,6 +1095,51 @@ func (s *StateReader) StorageGetContext(e *vm.ExecutionEngine) (bool, error) {
 	return true, nil
 }
 
+func (s *StateReader) StorageGetReadOnlyContext(e *vm.ExecutionEngine) (bool, error) {
+	hash, err := currentCodeHash(e)
+	if err != nil {
+		return false, err
+	}
+	vm.PushData(e, NewStorageContext(hash).AsReadOnly())
+	return true, nil
+}
+
+func (s *StateReader) StorageContextAsReadOnly(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 1 {
+		return false, errors.NewErr("[StorageContextAsReadOnly] Too few input parameters ")
+	}
+	context, ok := vm.PopInteropInterface(e).(*StorageContext)
+	if !ok {
+		return false, errors.NewErr("[StorageContextAsReadOnly] Wrong type!")
+	}
+	vm.PushData(e, context.AsReadOnly())
+	return true, nil
+}
+
+func currentCodeHash(e *vm.ExecutionEngine) (common.Uint160, error) {
+	context, err := e.CurrentContext()
+	if err != nil {
+		return common.Uint160{}, err
+	}
+	return context.GetCodeHash()
+}
+
+// checkStorageHolder checks the running contract holds the context. Contexts
+// made read-only may be handed to and read by any contract.
+func checkStorageHolder(e *vm.ExecutionEngine, context *StorageContext) error {
+	if context.IsReadOnly() {
+		return nil
+	}
+	current, err := currentCodeHash(e)
+	if err != nil {
+		return err
+	}
+	if holder := context.Holder(); holder.CompareTo(current) != 0 {
+		return errors.NewErr("[CheckStorageContext] StorageContext not held by the calling contract!")
+	}
+	return nil
+}
+
 func (s *StateReader) StorageGet(e *vm.ExecutionEngine) (bool, error) {
 	if vm.EvaluationStackCount(e) < 2 {
 		return false, errors.NewErr("[StorageGet] Too few input parameters ")
@@ -1061,7 +1159,10 @@ func (s *StateReader) StorageGet(e *vm.ExecutionEngine) (bool, error) {
 	if c == nil {
 		return false, nil
 	}
-	key := vm.PopByteArray(e)
+	if err := checkStorageHolder(e, context); err != nil {
+		return false, err
+	}
+	key := context.Key(vm.PopByteArray(e))
 	item, err := ledger.DefaultLedger.Store.GetStorageItem(&states.StorageKey{CodeHash: context.codeHash, Key: key})
 	if err != nil && !strings.EqualFold(err.Error(), ErrDBNotFound) {
 		return false, err
@@ -1073,3 +1174,152 @@ func (s *StateReader) StorageGet(e *vm.ExecutionEngine) (bool, error) {
 	}
 	return true, nil
 }
+
+func (s *StateReader) IteratorCreate(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 1 {
+		return false, errors.NewErr("[IteratorCreate] Too few input parameters ")
+	}
+	vm.PushData(e, NewArrayIterator(vm.PopArray(e)))
+	return true, nil
+}
+
+func (s *StateReader) IteratorKey(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 1 {
+		return false, errors.NewErr("[IteratorKey] Too few input parameters ")
+	}
+	iterator, ok := vm.PopInteropInterface(e).(Iterator)
+	if ok == false {
+		return false, errors.NewErr("[IteratorKey] Wrong type!")
+	}
+	key := iterator.Key()
+	if key == nil {
+		return false, errors.NewErr("[IteratorKey] Iterator out of range!")
+	}
+	vm.PushData(e, key)
+	return true, nil
+}
+
+func (s *StateReader) IteratorKeys(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 1 {
+		return false, errors.NewErr("[IteratorKeys] Too few input parameters ")
+	}
+	iterator, ok := vm.PopInteropInterface(e).(Iterator)
+	if ok == false {
+		return false, errors.NewErr("[IteratorKeys] Wrong type!")
+	}
+	vm.PushData(e, &IteratorKeysWrapper{iterator: iterator})
+	return true, nil
+}
+
+func (s *StateReader) IteratorValues(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 1 {
+		return false, errors.NewErr("[IteratorValues] Too few input parameters ")
+	}
+	iterator, ok := vm.PopInteropInterface(e).(Iterator)
+	if ok == false {
+		return false, errors.NewErr("[IteratorValues] Wrong type!")
+	}
+	vm.PushData(e, &IteratorValuesWrapper{iterator: iterator})
+	return true, nil
+}
+
+func (s *StateReader) EnumeratorCreate(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 1 {
+		return false, errors.NewErr("[EnumeratorCreate] Too few input parameters ")
+	}
+	vm.PushData(e, NewArrayIterator(vm.PopArray(e)))
+	return true, nil
+}
+
+func (s *StateReader) EnumeratorNext(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 1 {
+		return false, errors.NewErr("[EnumeratorNext] Too few input parameters ")
+	}
+	enumerator, ok := vm.PopInteropInterface(e).(Enumerator)
+	if ok == false {
+		return false, errors.NewErr("[EnumeratorNext] Wrong type!")
+	}
+	vm.PushData(e, enumerator.Next())
+	return true, nil
+}
+
+func (s *StateReader) EnumeratorValue(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 1 {
+		return false, errors.NewErr("[EnumeratorValue] Too few input parameters ")
+	}
+	enumerator, ok := vm.PopInteropInterface(e).(Enumerator)
+	if ok == false {
+		return false, errors.NewErr("[EnumeratorValue] Wrong type!")
+	}
+	value := enumerator.Value()
+	if value == nil {
+		return false, errors.NewErr("[EnumeratorValue] Enumerator out of range!")
+	}
+	vm.PushData(e, value)
+	return true, nil
+}
+
+func (s *StateReader) EnumeratorConcat(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 2 {
+		return false, errors.NewErr("[EnumeratorConcat] Too few input parameters ")
+	}
+	first, ok := vm.PopInteropInterface(e).(Enumerator)
+	if ok == false {
+		return false, errors.NewErr("[EnumeratorConcat] Wrong type!")
+	}
+	second, ok := vm.PopInteropInterface(e).(Enumerator)
+	if ok == false {
+		return false, errors.NewErr("[EnumeratorConcat] Wrong type!")
+	}
+	vm.PushData(e, NewConcatenatedEnumerator(first, second))
+	return true, nil
+}
+
+func (s *StateReader) CryptoSM3(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 1 {
+		return false, errors.NewErr("[CryptoSM3] Too few input parameters ")
+	}
+	hash := sm3.Sum(vm.PopByteArray(e))
+	vm.PushData(e, hash[:])
+	return true, nil
+}
+
+func (s *StateReader) CryptoVerifySM2(e *vm.ExecutionEngine) (bool, error) {
+	return s.verifyWithAlgorithm(e, crypto.SM2, "CryptoVerifySM2")
+}
+
+func (s *StateReader) CryptoVerifyP256(e *vm.ExecutionEngine) (bool, error) {
+	return s.verifyWithAlgorithm(e, crypto.P256R1, "CryptoVerifyP256")
+}
+
+// CryptoVerifyWithAlgorithm pops the algorithm, crypto.P256R1 or crypto.SM2,
+// followed by the same parameters as CryptoVerifyP256.
+func (s *StateReader) CryptoVerifyWithAlgorithm(e *vm.ExecutionEngine) (bool, error) {
+	if vm.EvaluationStackCount(e) < 4 {
+		return false, errors.NewErr("[CryptoVerifyWithAlgorithm] Too few input parameters ")
+	}
+	alg := vm.PopBigInt(e)
+	if !alg.IsInt64() || (alg.Int64() != crypto.P256R1 && alg.Int64() != crypto.SM2) {
+		return false, errors.NewErr("[CryptoVerifyWithAlgorithm] Unknown algorithm!")
+	}
+	return s.verifyWithAlgorithm(e, int(alg.Int64()), "CryptoVerifyWithAlgorithm")
+}
+
+// verifyWithAlgorithm pops the encoded public key, the signature and the
+// message and pushes whether the signature is valid. Malformed keys or
+// signatures verify as false.
+func (s *StateReader) verifyWithAlgorithm(e *vm.ExecutionEngine, alg int, name string) (bool, error) {
+	if vm.EvaluationStackCount(e) < 3 {
+		return false, errors.NewErr("[" + name + "] Too few input parameters ")
+	}
+	pubKey := vm.PopByteArray(e)
+	signature := vm.PopByteArray(e)
+	message := vm.PopByteArray(e)
+	pk, err := crypto.DecodePointWithAlgorithm(alg, pubKey)
+	if err != nil {
+		vm.PushData(e, false)
+		return true, nil
+	}
+	vm.PushData(e, crypto.VerifyWithAlgorithm(alg, *pk, message, signature) == nil)
+	return true, nil
+}
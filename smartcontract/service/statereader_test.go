@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/Ontology/crypto"
+	"github.com/Ontology/smartcontract/service"
+	"github.com/Ontology/smartcontract/types"
+	"github.com/Ontology/vm/neovm"
+)
+
+// runScript deploys script and returns the item it leaves on top of the stack.
+func runScript(script []byte) (neovm.Element, bool) {
+	db := newMemoryStore()
+	codeHash := db.deploy(script)
+	engine, err := run(db, service.NewStateMachine(db, types.Application, nil), codeHash)
+	if err != nil || engine.GetState() == neovm.FAULT || neovm.EvaluationStackCount(engine) != 1 {
+		return nil, false
+	}
+	return neovm.Peek(engine), true
+}
+
+func TestCryptoSM3(t *testing.T) {
+	// GB/T 32905-2016 examples
+	vectors := []struct {
+		message string
+		digest  string
+	}{
+		{"abc", "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+		{"abcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcdabcd", "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732"},
+	}
+	for _, v := range vectors {
+		item, ok := runScript(newScriptBuilder().push([]byte(v.message)).syscall("Neo.Crypto.SM3").ToArray())
+		if !ok {
+			t.Fatalf("SM3(%q) faulted", v.message)
+		}
+		if digest := hex.EncodeToString(item.GetStackItem().GetByteArray()); digest != v.digest {
+			t.Errorf("SM3(%q) = %s, want %s", v.message, digest, v.digest)
+		}
+	}
+	if _, ok := runScript(newScriptBuilder().syscall("Neo.Crypto.SM3").ToArray()); ok {
+		t.Error("SM3 of an empty stack did not fault")
+	}
+}
+
+type signedMessage struct {
+	pubKey    []byte
+	signature []byte
+}
+
+// signWith signs message with a new key of alg, the configured algorithm is
+// restored afterwards.
+func signWith(t *testing.T, alg string, message []byte) *signedMessage {
+	configured := crypto.AlgChoice
+	crypto.SetAlg(alg)
+	defer func() {
+		if configured == crypto.SM2 {
+			crypto.SetAlg("SM2")
+		} else {
+			crypto.SetAlg("P256R1")
+		}
+	}()
+	privKey, pubKey, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := crypto.Sign(privKey, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := pubKey.EncodePoint(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &signedMessage{encoded, signature}
+}
+
+func TestCryptoVerify(t *testing.T) {
+	defer crypto.SetAlg("P256R1")
+	message := []byte("interop")
+	sm2 := signWith(t, "SM2", message)
+	p256 := signWith(t, "P256R1", message)
+
+	verify := func(name string, alg int64, m []byte, s *signedMessage) []byte {
+		b := newScriptBuilder().push(m).push(s.signature).push(s.pubKey)
+		if alg >= 0 {
+			b.EmitPushInteger(big.NewInt(alg))
+		}
+		return b.syscall(name).ToArray()
+	}
+	cases := []struct {
+		name   string
+		script []byte
+		valid  bool
+	}{
+		{"SM2 signature", verify("Neo.Crypto.VerifySM2", -1, message, sm2), true},
+		{"SM2 signature of another message", verify("Neo.Crypto.VerifySM2", -1, []byte("other"), sm2), false},
+		{"P256 signature as SM2", verify("Neo.Crypto.VerifySM2", -1, message, p256), false},
+		{"P256 signature", verify("Neo.Crypto.VerifyP256", -1, message, p256), true},
+		{"P256 signature of another message", verify("Neo.Crypto.VerifyP256", -1, []byte("other"), p256), false},
+		{"SM2 signature as P256", verify("Neo.Crypto.VerifyP256", -1, message, sm2), false},
+		{"SM2 algorithm", verify("Neo.Crypto.VerifyWithAlgorithm", crypto.SM2, message, sm2), true},
+		{"P256 algorithm", verify("Neo.Crypto.VerifyWithAlgorithm", crypto.P256R1, message, p256), true},
+		{"SM2 algorithm on a P256 signature", verify("Neo.Crypto.VerifyWithAlgorithm", crypto.SM2, message, p256), false},
+		{"P256 algorithm on an SM2 signature", verify("Neo.Crypto.VerifyWithAlgorithm", crypto.P256R1, message, sm2), false},
+	}
+	// a node verifies signatures of either algorithm whichever it is configured with
+	for _, configured := range []string{"P256R1", "SM2"} {
+		crypto.SetAlg(configured)
+		for _, c := range cases {
+			item, ok := runScript(c.script)
+			if !ok {
+				t.Errorf("%s on %s: faulted", c.name, configured)
+				continue
+			}
+			if valid := item.GetStackItem().GetBoolean(); valid != c.valid {
+				t.Errorf("%s on %s: got %v, want %v", c.name, configured, valid, c.valid)
+			}
+		}
+		if _, ok := runScript(verify("Neo.Crypto.VerifyWithAlgorithm", 7, message, sm2)); ok {
+			t.Errorf("unknown algorithm on %s did not fault", configured)
+		}
+	}
+}
@@ -23,8 +23,15 @@ import (
 	"github.com/Ontology/common"
 )
 
+// StorageContext gives access to the storage of the contract codeHash. A
+// read-only context can not put or delete. A delegated context is handed by
+// the contract to the child contract delegate and confines it to the keys
+// starting with prefix.
 type StorageContext struct {
 	codeHash common.Uint160
+	readOnly bool
+	delegate *common.Uint160
+	prefix   []byte
 }
 
 func NewStorageContext(codeHash common.Uint160) *StorageContext {
@@ -33,7 +40,42 @@ func NewStorageContext(codeHash common.Uint160) *StorageContext {
 	return &storageContext
 }
 
+// AsReadOnly returns a copy of the context which only allows reads.
+func (sc *StorageContext) AsReadOnly() *StorageContext {
+	context := *sc
+	context.readOnly = true
+	return &context
+}
+
+// Delegate returns a context for the keys of sc starting with prefix, usable
+// only by the contract child.
+func (sc *StorageContext) Delegate(child common.Uint160, prefix []byte) *StorageContext {
+	context := *sc
+	context.delegate = &child
+	context.prefix = append(append([]byte{}, sc.prefix...), prefix...)
+	return &context
+}
+
+func (sc *StorageContext) IsReadOnly() bool {
+	return sc.readOnly
+}
+
+// Holder returns the code hash of the contract allowed to use the context.
+func (sc *StorageContext) Holder() common.Uint160 {
+	if sc.delegate != nil {
+		return *sc.delegate
+	}
+	return sc.codeHash
+}
+
+// Key maps a key given by the holder to the key in the contract storage.
+func (sc *StorageContext) Key(key []byte) []byte {
+	if len(sc.prefix) == 0 {
+		return key
+	}
+	return append(append([]byte{}, sc.prefix...), key...)
+}
+
 func (sc *StorageContext) ToArray() []byte {
 	return sc.codeHash.ToArray()
 }
-
@@ -21,6 +21,7 @@ package smartcontract
 
 import (
 	"github.com/Ontology/common"
+	"github.com/Ontology/common/config"
 	"github.com/Ontology/core/contract"
 	sig "github.com/Ontology/core/signature"
 	"github.com/Ontology/smartcontract/service"
@@ -32,6 +33,7 @@ import (
 	"github.com/Ontology/errors"
 	"github.com/Ontology/common/log"
 	scommon "github.com/Ontology/smartcontract/common"
+	"github.com/Ontology/smartcontract/native"
 	"reflect"
 )
 
@@ -61,6 +63,7 @@ type Context struct {
 	Gas            common.Fixed64
 	ReturnType     contract.ContractParameterType
 	ParameterTypes []contract.ContractParameterType
+	Tracer         neovm.Tracer
 }
 
 type Engine interface {
@@ -72,12 +75,22 @@ func NewSmartContract(context *Context) (*SmartContract, error) {
 	var e Engine
 	switch context.VmType {
 	case types.NEOVM:
-		e = neovm.NewExecutionEngine(
+		engine := neovm.NewExecutionEngine(
 			context.SignableData,
 			new(neovm.ECDsaCrypto),
 			context.CacheCodeTable,
 			context.StateMachine,
 		)
+		engine.SetMaxCallDepth(config.Parameters.MaxCallDepth)
+		if context.Tracer != nil {
+			engine.SetTracer(context.Tracer)
+		}
+		if context.StateMachine != nil {
+			engine.SetNativeInvoker(native.NewInvoker(context.StateMachine, context.SignableData))
+		}
+		e = engine
+	case types.Native:
+		e = native.NewNativeEngine(context.StateMachine, context.SignableData)
 	default:
 		return nil, errors.NewErr("[NewSmartContract] Invalid vm type!")
 	}
@@ -137,10 +150,17 @@ func (sc *SmartContract) InvokeResult() (interface{}, error) {
 					states = append(states, scommon.ConvertReturnTypes(v)...)
 				}
 				return states, nil
+			case contract.Map:
+				return scommon.ConvertReturnTypes(neovm.PopStackItem(engine))[0], nil
 			default:
 				return common.ToHexString(neovm.PopByteArray(engine)), nil
 			}
 		}
+	case types.Native:
+		results := scommon.ConvertReturnTypes(sc.Engine.(*native.NativeEngine).Result())
+		if len(results) > 0 {
+			return results[0], nil
+		}
 	}
 	return nil, nil
 }
\ No newline at end of file
@@ -20,6 +20,9 @@
 package storage
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/Ontology/core/states"
 	"github.com/Ontology/core/store"
 )
@@ -111,3 +114,32 @@ func (cloneCache *CloneCache) Delete(prefix store.DataEntryPrefix, key []byte) {
 		}
 	}
 }
+
+// Find returns the items whose key starts with key, merging the writes cached in
+// memory over the ones of the underlying store, ordered by key.
+func (cloneCache *CloneCache) Find(prefix store.DataEntryPrefix, key []byte) ([]*store.StateItem, error) {
+	stateValues, err := cloneCache.Store.Find(prefix, key)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string]*store.StateItem, len(stateValues))
+	for _, v := range stateValues {
+		items[v.Key] = v
+	}
+	for _, v := range cloneCache.Memory {
+		if v.Prefix != prefix || !strings.HasPrefix(v.Key, string(key)) {
+			continue
+		}
+		if v.State == store.Deleted {
+			delete(items, v.Key)
+		} else {
+			items[v.Key] = &store.StateItem{Key: v.Key, Value: v.Value}
+		}
+	}
+	result := make([]*store.StateItem, 0, len(items))
+	for _, v := range items {
+		result = append(result, v)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
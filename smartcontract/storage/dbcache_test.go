@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Ontology/core/states"
+	"github.com/Ontology/core/store"
+)
+
+// findStore is a state store holding only the items returned by Find.
+type findStore struct {
+	store.IStateStore
+	prefix store.DataEntryPrefix
+	items  []*store.StateItem
+}
+
+func (s *findStore) Find(prefix store.DataEntryPrefix, key []byte) ([]*store.StateItem, error) {
+	var items []*store.StateItem
+	for _, item := range s.items {
+		if prefix == s.prefix && strings.HasPrefix(item.Key, string(key)) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func storageItem(value string) *states.StorageItem {
+	return &states.StorageItem{Value: []byte(value)}
+}
+
+func TestCloneCacheFind(t *testing.T) {
+	cache := NewCloneCache(&findStore{
+		prefix: store.ST_Storage,
+		items: []*store.StateItem{
+			{Key: "a1", Value: storageItem("x")},
+			{Key: "a2", Value: storageItem("y")},
+			{Key: "a3", Value: storageItem("z")},
+			{Key: "b1", Value: storageItem("w")},
+		},
+	})
+	cache.Add(store.ST_Storage, []byte("a2"), storageItem("Y"))
+	cache.Add(store.ST_Storage, []byte("a0"), storageItem("v"))
+	cache.Add(store.ST_Storage, []byte("b2"), storageItem("u"))
+	cache.Add(store.ST_Contract, []byte("a5"), storageItem("t"))
+	cache.Delete(store.ST_Storage, []byte("a3"))
+	cache.Add(store.ST_Storage, []byte("a4"), storageItem("s"))
+	cache.Delete(store.ST_Storage, []byte("a4"))
+
+	items, err := cache.Find(store.ST_Storage, []byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"a0", "v"}, {"a1", "x"}, {"a2", "Y"}}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for i, item := range items {
+		value := string(item.Value.(*states.StorageItem).Value)
+		if item.Key != want[i][0] || value != want[i][1] {
+			t.Errorf("item %d: got %s=%s, want %s=%s", i, item.Key, value, want[i][0], want[i][1])
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package storage
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Ontology/core/states"
+	"github.com/Ontology/core/store"
+)
+
+// MemoryStore is an IStateStore kept in memory, the state store of a chain
+// with no blocks. It stands in for the ledger state store in tests.
+type MemoryStore map[string]*store.StateItem
+
+func (m MemoryStore) key(prefix store.DataEntryPrefix, key []byte) string {
+	return string(append([]byte{byte(prefix)}, key...))
+}
+
+func (m MemoryStore) TryAdd(prefix store.DataEntryPrefix, key []byte, value states.IStateValue, trie bool) {
+	m[m.key(prefix, key)] = &store.StateItem{Key: string(key), Value: value, State: store.Changed}
+}
+
+func (m MemoryStore) TryGetOrAdd(prefix store.DataEntryPrefix, key []byte, value states.IStateValue, trie bool) error {
+	if _, ok := m[m.key(prefix, key)]; !ok {
+		m.TryAdd(prefix, key, value, trie)
+	}
+	return nil
+}
+
+func (m MemoryStore) TryGet(prefix store.DataEntryPrefix, key []byte) (*store.StateItem, error) {
+	return m[m.key(prefix, key)], nil
+}
+
+func (m MemoryStore) TryGetAndChange(prefix store.DataEntryPrefix, key []byte, trie bool) (states.IStateValue, error) {
+	if item, ok := m[m.key(prefix, key)]; ok {
+		return item.Value, nil
+	}
+	return nil, nil
+}
+
+func (m MemoryStore) TryDelete(prefix store.DataEntryPrefix, key []byte) {
+	delete(m, m.key(prefix, key))
+}
+
+// Find returns the items of prefix whose key starts with key, ordered by key.
+func (m MemoryStore) Find(prefix store.DataEntryPrefix, key []byte) ([]*store.StateItem, error) {
+	start := m.key(prefix, key)
+	var items []*store.StateItem
+	for k, item := range m {
+		if strings.HasPrefix(k, start) {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	return items, nil
+}
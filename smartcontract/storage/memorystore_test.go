@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/Ontology/core/states"
+	"github.com/Ontology/core/store"
+)
+
+func TestMemoryStoreFind(t *testing.T) {
+	m := MemoryStore{}
+	for _, k := range []string{"b1", "a2", "a1"} {
+		m.TryAdd(store.ST_Storage, []byte(k), storageItem(k), true)
+	}
+	m.TryAdd(store.ST_Contract, []byte("a0"), storageItem("a0"), false)
+	m.TryDelete(store.ST_Storage, []byte("a2"))
+	m.TryAdd(store.ST_Storage, []byte("a3"), storageItem("a3"), true)
+
+	items, err := m.Find(store.ST_Storage, []byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a1", "a3"}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for i, item := range items {
+		if item.Key != want[i] || string(item.Value.(*states.StorageItem).Value) != want[i] {
+			t.Errorf("item %d: got %s", i, item.Key)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trace
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/vm/neovm"
+	"github.com/Ontology/vm/neovm/asm"
+)
+
+// size of a conditional jump, the fall through target
+const jumpSize = 3
+
+type contractHits struct {
+	ips map[int]int
+	// taken and not taken counts of conditional jumps
+	branches map[int]*[2]int
+}
+
+// Coverage aggregates executed instructions and conditional jump outcomes
+// per contract.
+type Coverage struct {
+	contracts map[Uint160]*contractHits
+}
+
+func NewCoverage() *Coverage {
+	return &Coverage{contracts: make(map[Uint160]*contractHits)}
+}
+
+func (c *Coverage) hits(codeHash Uint160) *contractHits {
+	h, ok := c.contracts[codeHash]
+	if !ok {
+		h = &contractHits{ips: make(map[int]int), branches: make(map[int]*[2]int)}
+		c.contracts[codeHash] = h
+	}
+	return h
+}
+
+// Add counts the steps of one invocation. The outcome of JMPIF and JMPIFNOT
+// is taken from the next step in the same context.
+func (c *Coverage) Add(steps []neovm.TraceStep) {
+	for i, step := range steps {
+		h := c.hits(step.CodeHash)
+		h.ips[step.IP]++
+		if step.OpCode != neovm.JMPIF && step.OpCode != neovm.JMPIFNOT {
+			continue
+		}
+		if i+1 >= len(steps) {
+			continue
+		}
+		next := steps[i+1]
+		if next.Depth != step.Depth || next.CodeHash != step.CodeHash {
+			continue
+		}
+		b, ok := h.branches[step.IP]
+		if !ok {
+			b = new([2]int)
+			h.branches[step.IP] = b
+		}
+		if next.IP != step.IP+jumpSize {
+			b[0]++
+		} else {
+			b[1]++
+		}
+	}
+}
+
+// Branch is a conditional jump and how often it was taken or fell through.
+type Branch struct {
+	Offset   int
+	Taken    int
+	NotTaken int
+}
+
+// Report is the coverage of one contract.
+type Report struct {
+	CodeHash            string
+	Instructions        int
+	CoveredInstructions int
+	// Branches counts both outcomes of every conditional jump
+	Branches        int
+	CoveredBranches int
+	Uncovered       []int
+	PartialBranches []Branch
+
+	instrs   []*asm.Instruction
+	ips      map[int]int
+	branches map[int]*[2]int
+}
+
+// Report computes the coverage of code against the recorded steps.
+func (c *Coverage) Report(codeHash Uint160, code []byte) *Report {
+	h, ok := c.contracts[codeHash]
+	if !ok {
+		h = &contractHits{ips: map[int]int{}, branches: map[int]*[2]int{}}
+	}
+	r := &Report{
+		CodeHash:  ToHexString(codeHash.ToArray()),
+		Uncovered: []int{},
+		instrs:    asm.Disassemble(code),
+		ips:       make(map[int]int, len(h.ips)),
+		branches:  make(map[int]*[2]int, len(h.branches)),
+	}
+	for ip, n := range h.ips {
+		r.ips[ip] = n
+	}
+	for ip, b := range h.branches {
+		r.branches[ip] = &[2]int{b[0], b[1]}
+	}
+	for _, instr := range r.instrs {
+		r.Instructions++
+		if r.ips[instr.Offset] > 0 {
+			r.CoveredInstructions++
+		} else {
+			r.Uncovered = append(r.Uncovered, instr.Offset)
+		}
+		if instr.Raw || (instr.OpCode != neovm.JMPIF && instr.OpCode != neovm.JMPIFNOT) {
+			continue
+		}
+		r.Branches += 2
+		b := r.branches[instr.Offset]
+		if b == nil {
+			b = new([2]int)
+		}
+		if b[0] > 0 {
+			r.CoveredBranches++
+		}
+		if b[1] > 0 {
+			r.CoveredBranches++
+		}
+		if b[0] == 0 || b[1] == 0 {
+			r.PartialBranches = append(r.PartialBranches, Branch{Offset: instr.Offset, Taken: b[0], NotTaken: b[1]})
+		}
+	}
+	return r
+}
+
+// InstructionRate returns the covered share of instructions in percent.
+func (r *Report) InstructionRate() float64 {
+	return rate(r.CoveredInstructions, r.Instructions)
+}
+
+// BranchRate returns the covered share of branch outcomes in percent.
+func (r *Report) BranchRate() float64 {
+	return rate(r.CoveredBranches, r.Branches)
+}
+
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+// Format renders the disassembly annotated with hit counts, "-" marking
+// instructions never executed, followed by the summary.
+func (r *Report) Format() string {
+	var b strings.Builder
+	lines := strings.Split(strings.TrimSuffix(asm.Format(r.instrs), "\n"), "\n")
+	for i, instr := range r.instrs {
+		count := "-"
+		if n := r.ips[instr.Offset]; n > 0 {
+			count = fmt.Sprint(n)
+		}
+		line := lines[i]
+		if !instr.Raw && (instr.OpCode == neovm.JMPIF || instr.OpCode == neovm.JMPIFNOT) {
+			br := r.branches[instr.Offset]
+			if br == nil {
+				br = new([2]int)
+			}
+			line += fmt.Sprintf(" [taken %d, not taken %d]", br[0], br[1])
+		}
+		fmt.Fprintf(&b, "%6s  %s\n", count, line)
+	}
+	fmt.Fprintf(&b, "contract %s\n", r.CodeHash)
+	fmt.Fprintf(&b, "instructions: %d/%d (%.1f%%)\n", r.CoveredInstructions, r.Instructions, r.InstructionRate())
+	fmt.Fprintf(&b, "branches:     %d/%d (%.1f%%)\n", r.CoveredBranches, r.Branches, r.BranchRate())
+	return b.String()
+}
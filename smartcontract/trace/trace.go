@@ -0,0 +1,206 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package trace records the instructions NeoVM executes and aggregates them
+// into per contract coverage.
+package trace
+
+import (
+	"encoding/hex"
+	"sync"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/common/config"
+	"github.com/Ontology/common/log"
+	"github.com/Ontology/vm/neovm"
+	"github.com/Ontology/vm/neovm/asm"
+)
+
+// DefaultMaxSteps is the number of steps kept over all the stored traces
+// when TraceMaxSteps is not configured.
+const DefaultMaxSteps = 1000000
+
+// Recorder is a neovm.Tracer keeping the executed steps in memory.
+type Recorder struct {
+	steps     []neovm.TraceStep
+	limit     int
+	truncated bool
+}
+
+// NewRecorder returns a recorder keeping at most limit steps, all of them if
+// limit is not positive.
+func NewRecorder(limit int) *Recorder {
+	return &Recorder{limit: limit}
+}
+
+func (r *Recorder) CaptureStep(step *neovm.TraceStep) {
+	if r.limit > 0 && len(r.steps) >= r.limit {
+		r.truncated = true
+		return
+	}
+	r.steps = append(r.steps, *step)
+}
+
+func (r *Recorder) Steps() []neovm.TraceStep {
+	return r.steps
+}
+
+// Truncated reports whether steps were dropped after the limit.
+func (r *Recorder) Truncated() bool {
+	return r.truncated
+}
+
+// StepInfo is the JSON form of a trace step.
+type StepInfo struct {
+	CodeHash   string
+	IP         int
+	OpCode     string
+	StackDepth int
+	Depth      int
+}
+
+// TxTrace is the trace of one invoke transaction.
+type TxTrace struct {
+	TxHash    string
+	Truncated bool
+	Steps     []StepInfo
+}
+
+type storedTrace struct {
+	steps     []neovm.TraceStep
+	truncated bool
+}
+
+// Store keeps the traces of the latest transactions and the coverage of all
+// of them. The traces together hold at most maxSteps steps. It is safe for
+// concurrent use.
+type Store struct {
+	sync.RWMutex
+	traces   map[Uint256]*storedTrace
+	order    []Uint256
+	steps    int
+	maxSteps int
+	coverage *Coverage
+}
+
+// DefaultStore is filled by the ledger when tracing is enabled.
+var DefaultStore = NewStore(config.Parameters.TraceMaxSteps)
+
+// NewStore returns a store keeping at most maxSteps steps over all traces,
+// DefaultMaxSteps if it is not positive.
+func NewStore(maxSteps int) *Store {
+	if maxSteps <= 0 {
+		maxSteps = DefaultMaxSteps
+	}
+	return &Store{
+		traces:   make(map[Uint256]*storedTrace),
+		maxSteps: maxSteps,
+		coverage: NewCoverage(),
+	}
+}
+
+// NewRecorder returns a recorder whose trace fits in the store.
+func (s *Store) NewRecorder() *Recorder {
+	return NewRecorder(s.maxSteps)
+}
+
+// Put stores the trace of a transaction, dropping the oldest ones until the
+// steps of all traces fit, and adds it to the coverage.
+func (s *Store) Put(txHash Uint256, r *Recorder) {
+	trace := &storedTrace{steps: r.steps, truncated: r.truncated}
+	if len(trace.steps) > s.maxSteps {
+		trace.steps = trace.steps[:s.maxSteps]
+		trace.truncated = true
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.coverage.Add(r.steps)
+	if old, ok := s.traces[txHash]; ok {
+		s.steps -= len(old.steps)
+	} else {
+		s.order = append(s.order, txHash)
+	}
+	s.traces[txHash] = trace
+	s.steps += len(trace.steps)
+	for s.steps > s.maxSteps {
+		s.steps -= len(s.traces[s.order[0]].steps)
+		delete(s.traces, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// Get returns the trace of a transaction, nil if it is not kept.
+func (s *Store) Get(txHash Uint256) *TxTrace {
+	s.RLock()
+	defer s.RUnlock()
+	stored, ok := s.traces[txHash]
+	if !ok {
+		return nil
+	}
+	trace := &TxTrace{
+		TxHash:    ToHexString(txHash.ToArray()),
+		Truncated: stored.truncated,
+		Steps:     make([]StepInfo, 0, len(stored.steps)),
+	}
+	for _, step := range stored.steps {
+		trace.Steps = append(trace.Steps, StepInfo{
+			CodeHash:   ToHexString(step.CodeHash.ToArray()),
+			IP:         step.IP,
+			OpCode:     asm.OpName(step.OpCode),
+			StackDepth: step.StackDepth,
+			Depth:      step.Depth,
+		})
+	}
+	return trace
+}
+
+// Report returns the coverage of a contract over all traced transactions.
+func (s *Store) Report(codeHash Uint160, code []byte) *Report {
+	s.RLock()
+	defer s.RUnlock()
+	return s.coverage.Report(codeHash, code)
+}
+
+var (
+	tracedOnce      sync.Once
+	tracedContracts map[Uint160]bool
+)
+
+// Traced reports whether the ledger traces the invocations of a contract:
+// the contracts listed in TraceContracts, all of them if it is empty.
+func Traced(codeHash Uint160) bool {
+	tracedOnce.Do(func() {
+		tracedContracts = make(map[Uint160]bool)
+		for _, s := range config.Parameters.TraceContracts {
+			b, err := hex.DecodeString(s)
+			if err != nil {
+				log.Warn("[Traced] Invalid contract in TraceContracts:", s)
+				continue
+			}
+			hash, err := Uint160ParseFromBytes(b)
+			if err != nil {
+				log.Warn("[Traced] Invalid contract in TraceContracts:", s)
+				continue
+			}
+			tracedContracts[hash] = true
+		}
+	})
+	return len(tracedContracts) == 0 || tracedContracts[codeHash]
+}
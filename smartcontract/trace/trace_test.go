@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trace
+
+import (
+	"testing"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/vm/neovm"
+)
+
+func record(n int) *Recorder {
+	r := NewRecorder(0)
+	for i := 0; i < n; i++ {
+		r.CaptureStep(&neovm.TraceStep{IP: i, OpCode: neovm.NOP})
+	}
+	return r
+}
+
+func TestStoreMaxSteps(t *testing.T) {
+	s := NewStore(10)
+	s.Put(Uint256{1}, record(4))
+	s.Put(Uint256{2}, record(4))
+	s.Put(Uint256{3}, record(4))
+	if s.Get(Uint256{1}) != nil {
+		t.Errorf("oldest trace kept over the step limit")
+	}
+	if s.Get(Uint256{2}) == nil || s.Get(Uint256{3}) == nil {
+		t.Errorf("latest traces dropped")
+	}
+	if s.steps != 8 {
+		t.Errorf("steps: got %d", s.steps)
+	}
+
+	s.Put(Uint256{4}, record(12))
+	trace := s.Get(Uint256{4})
+	if trace == nil || len(trace.Steps) != 10 || !trace.Truncated {
+		t.Fatalf("trace over the limit: got %+v", trace)
+	}
+	if s.Get(Uint256{3}) != nil || s.steps != 10 {
+		t.Errorf("steps: got %d", s.steps)
+	}
+
+	if r := s.NewRecorder(); r.limit != 10 {
+		t.Errorf("recorder limit: got %d", r.limit)
+	}
+}
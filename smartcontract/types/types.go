@@ -24,8 +24,41 @@ type VmType byte
 const (
 	NEOVM VmType = iota
 	EVM
+	// Native contracts are implemented in Go, see smartcontract/native
+	Native
 )
 
+// ContractProperty is stored in the byte that held the former NeedStorage
+// bool, so contracts deployed before decode unchanged.
+type ContractProperty byte
+
+const (
+	HasStorage ContractProperty = 1 << 0
+	// HasDynamicInvoke allows the contract to APPCALL a code hash taken from the stack
+	HasDynamicInvoke ContractProperty = 1 << 1
+	// HasReentrancyGuard faults any call into the contract while it is already
+	// on the invocation stack
+	HasReentrancyGuard ContractProperty = 1 << 2
+)
+
+func NewContractProperty(needStorage, dynamicInvoke, reentrancyGuard bool) ContractProperty {
+	var p ContractProperty
+	if needStorage {
+		p |= HasStorage
+	}
+	if dynamicInvoke {
+		p |= HasDynamicInvoke
+	}
+	if reentrancyGuard {
+		p |= HasReentrancyGuard
+	}
+	return p
+}
+
+func (p ContractProperty) Has(flag ContractProperty) bool {
+	return p&flag != 0
+}
+
 type TriggerType byte
 
 const (
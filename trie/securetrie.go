@@ -22,6 +22,7 @@ package trie
 import (
 	"github.com/Ontology/common"
 	"github.com/Ontology/common/log"
+	"github.com/Ontology/rlp"
 	"fmt"
 )
 
@@ -88,6 +89,17 @@ func (t *SecureTrie) TryDelete(key []byte) error {
 	return t.trie.TryDelete(hk)
 }
 
+// Prove returns the proof of key, to be checked with VerifySecureProof.
+func (t *SecureTrie) Prove(key []byte) []rlp.RawValue {
+	return t.trie.Prove(t.hashKey(key))
+}
+
+// VerifySecureProof checks a proof made by SecureTrie.Prove and returns the
+// value of key, nil if the proof shows the key is absent.
+func VerifySecureProof(rootHash common.Uint256, key []byte, proof []rlp.RawValue) ([]byte, error) {
+	return VerifyProof(rootHash, ToHash256(key), proof)
+}
+
 func (t *SecureTrie) Commit() (common.Uint256, error) {
 	return t.CommitTo(t.trie.db)
 }
@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trie
+
+import (
+	"fmt"
+)
+
+// Walk calls fn with the key and the value of every leaf of the trie. Every
+// node is resolved from the database, the walk fails at the first node that
+// is missing or doesn't decode.
+func (t *Trie) Walk(fn func(key, value []byte) error) error {
+	return t.walk(t.root, nil, fn)
+}
+
+func (t *Trie) walk(n node, prefix []byte, fn func(key, value []byte) error) error {
+	switch n := n.(type) {
+	case nil:
+		return nil
+	case valueNode:
+		return fn(hexToKeybytes(prefix), n)
+	case *shortNode:
+		return t.walk(n.Val, concat(prefix, n.Key...), fn)
+	case *fullNode:
+		// the value child at index 16 extends the key with the terminator
+		for i, child := range n.Children {
+			if err := t.walk(child, concat(prefix, byte(i)), fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	case hashNode:
+		enc, err := t.db.Get(n)
+		if err != nil {
+			return fmt.Errorf("trie node %x: %v", []byte(n), err)
+		}
+		dec, err := decodeNode(n, enc)
+		if err != nil {
+			return fmt.Errorf("trie node %x: %v", []byte(n), err)
+		}
+		return t.walk(dec, prefix, fn)
+	}
+	return fmt.Errorf("invalid trie node %T", n)
+}
+
+// Walk calls fn with the hashed key and the value of every leaf, see
+// Trie.Walk and GetKey for the key the hash was made of.
+func (t *SecureTrie) Walk(fn func(hashedKey, value []byte) error) error {
+	return t.trie.Walk(fn)
+}
+
+// GetKey returns the key hashedKey was made of, stored along the trie nodes
+// when they were committed.
+func (t *SecureTrie) GetKey(hashedKey []byte) ([]byte, error) {
+	if key, ok := t.getSecKeyCache()[string(hashedKey)]; ok {
+		return key, nil
+	}
+	return t.trie.db.Get(t.secKey(hashedKey))
+}
+
+func hexToKeybytes(hex []byte) []byte {
+	if hasTerm(hex) {
+		hex = hex[:len(hex)-1]
+	}
+	key := make([]byte, len(hex)/2)
+	decodeNibbles(hex[:len(key)*2], key)
+	return key
+}
@@ -0,0 +1,286 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package asm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/Ontology/common/serialization"
+	"github.com/Ontology/errors"
+	"github.com/Ontology/vm/neovm"
+)
+
+// PUSH is the pseudo mnemonic that pushes hex data, a quoted string or an
+// integer encoded as ParamsBuilder emits contract parameters.
+const PUSH = "PUSH"
+
+var mnemonics = buildMnemonics()
+
+func buildMnemonics() map[string]neovm.OpCode {
+	m := map[string]neovm.OpCode{
+		"PUSHF": neovm.PUSHF,
+		"PUSHT": neovm.PUSHT,
+	}
+	for i := neovm.PUSHBYTES1; i <= neovm.PUSHBYTES75; i++ {
+		m[OpName(i)] = i
+	}
+	for i := range neovm.OpExecList {
+		if name := neovm.OpExecList[i].Name; name != "" {
+			m[name] = neovm.OpCode(i)
+		}
+	}
+	return m
+}
+
+type statement struct {
+	line   int
+	offset int
+	name   string
+	code   []byte
+	// label the jump operand refers to, resolved after all offsets are known
+	target string
+}
+
+// Assemble converts the text form produced by Format back to bytecode.
+func Assemble(text string) ([]byte, error) {
+	labels := make(map[string]int)
+	var stmts []*statement
+	offset := 0
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(stripComment(line))
+		label, rest := splitLabel(line)
+		if label != "" {
+			if _, ok := labels[label]; ok {
+				return nil, lineErr(i+1, fmt.Sprintf("duplicate label %s", label))
+			}
+			labels[label] = offset
+		}
+		if rest == "" {
+			continue
+		}
+		stmt, err := parseStatement(rest)
+		if err != nil {
+			return nil, lineErr(i+1, err.Error())
+		}
+		stmt.line = i + 1
+		stmt.offset = offset
+		offset += len(stmt.code)
+		stmts = append(stmts, stmt)
+	}
+
+	var buf bytes.Buffer
+	for _, stmt := range stmts {
+		if stmt.target != "" {
+			target, ok := labels[stmt.target]
+			if !ok {
+				return nil, lineErr(stmt.line, fmt.Sprintf("undefined label %s", stmt.target))
+			}
+			rel := target - stmt.offset
+			if rel < math.MinInt16 || rel > math.MaxInt16 {
+				return nil, lineErr(stmt.line, fmt.Sprintf("label %s out of jump range", stmt.target))
+			}
+			binary.LittleEndian.PutUint16(stmt.code[1:], uint16(int16(rel)))
+		}
+		buf.Write(stmt.code)
+	}
+	return buf.Bytes(), nil
+}
+
+func lineErr(line int, msg string) error {
+	return errors.NewErr(fmt.Sprintf("[Assemble] line %d: %s", line, msg))
+}
+
+func stripComment(line string) string {
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case ';':
+			if !quoted {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+func splitLabel(line string) (string, string) {
+	n := strings.IndexAny(line, " \t\"")
+	first := line
+	if n >= 0 {
+		first = line[:n]
+	}
+	if !strings.HasSuffix(first, ":") || len(first) == 1 {
+		return "", line
+	}
+	return strings.TrimSuffix(first, ":"), strings.TrimSpace(line[len(first):])
+}
+
+func parseStatement(s string) (*statement, error) {
+	name, operand := s, ""
+	if n := strings.IndexAny(s, " \t"); n >= 0 {
+		name, operand = s[:n], strings.TrimSpace(s[n:])
+	}
+	name = strings.ToUpper(name)
+	stmt := &statement{name: name}
+
+	switch name {
+	case DB:
+		data, err := parseHex(operand)
+		if err != nil {
+			return nil, err
+		}
+		stmt.code = data
+		return stmt, nil
+	case PUSH:
+		code, err := parsePush(operand)
+		if err != nil {
+			return nil, err
+		}
+		stmt.code = code
+		return stmt, nil
+	}
+
+	op, ok := mnemonics[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown opcode %s", name)
+	}
+	stmt.code = []byte{byte(op)}
+	switch {
+	case op >= neovm.PUSHBYTES1 && op <= neovm.PUSHBYTES75:
+		data, err := parseHex(operand)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) != int(op) {
+			return nil, fmt.Errorf("%s expects %d bytes, got %d", name, op, len(data))
+		}
+		stmt.code = append(stmt.code, data...)
+	case op == neovm.PUSHDATA1 || op == neovm.PUSHDATA2 || op == neovm.PUSHDATA4:
+		data, err := parseHex(operand)
+		if err != nil {
+			return nil, err
+		}
+		var max uint64 = math.MaxUint32
+		size := 4
+		if op == neovm.PUSHDATA1 {
+			max, size = math.MaxUint8, 1
+		} else if op == neovm.PUSHDATA2 {
+			max, size = math.MaxUint16, 2
+		}
+		if uint64(len(data)) > max {
+			return nil, fmt.Errorf("%s data too long", name)
+		}
+		length := make([]byte, 4)
+		binary.LittleEndian.PutUint32(length, uint32(len(data)))
+		stmt.code = append(stmt.code, length[:size]...)
+		stmt.code = append(stmt.code, data...)
+	case isJump(op):
+		stmt.code = append(stmt.code, 0, 0)
+		if operand == "" {
+			return nil, fmt.Errorf("%s expects a label or offset", name)
+		}
+		if operand[0] == '+' || operand[0] == '-' {
+			rel, err := strconv.ParseInt(operand, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid jump offset %s", operand)
+			}
+			binary.LittleEndian.PutUint16(stmt.code[1:], uint16(int16(rel)))
+		} else {
+			stmt.target = operand
+		}
+	case op == neovm.APPCALL || op == neovm.TAILCALL:
+		hash, err := parseHex(operand)
+		if err != nil {
+			return nil, err
+		}
+		if len(hash) != hashLen {
+			return nil, fmt.Errorf("%s expects a %d bytes script hash", name, hashLen)
+		}
+		stmt.code = append(stmt.code, hash...)
+	case op == neovm.SYSCALL:
+		api := operand
+		if strings.HasPrefix(operand, "\"") {
+			var err error
+			if api, err = strconv.Unquote(operand); err != nil {
+				return nil, fmt.Errorf("invalid interop name %s", operand)
+			}
+		}
+		if api == "" {
+			return nil, fmt.Errorf("SYSCALL expects an interop name")
+		}
+		var buf bytes.Buffer
+		serialization.WriteVarString(&buf, api)
+		stmt.code = append(stmt.code, buf.Bytes()...)
+	default:
+		if operand != "" {
+			return nil, fmt.Errorf("%s takes no operand", name)
+		}
+	}
+	return stmt, nil
+}
+
+func parseHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex data %s", s)
+	}
+	return data, nil
+}
+
+func parsePush(s string) ([]byte, error) {
+	builder := neovm.NewParamsBuilder(new(bytes.Buffer))
+	switch {
+	case s == "":
+		return nil, fmt.Errorf("PUSH expects an operand")
+	case strings.HasPrefix(s, "\""):
+		str, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", s)
+		}
+		builder.EmitPushByteArray([]byte(str))
+	case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+		data, err := parseHex(s)
+		if err != nil {
+			return nil, err
+		}
+		builder.EmitPushByteArray(data)
+	default:
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid PUSH operand %s", s)
+		}
+		builder.EmitPushInteger(n)
+	}
+	return builder.ToArray(), nil
+}
@@ -0,0 +1,243 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package asm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/Ontology/core/contract"
+	"github.com/Ontology/crypto"
+	"github.com/Ontology/vm/neovm"
+)
+
+// counter is laid out as the NeoVM compiler emits contracts: a loop testing
+// its argument with a forward JMPIFNOT, storage syscalls, an APPCALL and a
+// backward JMP.
+const counter = "52c56b6c766b00527ac46c766b00c3644d0068164e656f2e53746f726167652e476574436f6e7465787405636f756e74680f4e656f2e53746f726167652e4765745193670102030405060708090a0b0c0d0e0f10111213147562b1ff6c7566"
+
+func contracts(t *testing.T) map[string][]byte {
+	crypto.SetAlg("P256R1")
+	var keys []*crypto.PubKey
+	for i := 0; i < 3; i++ {
+		_, key, err := crypto.GenKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, &key)
+	}
+	signature, err := contract.CreateSignatureRedeemScript(keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	multiSig, err := contract.CreateMultiSigRedeemScript(2, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compiled, _ := hex.DecodeString(counter)
+	return map[string][]byte{"signature": signature, "multisig": multiSig, "compiled": compiled}
+}
+
+func assemble(t *testing.T, text string) []byte {
+	code, err := Assemble(text)
+	if err != nil {
+		t.Fatalf("%q: %v", text, err)
+	}
+	return code
+}
+
+func TestRoundTrip(t *testing.T) {
+	for name, code := range contracts(t) {
+		text := DisassembleString(code)
+		if got := assemble(t, text); !bytes.Equal(got, code) {
+			t.Errorf("%s: assembled %x from\n%s", name, got, text)
+		}
+		if again := DisassembleString(assemble(t, text)); again != text {
+			t.Errorf("%s: text changed to\n%s", name, again)
+		}
+	}
+
+	instrs := Disassemble(contracts(t)["compiled"])
+	var jumps []*Instruction
+	for _, instr := range instrs {
+		if isJump(instr.OpCode) {
+			jumps = append(jumps, instr)
+		}
+	}
+	if len(jumps) != 2 || jumps[0].Target() != 92 || jumps[1].Target() != 10 {
+		t.Fatalf("jumps: got %v", jumps)
+	}
+	text := DisassembleString(contracts(t)["compiled"])
+	if !strings.Contains(text, "JMPIFNOT L005c") || !strings.Contains(text, "JMP L000a") {
+		t.Errorf("jumps are not labeled:\n%s", text)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	code := assemble(t, `
+		PUSH1
+	loop:	DUP          ; backward target
+		JMPIFNOT end
+		DEC
+		JMP loop
+	end:	RET`)
+	want := []byte{
+		byte(neovm.PUSH1),
+		byte(neovm.DUP),
+		byte(neovm.JMPIFNOT), 7, 0,
+		byte(neovm.DEC),
+		byte(neovm.JMP), 0xfb, 0xff,
+		byte(neovm.RET),
+	}
+	if !bytes.Equal(code, want) {
+		t.Errorf("got %x, want %x", code, want)
+	}
+	if got := assemble(t, "JMP +3\nNOP\nNOP\nJMP -2"); !bytes.Equal(got, []byte{0x62, 3, 0, 0x61, 0x61, 0x62, 0xfe, 0xff}) {
+		t.Errorf("offsets: got %x", got)
+	}
+}
+
+func TestPushData(t *testing.T) {
+	for _, c := range []struct {
+		size   int
+		op     neovm.OpCode
+		header int
+	}{
+		{1, neovm.PUSHBYTES1, 1},
+		{74, neovm.OpCode(74), 1},
+		{76, neovm.PUSHDATA1, 2},
+		{255, neovm.PUSHDATA1, 2},
+		{256, neovm.PUSHDATA2, 3},
+		{65536, neovm.PUSHDATA4, 5},
+	} {
+		data := bytes.Repeat([]byte{0xab}, c.size)
+		code := assemble(t, "PUSH 0x"+hex.EncodeToString(data))
+		if neovm.OpCode(code[0]) != c.op || len(code) != c.header+c.size {
+			t.Errorf("PUSH %d bytes: got %s of %d bytes", c.size, OpName(neovm.OpCode(code[0])), len(code))
+			continue
+		}
+		instrs := Disassemble(code)
+		if len(instrs) != 1 || instrs[0].OpCode != c.op || !bytes.Equal(instrs[0].Data, data) {
+			t.Errorf("%d bytes: disassembled %v", c.size, instrs)
+		}
+		if got := assemble(t, DisassembleString(code)); !bytes.Equal(got, code) {
+			t.Errorf("%d bytes: round trip changed the code", c.size)
+		}
+	}
+
+	// explicit widths keep their encoding even if a shorter one exists
+	if got := assemble(t, "PUSHDATA2 0x0102"); !bytes.Equal(got, []byte{byte(neovm.PUSHDATA2), 2, 0, 1, 2}) {
+		t.Errorf("PUSHDATA2: got %x", got)
+	}
+	data := bytes.Repeat([]byte{0xab}, 75)
+	code := assemble(t, "PUSHBYTES75 0x"+hex.EncodeToString(data))
+	if !bytes.Equal(code, append([]byte{byte(neovm.PUSHBYTES75)}, data...)) {
+		t.Errorf("PUSHBYTES75: got %x", code[:2])
+	}
+	if got := assemble(t, DisassembleString(code)); !bytes.Equal(got, code) {
+		t.Errorf("PUSHBYTES75: round trip changed the code")
+	}
+	for text, want := range map[string][]byte{
+		"PUSH -1":    {byte(neovm.PUSHM1)},
+		"PUSH 0":     {byte(neovm.PUSH0)},
+		"PUSH 15":    {byte(neovm.PUSH15)},
+		"PUSH 17":    {1, 17},
+		`PUSH "a;b"`: {3, 'a', ';', 'b'},
+		"PUSHT":      {byte(neovm.PUSHT)},
+	} {
+		if got := assemble(t, text); !bytes.Equal(got, want) {
+			t.Errorf("%s: got %x, want %x", text, got, want)
+		}
+	}
+}
+
+func TestSysCallAndAppCall(t *testing.T) {
+	want := append([]byte{byte(neovm.SYSCALL), 15}, "Neo.Storage.Put"...)
+	for _, text := range []string{`SYSCALL "Neo.Storage.Put"`, "SYSCALL Neo.Storage.Put"} {
+		if got := assemble(t, text); !bytes.Equal(got, want) {
+			t.Errorf("%s: got %x", text, got)
+		}
+	}
+	long := strings.Repeat("n", 300)
+	code := assemble(t, `SYSCALL "`+long+`"`)
+	if code[1] != 0xfd || len(code) != 4+len(long) {
+		t.Errorf("long name: got %x", code[:4])
+	}
+	instrs := Disassemble(code)
+	if len(instrs) != 1 || string(instrs[0].Data) != long {
+		t.Errorf("long name: disassembled %v", instrs)
+	}
+
+	hash := "0x0102030405060708090a0b0c0d0e0f1011121314"
+	for _, op := range []string{"APPCALL", "TAILCALL"} {
+		code := assemble(t, op+" "+hash)
+		text := DisassembleString(code)
+		if len(code) != 21 || !strings.Contains(text, op+" "+hash) {
+			t.Errorf("%s: got %x\n%s", op, code, text)
+		}
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	for _, text := range []string{
+		"FOO",
+		"NOP 1",
+		"PUSHBYTES2 0x01",
+		"PUSHBYTES1 0xzz",
+		"PUSH",
+		"PUSH 1.5",
+		`PUSH "open`,
+		"JMP",
+		"JMP nowhere",
+		"JMP +40000",
+		"a: NOP\na: NOP",
+		"SYSCALL",
+		`SYSCALL ""`,
+		"APPCALL 0x0102",
+		"JMP far\nDB 0x" + strings.Repeat("00", 40000) + "\nfar: RET",
+	} {
+		if _, err := Assemble(text); err == nil {
+			t.Errorf("%.40q: expected error", text)
+		}
+	}
+	if _, err := Assemble("NOP\nFOO"); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("error does not name the line: %v", err)
+	}
+
+	// bytes that do not decode survive a round trip as DB
+	for _, code := range [][]byte{
+		{0xff},
+		{2, 1},
+		{byte(neovm.PUSHDATA2), 5, 0, 1},
+		{byte(neovm.JMP), 1},
+		{byte(neovm.APPCALL), 1, 2},
+		{byte(neovm.SYSCALL), 0xfd, 1},
+	} {
+		instrs := Disassemble(code)
+		if len(instrs) != 1 || !instrs[0].Raw {
+			t.Errorf("%x: disassembled %v", code, instrs)
+		}
+		if got := assemble(t, DisassembleString(code)); !bytes.Equal(got, code) {
+			t.Errorf("%x: assembled %x", code, got)
+		}
+	}
+}
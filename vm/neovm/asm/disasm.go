@@ -0,0 +1,246 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package asm converts NeoVM bytecode to a readable text form and back.
+//
+// The text form has one instruction per line, an optional "label:" in front of
+// it and ";" comments. Operands are hex data for PUSHBYTES and PUSHDATA, a
+// quoted interop name for SYSCALL, a hex script hash for APPCALL and TAILCALL
+// and a label or signed offset for JMP, JMPIF, JMPIFNOT and CALL. Bytes that
+// do not decode are written as "DB 0x..".
+package asm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode"
+
+	. "github.com/Ontology/common"
+	"github.com/Ontology/vm/neovm"
+)
+
+const (
+	// DB is the pseudo mnemonic for raw bytes.
+	DB = "DB"
+
+	hashLen = 20
+)
+
+// Instruction is a decoded opcode with its operand.
+type Instruction struct {
+	Offset int
+	OpCode neovm.OpCode
+	// Data is the pushed data, the SYSCALL name, the APPCALL/TAILCALL hash
+	// or, for raw bytes, the undecoded bytes.
+	Data []byte
+	// Jump is the offset of a JMP, JMPIF, JMPIFNOT or CALL target relative
+	// to the start of the instruction.
+	Jump int16
+	Size int
+	Raw  bool
+}
+
+// Target returns the absolute offset a jump instruction points to.
+func (i *Instruction) Target() int {
+	return i.Offset + int(i.Jump)
+}
+
+// Name returns the mnemonic of the instruction.
+func (i *Instruction) Name() string {
+	if i.Raw {
+		return DB
+	}
+	return OpName(i.OpCode)
+}
+
+// OpName returns the mnemonic of an opcode, or its hex value if it is unknown.
+func OpName(op neovm.OpCode) string {
+	if op >= neovm.PUSHBYTES1 && op <= neovm.PUSHBYTES75 {
+		return fmt.Sprintf("PUSHBYTES%d", op)
+	}
+	if name := neovm.OpExecList[op].Name; name != "" {
+		return name
+	}
+	return fmt.Sprintf("0x%02x", byte(op))
+}
+
+func isJump(op neovm.OpCode) bool {
+	return op == neovm.JMP || op == neovm.JMPIF || op == neovm.JMPIFNOT || op == neovm.CALL
+}
+
+func isKnown(op neovm.OpCode) bool {
+	return (op >= neovm.PUSHBYTES1 && op <= neovm.PUSHBYTES75) || neovm.OpExecList[op].Name != ""
+}
+
+// Disassemble decodes code into instructions. Unknown opcodes and truncated
+// operands are returned as raw instructions so that the result always covers
+// the whole code.
+func Disassemble(code []byte) []*Instruction {
+	var instrs []*Instruction
+	for offset := 0; offset < len(code); {
+		instr := decode(code, offset)
+		instrs = append(instrs, instr)
+		offset += instr.Size
+	}
+	return instrs
+}
+
+func decode(code []byte, offset int) *Instruction {
+	op := neovm.OpCode(code[offset])
+	instr := &Instruction{Offset: offset, OpCode: op, Size: 1}
+	if !isKnown(op) {
+		instr.Raw = true
+		instr.Data = code[offset : offset+1]
+		return instr
+	}
+	rest := code[offset+1:]
+	ok := true
+	take := func(skip int, n uint64) ([]byte, bool) {
+		if skip > len(rest) || uint64(len(rest)-skip) < n {
+			return nil, false
+		}
+		instr.Size += skip + int(n)
+		return rest[skip : skip+int(n)], true
+	}
+	switch {
+	case op >= neovm.PUSHBYTES1 && op <= neovm.PUSHBYTES75:
+		instr.Data, ok = take(0, uint64(op))
+	case op == neovm.PUSHDATA1:
+		if ok = len(rest) >= 1; ok {
+			instr.Data, ok = take(1, uint64(rest[0]))
+		}
+	case op == neovm.PUSHDATA2:
+		if ok = len(rest) >= 2; ok {
+			instr.Data, ok = take(2, uint64(binary.LittleEndian.Uint16(rest)))
+		}
+	case op == neovm.PUSHDATA4:
+		if ok = len(rest) >= 4; ok {
+			instr.Data, ok = take(4, uint64(binary.LittleEndian.Uint32(rest)))
+		}
+	case isJump(op):
+		if ok = len(rest) >= 2; ok {
+			instr.Jump = int16(binary.LittleEndian.Uint16(rest))
+			instr.Size += 2
+		}
+	case op == neovm.APPCALL || op == neovm.TAILCALL:
+		instr.Data, ok = take(0, hashLen)
+	case op == neovm.SYSCALL:
+		if ok = len(rest) >= 1; ok {
+			switch rest[0] {
+			case 0xFD:
+				if ok = len(rest) >= 3; ok {
+					instr.Data, ok = take(3, uint64(binary.LittleEndian.Uint16(rest[1:])))
+				}
+			case 0xFE:
+				if ok = len(rest) >= 5; ok {
+					instr.Data, ok = take(5, uint64(binary.LittleEndian.Uint32(rest[1:])))
+				}
+			case 0xFF:
+				if ok = len(rest) >= 9; ok {
+					instr.Data, ok = take(9, binary.LittleEndian.Uint64(rest[1:]))
+				}
+			default:
+				instr.Data, ok = take(1, uint64(rest[0]))
+			}
+		}
+	}
+	if !ok {
+		instr.Raw = true
+		instr.Data = code[offset:]
+		instr.Size = len(instr.Data)
+		return instr
+	}
+	return instr
+}
+
+// Format renders instructions in the text form accepted by Assemble. Jump
+// targets get an "L<offset>" label and every line is commented with its offset.
+func Format(instrs []*Instruction) string {
+	starts := make(map[int]bool, len(instrs))
+	for _, instr := range instrs {
+		starts[instr.Offset] = true
+	}
+	targets := make(map[int]bool)
+	for _, instr := range instrs {
+		if !instr.Raw && isJump(instr.OpCode) && starts[instr.Target()] {
+			targets[instr.Target()] = true
+		}
+	}
+
+	var b strings.Builder
+	for _, instr := range instrs {
+		label := ""
+		if targets[instr.Offset] {
+			label = labelName(instr.Offset) + ":"
+		}
+		text := instr.Name()
+		if operand := formatOperand(instr, targets); operand != "" {
+			text += " " + operand
+		}
+		comment := fmt.Sprintf("%04x", instr.Offset)
+		if s, ok := printable(instr); ok {
+			comment += " " + s
+		}
+		fmt.Fprintf(&b, "%-8s%-40s ; %s\n", label, text, comment)
+	}
+	return b.String()
+}
+
+// DisassembleString is a shortcut for Format(Disassemble(code)).
+func DisassembleString(code []byte) string {
+	return Format(Disassemble(code))
+}
+
+func labelName(offset int) string {
+	return fmt.Sprintf("L%04x", offset)
+}
+
+func formatOperand(instr *Instruction, targets map[int]bool) string {
+	if instr.Raw {
+		return "0x" + ToHexString(instr.Data)
+	}
+	op := instr.OpCode
+	switch {
+	case isJump(op):
+		if targets[instr.Target()] {
+			return labelName(instr.Target())
+		}
+		return fmt.Sprintf("%+d", instr.Jump)
+	case op == neovm.SYSCALL:
+		return fmt.Sprintf("%q", string(instr.Data))
+	case op >= neovm.PUSHBYTES1 && op <= neovm.PUSHDATA4,
+		op == neovm.APPCALL, op == neovm.TAILCALL:
+		return "0x" + ToHexString(instr.Data)
+	}
+	return ""
+}
+
+// printable returns pushed data as a quoted string if it is readable text.
+func printable(instr *Instruction) (string, bool) {
+	if instr.Raw || instr.OpCode < neovm.PUSHBYTES1 || instr.OpCode > neovm.PUSHDATA4 || len(instr.Data) == 0 {
+		return "", false
+	}
+	for _, c := range string(instr.Data) {
+		if c > unicode.MaxASCII || !unicode.IsPrint(c) {
+			return "", false
+		}
+	}
+	return fmt.Sprintf("%q", string(instr.Data)), true
+}
@@ -349,6 +349,8 @@ func NewStackItemInterface(data interface{}) types.StackItemInterface {
 		stackItem = data.(*types.ByteArray)
 	case *types.Struct:
 		stackItem = data.(*types.Struct)
+	case *types.Map:
+		stackItem = data.(*types.Map)
 	case bool:
 		stackItem = types.NewBoolean(data.(bool))
 	case []byte:
@@ -35,6 +35,7 @@ var (
 	ErrOverMaxArraySize      = errors.New("the array over max size")
 	ErrOverMaxBigIntegerSize = errors.New("the biginteger over max size 32bit")
 	ErrOutOfGas              = errors.New("out of gas")
+	ErrOverMaxSteps          = errors.New("the execution over max steps")
 	ErrNotArray              = errors.New("not array")
 	ErrTableIsNil            = errors.New("table is nil")
 	ErrServiceIsNil          = errors.New("service is nil")
@@ -45,4 +46,8 @@ var (
 	ErrCallingContextNil     = errors.New("calling context is nil")
 	ErrEntryContextNil       = errors.New("entry context is nil")
 	ErrAppendNotArray        = errors.New("append not array")
+	ErrNotMap                = errors.New("not map")
+	ErrNotMapKey             = errors.New("invalid map key")
+	ErrDynamicInvoke         = errors.New("dynamic invoke not allowed")
+	ErrReentrancy            = errors.New("contract reentrancy not allowed")
 )
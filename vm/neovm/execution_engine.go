@@ -44,6 +44,7 @@ func NewExecutionEngine(container interfaces.ICodeContainer, crypto interfaces.I
 
 	engine.context = nil
 	engine.opCode = 0
+	engine.maxCallDepth = MaxInvovationStackSize
 
 	engine.service = NewInteropService()
 
@@ -71,6 +72,19 @@ type ExecutionEngine struct {
 	//current opcode
 	opCode          OpCode
 	gas             int64
+	maxCallDepth    int
+	maxSteps        int
+	steps           int
+
+	breakPoints     map[common.Uint160]map[uint]bool
+	tracer          Tracer
+	native          NativeInvoker
+}
+
+// BreakPoint is an instruction offset in the script with the given code hash.
+type BreakPoint struct {
+	CodeHash common.Uint160
+	Offset   uint
 }
 
 func (e *ExecutionEngine) Create(caller common.Uint160, code []byte) ([]byte, error) {
@@ -87,6 +101,20 @@ func (e *ExecutionEngine) Call(caller common.Uint160, code, input []byte) ([]byt
 	return nil, nil
 }
 
+// SetMaxCallDepth limits the invocation stack, MaxInvovationStackSize by default.
+func (e *ExecutionEngine) SetMaxCallDepth(depth int) {
+	if depth > 0 {
+		e.maxCallDepth = depth
+	}
+}
+
+// SetMaxSteps limits the number of instructions executed, unlimited by default.
+func (e *ExecutionEngine) SetMaxSteps(steps int) {
+	if steps > 0 {
+		e.maxSteps = steps
+	}
+}
+
 func (e *ExecutionEngine) GetCodeContainer() interfaces.ICodeContainer {
 	return e.codeContainer
 }
@@ -103,6 +131,14 @@ func (e *ExecutionEngine) GetEvaluationStackCount() int {
 	return e.evaluationStack.Count()
 }
 
+func (e *ExecutionEngine) GetAltStack() *RandomAccessStack {
+	return e.altStack
+}
+
+func (e *ExecutionEngine) GetInvocationStack() *RandomAccessStack {
+	return e.invocationStack
+}
+
 func (e *ExecutionEngine) GetExecuteResult() bool {
 	if e.evaluationStack.Count() < 1 {
 		return false
@@ -171,11 +207,13 @@ func (e *ExecutionEngine) StepInto() error {
 	}
 	context, err := e.CurrentContext()
 	if err != nil {
+		e.state = FAULT
 		return err
 	}
 	var opCode OpCode
 
-	if context.GetInstructionPointer() >= len(context.Code) {
+	ip := context.GetInstructionPointer()
+	if ip >= len(context.Code) {
 		opCode = RET
 	} else {
 		o, err := context.OpReader.ReadByte()
@@ -187,20 +225,26 @@ func (e *ExecutionEngine) StepInto() error {
 	}
 	e.opCode = opCode
 	e.context = context
+	if e.tracer != nil {
+		e.captureStep(context, ip, opCode)
+	}
 	if !e.checkStackSize() {
+		e.state = FAULT
 		return ErrOverLimitStack
 	}
+	e.steps++
+	if e.maxSteps > 0 && e.steps > e.maxSteps {
+		e.state = FAULT
+		return ErrOverMaxSteps
+	}
 	state, err := e.ExecuteOp()
 
 	if state == HALT || state == FAULT {
 		e.state = state
 		return err
 	}
-	for _, v := range context.BreakPoints {
-		if v == uint(context.InstructionPointer) {
-			e.state = HALT
-			return nil
-		}
+	if e.isBreakPoint() {
+		e.state = BREAK
 	}
 	return nil
 }
@@ -228,57 +272,126 @@ func (e *ExecutionEngine) ExecuteOp() (VMState, error) {
 	return opExec.Exec(e)
 }
 
-func (e *ExecutionEngine) StepOut() {
+// StepOut runs until the current context returns to its caller.
+func (e *ExecutionEngine) StepOut() error {
 	e.state = e.state & (^BREAK)
 	c := e.invocationStack.Count()
 	for {
-		if e.state == FAULT || e.state == HALT || e.state == BREAK || e.invocationStack.Count() >= c {
+		if e.state == FAULT || e.state == HALT || e.state == BREAK || e.invocationStack.Count() < c {
 			break
 		}
-		e.StepInto()
+		if err := e.StepInto(); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (e *ExecutionEngine) StepOver() {
+// StepOver executes the next instruction, running any call it makes to completion.
+func (e *ExecutionEngine) StepOver() error {
 	if e.state == FAULT || e.state == HALT {
-		return
+		return nil
 	}
 	e.state = e.state & (^BREAK)
 	c := e.invocationStack.Count()
+	if err := e.StepInto(); err != nil {
+		return err
+	}
 	for {
-		if e.state == FAULT || e.state == HALT || e.state == BREAK || e.invocationStack.Count() > c {
+		if e.state == FAULT || e.state == HALT || e.state == BREAK || e.invocationStack.Count() <= c {
 			break
 		}
-		e.StepInto()
+		if err := e.StepInto(); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 func (e *ExecutionEngine) AddBreakPoint(position uint) {
-	e.context.BreakPoints = append(e.context.BreakPoints, position)
+	context, err := e.CurrentContext()
+	if err != nil {
+		return
+	}
+	context.BreakPoints = append(context.BreakPoints, position)
 }
 
 func (e *ExecutionEngine) RemoveBreakPoint(position uint) bool {
-	if e.invocationStack.Count() == 0 {
+	context, err := e.CurrentContext()
+	if err != nil {
 		return false
 	}
 	bs := make([]uint, 0)
-	breakPoints := e.context.BreakPoints
-	for _, v := range breakPoints {
+	for _, v := range context.BreakPoints {
 		if v != position {
 			bs = append(bs, v)
 		}
 	}
-	e.context.BreakPoints = bs
+	context.BreakPoints = bs
+	return true
+}
+
+// AddCodeBreakPoint stops execution before the instruction at offset of the
+// script with the given code hash, whichever context runs it.
+func (e *ExecutionEngine) AddCodeBreakPoint(codeHash common.Uint160, offset uint) {
+	if e.breakPoints == nil {
+		e.breakPoints = make(map[common.Uint160]map[uint]bool)
+	}
+	if e.breakPoints[codeHash] == nil {
+		e.breakPoints[codeHash] = make(map[uint]bool)
+	}
+	e.breakPoints[codeHash][offset] = true
+}
+
+func (e *ExecutionEngine) RemoveCodeBreakPoint(codeHash common.Uint160, offset uint) bool {
+	if !e.breakPoints[codeHash][offset] {
+		return false
+	}
+	delete(e.breakPoints[codeHash], offset)
 	return true
 }
 
+func (e *ExecutionEngine) GetCodeBreakPoints() []BreakPoint {
+	var breakPoints []BreakPoint
+	for codeHash, offsets := range e.breakPoints {
+		for offset := range offsets {
+			breakPoints = append(breakPoints, BreakPoint{CodeHash: codeHash, Offset: offset})
+		}
+	}
+	return breakPoints
+}
+
+func (e *ExecutionEngine) isBreakPoint() bool {
+	if e.invocationStack.Count() == 0 {
+		return false
+	}
+	context, err := e.CurrentContext()
+	if err != nil {
+		return false
+	}
+	position := uint(context.GetInstructionPointer())
+	for _, v := range context.BreakPoints {
+		if v == position {
+			return true
+		}
+	}
+	if len(e.breakPoints) == 0 {
+		return false
+	}
+	codeHash, err := context.GetCodeHash()
+	if err != nil {
+		return false
+	}
+	return e.breakPoints[codeHash][position]
+}
+
 func (e *ExecutionEngine) checkStackSize() bool {
 	size := 0
 	if e.opCode < PUSH16 {
 		size = 1
 	} else {
 		switch e.opCode {
-		case DEPTH, DUP, OVER, TUCK:
+		case DEPTH, DUP, OVER, TUCK, NEWMAP:
 			size = 1
 		case UNPACK:
 			item := Peek(e)
@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	"testing"
+
+	"github.com/Ontology/common"
+)
+
+// newDebugEngine loads a script calling a contract computing 2+3, then adding 1
+// to the result. The call is at offset 0 of the script and returns at 21.
+func newDebugEngine() (*ExecutionEngine, common.Uint160) {
+	table := newContractTable()
+	callee := table.deploy([]byte{byte(PUSH2), byte(PUSH3), byte(ADD)}, false, false)
+	script := append([]byte{byte(APPCALL)}, callee.ToArray()...)
+	script = append(script, byte(PUSH1), byte(ADD))
+	e := NewExecutionEngine(nil, nil, table, nil)
+	e.LoadCode(script, false)
+	return e, callee
+}
+
+// checkPosition checks the call depth, instruction pointer and evaluation stack size.
+func checkPosition(t *testing.T, name string, e *ExecutionEngine, depth, ip, count int) {
+	if e.invocationStack.Count() != depth {
+		t.Fatalf("%s: got depth %d, want %d", name, e.invocationStack.Count(), depth)
+	}
+	context, err := e.CurrentContext()
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if context.GetInstructionPointer() != ip || e.GetEvaluationStackCount() != count {
+		t.Fatalf("%s: got offset %d with %d items, want %d with %d", name,
+			context.GetInstructionPointer(), e.GetEvaluationStackCount(), ip, count)
+	}
+}
+
+func checkResult(t *testing.T, e *ExecutionEngine, want int) {
+	if err := e.Execute(); err != nil || e.GetState() != HALT {
+		t.Fatalf("got state %d, %v", e.GetState(), err)
+	}
+	if n := PopInt(e); n != want {
+		t.Errorf("got %d, want %d", n, want)
+	}
+}
+
+func TestStepInto(t *testing.T) {
+	e, _ := newDebugEngine()
+	if err := e.StepInto(); err != nil {
+		t.Fatal(err)
+	}
+	checkPosition(t, "call", e, 2, 0, 0)
+	if err := e.StepInto(); err != nil {
+		t.Fatal(err)
+	}
+	checkPosition(t, "PUSH2", e, 2, 1, 1)
+	if err := e.StepOut(); err != nil {
+		t.Fatal(err)
+	}
+	checkPosition(t, "step out", e, 1, 21, 1)
+	checkResult(t, e, 6)
+}
+
+func TestStepOver(t *testing.T) {
+	e, callee := newDebugEngine()
+	if err := e.StepOver(); err != nil {
+		t.Fatal(err)
+	}
+	checkPosition(t, "call", e, 1, 21, 1)
+	if err := e.StepOver(); err != nil {
+		t.Fatal(err)
+	}
+	checkPosition(t, "PUSH1", e, 1, 22, 2)
+	checkResult(t, e, 6)
+
+	// a breakpoint in the callee stops stepping over the call
+	e, _ = newDebugEngine()
+	e.AddCodeBreakPoint(callee, 2)
+	if err := e.StepOver(); err != nil {
+		t.Fatal(err)
+	}
+	if e.GetState() != BREAK {
+		t.Fatalf("got state %d", e.GetState())
+	}
+	checkPosition(t, "breakpoint", e, 2, 2, 2)
+	checkResult(t, e, 6)
+}
+
+func TestBreakPoints(t *testing.T) {
+	e, callee := newDebugEngine()
+	e.AddBreakPoint(22)
+	e.AddCodeBreakPoint(callee, 1)
+	for _, position := range []struct {
+		name             string
+		depth, ip, count int
+	}{
+		{"callee", 2, 1, 1},
+		{"caller", 1, 22, 2},
+	} {
+		if err := e.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		if e.GetState() != BREAK {
+			t.Fatalf("%s: got state %d", position.name, e.GetState())
+		}
+		checkPosition(t, position.name, e, position.depth, position.ip, position.count)
+	}
+	checkResult(t, e, 6)
+
+	e, callee = newDebugEngine()
+	e.AddBreakPoint(22)
+	e.RemoveBreakPoint(22)
+	e.AddCodeBreakPoint(callee, 1)
+	if !e.RemoveCodeBreakPoint(callee, 1) || e.RemoveCodeBreakPoint(callee, 1) {
+		t.Error("code breakpoint removed twice")
+	}
+	checkResult(t, e, 6)
+}
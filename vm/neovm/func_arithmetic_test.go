@@ -26,17 +26,16 @@ import (
 )
 
 var (
-	engine = NewExecutionEngine(nil, nil, nil, nil, 0)
+	engine = NewExecutionEngine(nil, nil, nil, nil)
 )
 
 func TestOpBigInt(t *testing.T) {
-	state, err := opBigInt(engine)
-	t.Log("state:", state, "err:", err)
+	var err error
 
 	engine.evaluationStack.Push(NewStackItem(types.NewInteger(big.NewInt(1))))
 	engine.opCode = INC
 
-	state, err = opBigInt(engine)
+	_, err = opBigInt(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -46,7 +45,7 @@ func TestOpBigInt(t *testing.T) {
 
 	engine.opCode = DEC
 
-	state, err = opBigInt(engine)
+	_, err = opBigInt(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -55,7 +54,7 @@ func TestOpBigInt(t *testing.T) {
 	t.Log("2 dec result 1, execute result:", engine.evaluationStack.Peek(0).GetStackItem().GetBigInteger())
 
 	engine.opCode = NEGATE
-	state, err = opBigInt(engine)
+	_, err = opBigInt(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -64,7 +63,7 @@ func TestOpBigInt(t *testing.T) {
 	t.Log("1 negate result -1, execute result:", engine.evaluationStack.Peek(0).GetStackItem().GetBigInteger())
 
 	engine.opCode = ABS
-	state, err = opBigInt(engine)
+	_, err = opBigInt(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -74,13 +73,12 @@ func TestOpBigInt(t *testing.T) {
 }
 
 func TestOpNot(t *testing.T) {
-	engine := NewExecutionEngine(nil, nil, nil, nil, 0)
-	state, err := opNot(engine)
-	t.Log("state:", state, "err:", err)
+	engine := NewExecutionEngine(nil, nil, nil, nil)
+	var err error
 
 	engine.evaluationStack.Push(NewStackItem(types.NewBoolean(false)))
 	engine.opCode = NOT
-	state, err = opNot(engine)
+	_, err = opNot(engine)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -90,16 +88,11 @@ func TestOpNot(t *testing.T) {
 }
 
 func TestOpNz(t *testing.T) {
-	state, err := opNz(engine)
-	t.Log("state:", state, "err:", err)
-
-	if err != nil {
-		t.Fatal(err)
-	}
+	var err error
 
 	engine.evaluationStack.Push(NewStackItem(types.NewInteger(big.NewInt(1))))
 	engine.opCode = NZ
-	state, err = opNz(engine)
+	_, err = opNz(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -108,7 +101,7 @@ func TestOpNz(t *testing.T) {
 	t.Log("1 nz result true, execute result:", engine.evaluationStack.Peek(0).GetStackItem().GetBoolean())
 
 	engine.evaluationStack.Push(NewStackItem(types.NewInteger(big.NewInt(0))))
-	state, err = opNz(engine)
+	_, err = opNz(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -119,15 +112,14 @@ func TestOpNz(t *testing.T) {
 }
 
 func TestBigIntZip(t *testing.T) {
-	state, err := opBigIntZip(engine)
-	t.Log("state:", state, "err:", err)
+	var err error
 
 	engine.evaluationStack.Push(NewStackItem(types.NewInteger(big.NewInt(1))))
 	engine.evaluationStack.Push(NewStackItem(types.NewInteger(big.NewInt(2))))
 
 	engine.opCode = ADD
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -139,7 +131,7 @@ func TestBigIntZip(t *testing.T) {
 
 	engine.opCode = SUB
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -151,7 +143,7 @@ func TestBigIntZip(t *testing.T) {
 
 	engine.opCode = MUL
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -163,7 +155,7 @@ func TestBigIntZip(t *testing.T) {
 
 	engine.opCode = DIV
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -175,7 +167,7 @@ func TestBigIntZip(t *testing.T) {
 
 	engine.opCode = MOD
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -187,7 +179,7 @@ func TestBigIntZip(t *testing.T) {
 
 	engine.opCode = SHL
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -199,7 +191,7 @@ func TestBigIntZip(t *testing.T) {
 
 	engine.opCode = SHR
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -211,7 +203,7 @@ func TestBigIntZip(t *testing.T) {
 
 	engine.opCode = MIN
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -223,7 +215,7 @@ func TestBigIntZip(t *testing.T) {
 
 	engine.opCode = MAX
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -235,7 +227,7 @@ func TestBigIntZip(t *testing.T) {
 
 	engine.opCode = AND
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -247,7 +239,7 @@ func TestBigIntZip(t *testing.T) {
 
 	engine.opCode = OR
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -259,7 +251,7 @@ func TestBigIntZip(t *testing.T) {
 
 	engine.opCode = XOR
 
-	state, err = opBigIntZip(engine)
+	_, err = opBigIntZip(engine)
 
 	if err != nil {
 		t.Fatal(err)
@@ -28,6 +28,8 @@ func opArraySize(e *ExecutionEngine) (VMState, error) {
 	item := PopStackItem(e)
 	if _, ok := item.(*types.Array); ok {
 		PushData(e, len(item.GetArray()))
+	} else if m, ok := item.(*types.Map); ok {
+		PushData(e, m.Count())
 	} else {
 		PushData(e, len(item.GetByteArray()))
 	}
@@ -56,8 +58,15 @@ func opUnpack(e *ExecutionEngine) (VMState, error) {
 }
 
 func opPickItem(e *ExecutionEngine) (VMState, error) {
-	index := PopInt(e)
-	items := PopArray(e)
+	key := PopStackItem(e)
+	item := PopStackItem(e)
+	if m, ok := item.(*types.Map); ok {
+		value, _ := m.TryGetValue(key)
+		PushData(e, value)
+		return NONE, nil
+	}
+	index := int(key.GetBigInteger().Int64())
+	items := item.GetArray()
 	PushData(e, items[index])
 	return NONE, nil
 }
@@ -67,8 +76,14 @@ func opSetItem(e *ExecutionEngine) (VMState, error) {
 	if value, ok := newItem.(*types.Struct); ok {
 		newItem = value.Clone()
 	}
-	index := PopInt(e)
-	items := PopArray(e)
+	key := PopStackItem(e)
+	item := PopStackItem(e)
+	if m, ok := item.(*types.Map); ok {
+		m.Add(key, newItem)
+		return NONE, nil
+	}
+	index := int(key.GetBigInteger().Int64())
+	items := item.GetArray()
 	items[index] = newItem
 	return NONE, nil
 }
@@ -111,3 +126,53 @@ func opReverse(e *ExecutionEngine) (VMState, error) {
 	return NONE, nil
 }
 
+func opNewMap(e *ExecutionEngine) (VMState, error) {
+	PushData(e, types.NewMap())
+	return NONE, nil
+}
+
+func opRemove(e *ExecutionEngine) (VMState, error) {
+	key := PopStackItem(e)
+	item := PopStackItem(e)
+	if m, ok := item.(*types.Map); ok {
+		m.Remove(key)
+		return NONE, nil
+	}
+	item.(*types.Array).RemoveAt(int(key.GetBigInteger().Int64()))
+	return NONE, nil
+}
+
+func opHasKey(e *ExecutionEngine) (VMState, error) {
+	key := PopStackItem(e)
+	item := PopStackItem(e)
+	if m, ok := item.(*types.Map); ok {
+		PushData(e, m.ContainsKey(key))
+		return NONE, nil
+	}
+	index := key.GetBigInteger()
+	PushData(e, index.Cmp(big.NewInt(int64(len(item.GetArray())))) < 0)
+	return NONE, nil
+}
+
+func opKeys(e *ExecutionEngine) (VMState, error) {
+	m := PopStackItem(e).(*types.Map)
+	PushData(e, types.NewArray(m.GetKeys()))
+	return NONE, nil
+}
+
+func opValues(e *ExecutionEngine) (VMState, error) {
+	item := PopStackItem(e)
+	var values []types.StackItemInterface
+	if m, ok := item.(*types.Map); ok {
+		values = m.GetValues()
+	} else {
+		values = append(values, item.GetArray()...)
+	}
+	for i, v := range values {
+		if value, ok := v.(*types.Struct); ok {
+			values[i] = value.Clone()
+		}
+	}
+	PushData(e, types.NewArray(values))
+	return NONE, nil
+}
@@ -22,7 +22,10 @@ package neovm
 import (
 	"testing"
 	"math/big"
+	"reflect"
+	"github.com/Ontology/common/log"
 	"github.com/Ontology/vm/neovm/types"
+	. "github.com/Ontology/vm/neovm/errors"
 )
 
 func TestOpArraySize(t *testing.T) {
@@ -96,4 +99,93 @@ func TestOpPickItem(t *testing.T) {
 
 }
 
+func init() {
+	log.Init()
+}
+
+// mapScript builds the map {1: 5, 3: 4}, overwriting the first value set for 1.
+var mapScript = []byte{
+	byte(NEWMAP),
+	byte(DUP), byte(PUSH1), byte(PUSH2), byte(SETITEM),
+	byte(DUP), byte(PUSH3), byte(PUSH4), byte(SETITEM),
+	byte(DUP), byte(PUSH1), byte(PUSH5), byte(SETITEM),
+}
+
+func runMapScript(ops ...OpCode) (*ExecutionEngine, error) {
+	script := append([]byte{}, mapScript...)
+	for _, op := range ops {
+		script = append(script, byte(op))
+	}
+	e := NewExecutionEngine(nil, nil, nil, nil)
+	e.LoadCode(script, false)
+	return e, e.Execute()
+}
+
+func TestMapOpcodes(t *testing.T) {
+	ints := func(items []types.StackItemInterface) []int64 {
+		var n []int64
+		for _, item := range items {
+			n = append(n, item.GetBigInteger().Int64())
+		}
+		return n
+	}
+	tests := []struct {
+		name string
+		ops  []OpCode
+		want interface{}
+	}{
+		{"ARRAYSIZE", []OpCode{ARRAYSIZE}, int64(2)},
+		{"PICKITEM", []OpCode{PUSH1, PICKITEM}, int64(5)},
+		{"HASKEY", []OpCode{PUSH3, HASKEY}, true},
+		{"HASKEY missing", []OpCode{PUSH2, HASKEY}, false},
+		{"KEYS", []OpCode{KEYS}, []int64{1, 3}},
+		{"VALUES", []OpCode{VALUES}, []int64{5, 4}},
+		{"REMOVE", []OpCode{DUP, PUSH1, REMOVE, KEYS}, []int64{3}},
+		{"REMOVE missing", []OpCode{DUP, PUSH2, REMOVE, KEYS}, []int64{1, 3}},
+	}
+	for _, test := range tests {
+		e, err := runMapScript(test.ops...)
+		if err != nil || e.GetState() != HALT {
+			t.Errorf("%s: got state %d, %v", test.name, e.GetState(), err)
+			continue
+		}
+		if n := e.GetEvaluationStackCount(); n != 1 {
+			t.Errorf("%s: %d items left on the stack", test.name, n)
+			continue
+		}
+		item := PopStackItem(e)
+		var got interface{}
+		switch test.want.(type) {
+		case int64:
+			got = item.GetBigInteger().Int64()
+		case bool:
+			got = item.GetBoolean()
+		case []int64:
+			got = ints(item.GetArray())
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
 
+func TestMapOpcodesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  []OpCode
+		err  error
+	}{
+		{"PICKITEM missing key", []OpCode{PUSH2, PICKITEM}, ErrBadValue},
+		{"PICKITEM map key", []OpCode{NEWMAP, PICKITEM}, ErrNotMapKey},
+		{"SETITEM array key", []OpCode{PUSH0, NEWARRAY, PUSH1, SETITEM}, ErrNotMapKey},
+		{"HASKEY map key", []OpCode{NEWMAP, HASKEY}, ErrNotMapKey},
+		{"REMOVE array key", []OpCode{PUSH0, NEWARRAY, REMOVE}, ErrNotMapKey},
+		{"KEYS of an array", []OpCode{VALUES, KEYS}, ErrNotMap},
+	}
+	for _, test := range tests {
+		e, err := runMapScript(test.ops...)
+		if e.GetState() != FAULT || err != test.err {
+			t.Errorf("%s: got state %d, %v, want %v", test.name, e.GetState(), err, test.err)
+		}
+	}
+}
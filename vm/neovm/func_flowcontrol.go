@@ -21,6 +21,7 @@ package neovm
 
 import (
 	. "github.com/Ontology/vm/neovm/errors"
+	"github.com/Ontology/vm/neovm/interfaces"
 	"github.com/Ontology/common/log"
 	"fmt"
 )
@@ -77,10 +78,25 @@ func opRet(e *ExecutionEngine) (VMState, error) {
 
 func opAppCall(e *ExecutionEngine) (VMState, error) {
 	codeHash := e.context.OpReader.ReadBytes(20)
-	if len(codeHash) == 0 {
+	if isZeroHash(codeHash) {
+		if !e.canDynamicInvoke() {
+			return FAULT, ErrDynamicInvoke
+		}
 		codeHash = PopByteArray(e)
 	}
 
+	if e.native != nil && e.native.IsNative(codeHash) {
+		// the native contract runs while the caller is still the
+		// current context, a tail call only drops it afterwards
+		if err := e.native.Invoke(e, codeHash); err != nil {
+			return FAULT, err
+		}
+		if e.opCode == TAILCALL {
+			e.invocationStack.Pop()
+		}
+		return NONE, nil
+	}
+
 	code, err := e.table.GetCode(codeHash)
 	if code == nil {
 		return FAULT, err
@@ -89,10 +105,51 @@ func opAppCall(e *ExecutionEngine) (VMState, error) {
 	if e.opCode == TAILCALL {
 		e.invocationStack.Pop()
 	}
+	if e.isReentry(codeHash) {
+		return FAULT, ErrReentrancy
+	}
 	e.LoadCode(code, false)
 	return NONE, nil
 }
 
+func isZeroHash(codeHash []byte) bool {
+	for _, b := range codeHash {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// canDynamicInvoke reports whether the running contract was deployed with
+// dynamic invoke. Code tables without contract properties deny it.
+func (e *ExecutionEngine) canDynamicInvoke() bool {
+	table, ok := e.table.(interfaces.IContractPropertyTable)
+	if !ok {
+		return false
+	}
+	codeHash, err := e.context.GetCodeHash()
+	if err != nil {
+		return false
+	}
+	return table.CanDynamicInvoke(codeHash.ToArray())
+}
+
+// isReentry reports whether a guarded contract is already on the invocation stack.
+func (e *ExecutionEngine) isReentry(codeHash []byte) bool {
+	table, ok := e.table.(interfaces.IContractPropertyTable)
+	if !ok || !table.HasReentrancyGuard(codeHash) {
+		return false
+	}
+	for i := 0; i < e.invocationStack.Count(); i++ {
+		hash, err := e.invocationStack.Peek(i).GetExecutionContext().GetCodeHash()
+		if err == nil && IsEqualBytes(hash.ToArray(), codeHash) {
+			return true
+		}
+	}
+	return false
+}
+
 func opSysCall(e *ExecutionEngine) (VMState, error) {
 	s := e.context.OpReader.ReadVarString()
 
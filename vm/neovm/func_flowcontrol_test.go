@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ontology/common"
+	. "github.com/Ontology/vm/neovm/errors"
+)
+
+// contractTable holds contracts with the call permissions they were deployed with.
+type contractTable struct {
+	codes   map[common.Uint160][]byte
+	dynamic map[common.Uint160]bool
+	guarded map[common.Uint160]bool
+}
+
+func newContractTable() *contractTable {
+	return &contractTable{
+		codes:   make(map[common.Uint160][]byte),
+		dynamic: make(map[common.Uint160]bool),
+		guarded: make(map[common.Uint160]bool),
+	}
+}
+
+func (t *contractTable) deploy(script []byte, dynamic, guarded bool) common.Uint160 {
+	codeHash, _ := common.ToCodeHash(script)
+	t.codes[codeHash] = script
+	t.dynamic[codeHash] = dynamic
+	t.guarded[codeHash] = guarded
+	return codeHash
+}
+
+func (t *contractTable) hash(scriptHash []byte) common.Uint160 {
+	codeHash, _ := common.Uint160ParseFromBytes(scriptHash)
+	return codeHash
+}
+
+func (t *contractTable) GetCode(scriptHash []byte) ([]byte, error) {
+	code, ok := t.codes[t.hash(scriptHash)]
+	if !ok {
+		return nil, errors.New("contract not found")
+	}
+	return code, nil
+}
+
+func (t *contractTable) CanDynamicInvoke(scriptHash []byte) bool {
+	return t.dynamic[t.hash(scriptHash)]
+}
+
+func (t *contractTable) HasReentrancyGuard(scriptHash []byte) bool {
+	return t.guarded[t.hash(scriptHash)]
+}
+
+// dynamicCall is the script calling the contract whose hash is on the stack.
+var dynamicCall = append([]byte{byte(APPCALL)}, make([]byte, 20)...)
+
+func TestAppCallDynamicInvoke(t *testing.T) {
+	table := newContractTable()
+	callee := table.deploy([]byte{byte(PUSH1)}, false, true)
+	script := append([]byte{20}, callee.ToArray()...)
+	script = append(script, dynamicCall...)
+
+	for _, dynamic := range []bool{false, true} {
+		table.deploy(script, dynamic, false)
+		e := NewExecutionEngine(nil, nil, table, nil)
+		e.LoadCode(script, false)
+		err := e.Execute()
+		if !dynamic {
+			if e.GetState() != FAULT || err != ErrDynamicInvoke {
+				t.Errorf("without dynamic invoke: got state %d, %v", e.GetState(), err)
+			}
+			continue
+		}
+		if e.GetState() != HALT || err != nil {
+			t.Fatalf("with dynamic invoke: got state %d, %v", e.GetState(), err)
+		}
+		if n := PopInt(e); n != 1 {
+			t.Errorf("with dynamic invoke: got %d", n)
+		}
+	}
+
+	// code tables without contract properties deny it
+	e := NewExecutionEngine(nil, nil, codeTable(table.codes), nil)
+	e.LoadCode(script, false)
+	if err := e.Execute(); e.GetState() != FAULT || err != ErrDynamicInvoke {
+		t.Errorf("without properties: got state %d, %v", e.GetState(), err)
+	}
+}
+
+// codeTable is a code table without contract properties.
+type codeTable map[common.Uint160][]byte
+
+func (t codeTable) GetCode(scriptHash []byte) ([]byte, error) {
+	codeHash, _ := common.Uint160ParseFromBytes(scriptHash)
+	return t[codeHash], nil
+}
+
+func TestAppCallReentrancy(t *testing.T) {
+	// the contract calls itself again with its hash, left on the stack
+	script := append([]byte{byte(DUP)}, dynamicCall...)
+	for _, guarded := range []bool{true, false} {
+		table := newContractTable()
+		codeHash := table.deploy(script, true, guarded)
+		e := NewExecutionEngine(nil, nil, table, nil)
+		e.SetMaxCallDepth(8)
+		e.LoadCode(script, false)
+		PushData(e, codeHash.ToArray())
+		err := e.Execute()
+		if guarded {
+			if e.GetState() != FAULT || err != ErrReentrancy || e.invocationStack.Count() != 1 {
+				t.Errorf("guarded: got state %d at depth %d, %v", e.GetState(), e.invocationStack.Count(), err)
+			}
+			continue
+		}
+		// unguarded, the recursion stops at the call depth limit
+		if e.GetState() != FAULT || err != ErrOverStackLen || e.invocationStack.Count() != 8 {
+			t.Errorf("unguarded: got state %d at depth %d, %v", e.GetState(), e.invocationStack.Count(), err)
+		}
+	}
+}
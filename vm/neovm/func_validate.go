@@ -118,7 +118,7 @@ func validateCall(e *ExecutionEngine) error {
 }
 
 func validateInvocationStack(e *ExecutionEngine) error {
-	if uint32(e.invocationStack.Count()) >= MaxInvovationStackSize {
+	if e.invocationStack.Count() >= e.maxCallDepth {
 		return ErrOverStackLen
 	}
 	return nil
@@ -393,17 +393,29 @@ func validatePickItem(e *ExecutionEngine) error {
 	if err := LogStackTrace(e, 2, "[validatePickItem]"); err != nil {
 		return err
 	}
-	index := PeekBigInteger(e)
-	if index.Sign() < 0 {
-		log.Error("[validatePickItem] index < 0")
-		return ErrBadValue
-	}
 	item := PeekN(1, e)
 	if item == nil {
 		log.Error("[validatePickItem] item = nil")
 		return ErrBadValue
 	}
 	stackItem := item.GetStackItem()
+	if m, ok := stackItem.(*types.Map); ok {
+		key := PeekStackItem(e)
+		if !types.IsMapKey(key) {
+			log.Error("[validatePickItem] invalid map key")
+			return ErrNotMapKey
+		}
+		if !m.ContainsKey(key) {
+			log.Error("[validatePickItem] map key not found")
+			return ErrBadValue
+		}
+		return nil
+	}
+	index := PeekBigInteger(e)
+	if index.Sign() < 0 {
+		log.Error("[validatePickItem] index < 0")
+		return ErrBadValue
+	}
 	if _, ok := stackItem.(*types.Array); !ok {
 		log.Error("[validatePickItem] ErrNotArray")
 		return ErrNotArray
@@ -424,17 +436,29 @@ func validatorSetItem(e *ExecutionEngine) error {
 		log.Error("[validatorSetItem] newItem = nil")
 		return ErrBadValue
 	}
-	index := PeekNBigInt(1, e)
-	if index.Sign() < 0 {
-		log.Error("[validatorSetItem] index < 0")
-		return ErrBadValue
-	}
 	arrItem := PeekN(2, e)
 	if arrItem == nil {
 		log.Error("[validatorSetItem] arrItem = nil")
 		return ErrBadValue
 	}
 	item := arrItem.GetStackItem()
+	if m, ok := item.(*types.Map); ok {
+		key := PeekNStackItem(1, e)
+		if !types.IsMapKey(key) {
+			log.Error("[validatorSetItem] invalid map key")
+			return ErrNotMapKey
+		}
+		if !m.ContainsKey(key) && uint32(m.Count()) >= MaxArraySize {
+			log.Error("[validatorSetItem] map count >= MaxArraySize")
+			return ErrOverMaxArraySize
+		}
+		return nil
+	}
+	index := PeekNBigInt(1, e)
+	if index.Sign() < 0 {
+		log.Error("[validatorSetItem] index < 0")
+		return ErrBadValue
+	}
 	if _, ok := item.(*types.Array); !ok {
 		return ErrNotArray
 	}
@@ -499,6 +523,77 @@ func validatorReverse(e *ExecutionEngine) error {
 	return nil
 }
 
+func validateRemove(e *ExecutionEngine) error {
+	if err := LogStackTrace(e, 2, "[validateRemove]"); err != nil {
+		return err
+	}
+	key := PeekStackItem(e)
+	item := PeekNStackItem(1, e)
+	if _, ok := item.(*types.Map); ok {
+		if !types.IsMapKey(key) {
+			log.Error("[validateRemove] invalid map key")
+			return ErrNotMapKey
+		}
+		return nil
+	}
+	if _, ok := item.(*types.Array); !ok {
+		return ErrNotArray
+	}
+	index := key.GetBigInteger()
+	if index.Sign() < 0 || index.Cmp(big.NewInt(int64(len(item.GetArray())))) >= 0 {
+		log.Error("[validateRemove] index out of range")
+		return ErrBadValue
+	}
+	return nil
+}
+
+func validateHasKey(e *ExecutionEngine) error {
+	if err := LogStackTrace(e, 2, "[validateHasKey]"); err != nil {
+		return err
+	}
+	key := PeekStackItem(e)
+	item := PeekNStackItem(1, e)
+	if _, ok := item.(*types.Map); ok {
+		if !types.IsMapKey(key) {
+			log.Error("[validateHasKey] invalid map key")
+			return ErrNotMapKey
+		}
+		return nil
+	}
+	if _, ok := item.(*types.Array); !ok {
+		return ErrNotArray
+	}
+	if key.GetBigInteger().Sign() < 0 {
+		log.Error("[validateHasKey] index < 0")
+		return ErrBadValue
+	}
+	return nil
+}
+
+func validateKeys(e *ExecutionEngine) error {
+	if err := LogStackTrace(e, 1, "[validateKeys]"); err != nil {
+		return err
+	}
+	if _, ok := PeekStackItem(e).(*types.Map); !ok {
+		return ErrNotMap
+	}
+	return nil
+}
+
+func validateValues(e *ExecutionEngine) error {
+	if err := LogStackTrace(e, 1, "[validateValues]"); err != nil {
+		return err
+	}
+	item := PeekStackItem(e)
+	if _, ok := item.(*types.Map); ok {
+		return nil
+	}
+	if _, ok := item.(*types.Array); !ok {
+		return ErrNotArray
+	}
+	return nil
+}
+
 func validatorThrowIfNot(e *ExecutionEngine) error {
 	if err := LogStackTrace(e, 1, "[validatorThrowIfNot]"); err != nil {
 		return err
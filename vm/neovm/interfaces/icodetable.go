@@ -22,3 +22,11 @@ package interfaces
 type ICodeTable interface {
 	GetCode(scriptHash []byte) ([]byte, error)
 }
+
+// IContractPropertyTable is optionally implemented by an ICodeTable to expose
+// the call permissions contracts were deployed with. Dynamic calls fault on
+// tables not implementing it.
+type IContractPropertyTable interface {
+	CanDynamicInvoke(scriptHash []byte) bool
+	HasReentrancyGuard(scriptHash []byte) bool
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+// NativeInvoker runs contracts implemented in Go when APPCALL or TAILCALL
+// targets their code hash. Invoke takes its parameters from and pushes its
+// result to the evaluation stack.
+type NativeInvoker interface {
+	IsNative(codeHash []byte) bool
+	Invoke(e *ExecutionEngine, codeHash []byte) error
+}
+
+// SetNativeInvoker installs the native contract dispatcher, nil removes it.
+func (e *ExecutionEngine) SetNativeInvoker(invoker NativeInvoker) {
+	e.native = invoker
+}
@@ -140,8 +140,13 @@ const (
 	SETITEM OpCode = 0xC4
 	NEWARRAY OpCode = 0xC5
 	NEWSTRUCT = 0xC6
+	NEWMAP OpCode = 0xC7
 	APPEND OpCode = 0xC8
 	REVERSE OpCode = 0xC9
+	REMOVE OpCode = 0xCA
+	HASKEY OpCode = 0xCB
+	KEYS OpCode = 0xCC
+	VALUES OpCode = 0xCD
 
 	//Exception
 	THROW = 0xF0
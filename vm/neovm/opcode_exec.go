@@ -61,7 +61,7 @@ var (
 		CALL:     {Opcode: CALL, Name: "CALL", Exec: opCall, Validator: validateCall},
 		RET:      {Opcode: RET, Name: "RET", Exec: opRet},
 		APPCALL:  {Opcode: APPCALL, Name: "APPCALL", Exec: opAppCall, Validator: validateAppCall},
-		TAILCALL: {Opcode: TAILCALL, Name: "TAILCALL", Exec: opAppCall},
+		TAILCALL: {Opcode: TAILCALL, Name: "TAILCALL", Exec: opAppCall, Validator: validateAppCall},
 		SYSCALL:  {Opcode: SYSCALL, Name: "SYSCALL", Exec: opSysCall, Validator: validateSysCall},
 
 		//Stack ops
@@ -141,6 +141,11 @@ var (
 		NEWSTRUCT: {Opcode: NEWSTRUCT, Name: "NEWSTRUCT", Exec: opNewStruct, Validator: validateNewStruct},
 		APPEND:    {Opcode: APPEND, Name: "APPEND", Exec: opAppend, Validator: validateAppend},
 		REVERSE:   {Opcode: REVERSE, Name: "REVERSE", Exec: opReverse, Validator: validatorReverse},
+		NEWMAP:    {Opcode: NEWMAP, Name: "NEWMAP", Exec: opNewMap},
+		REMOVE:    {Opcode: REMOVE, Name: "REMOVE", Exec: opRemove, Validator: validateRemove},
+		HASKEY:    {Opcode: HASKEY, Name: "HASKEY", Exec: opHasKey, Validator: validateHasKey},
+		KEYS:      {Opcode: KEYS, Name: "KEYS", Exec: opKeys, Validator: validateKeys},
+		VALUES:    {Opcode: VALUES, Name: "VALUES", Exec: opValues, Validator: validateValues},
 
 		//Exceptions
 		THROW:      {Opcode: THROW, Name: "THROW", Exec: opThrow},
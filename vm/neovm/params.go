@@ -24,6 +24,6 @@ const (
 	MaxInvovationStackSize = 1024
 	MaxSizeForBigInteger = 32
 	MaxItemSize uint32 = 1024 * 1024
-	MaxArraySize uint32 = 1024
+	MaxArraySize uint32 = 1024 // max number of array items or map entries
 )
 
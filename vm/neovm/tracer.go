@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import "github.com/Ontology/common"
+
+// TraceStep describes an instruction right before the engine executes it.
+type TraceStep struct {
+	CodeHash common.Uint160
+	// IP is the offset of the opcode in the script
+	IP     int
+	OpCode OpCode
+	// StackDepth is the evaluation stack count and Depth the invocation stack count
+	StackDepth int
+	Depth      int
+}
+
+// Tracer is notified by StepInto for every executed instruction.
+type Tracer interface {
+	CaptureStep(step *TraceStep)
+}
+
+// SetTracer installs a tracer, nil removes it.
+func (e *ExecutionEngine) SetTracer(tracer Tracer) {
+	e.tracer = tracer
+}
+
+func (e *ExecutionEngine) captureStep(context *ExecutionContext, ip int, opCode OpCode) {
+	codeHash, _ := context.GetCodeHash()
+	e.tracer.CaptureStep(&TraceStep{
+		CodeHash:   codeHash,
+		IP:         ip,
+		OpCode:     opCode,
+		StackDepth: e.evaluationStack.Count(),
+		Depth:      e.invocationStack.Count(),
+	})
+}
@@ -89,5 +89,6 @@ func (a *Array) GetStruct() []StackItemInterface {
 	return a._array
 }
 
-
-
+func (a *Array) RemoveAt(index int) {
+	a._array = append(a._array[:index], a._array[index+1:]...)
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"github.com/Ontology/vm/neovm/interfaces"
+	"math/big"
+)
+
+// Map is a dictionary stack item. Keys are compared with Equals and kept in
+// insertion order.
+type Map struct {
+	_keys   []StackItemInterface
+	_values []StackItemInterface
+}
+
+func NewMap() *Map {
+	var m Map
+	m._keys = make([]StackItemInterface, 0)
+	m._values = make([]StackItemInterface, 0)
+	return &m
+}
+
+func (m *Map) indexOf(key StackItemInterface) int {
+	for i, k := range m._keys {
+		if k.Equals(key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Map) Add(key StackItemInterface, value StackItemInterface) {
+	if i := m.indexOf(key); i >= 0 {
+		m._values[i] = value
+		return
+	}
+	m._keys = append(m._keys, key)
+	m._values = append(m._values, value)
+}
+
+func (m *Map) Remove(key StackItemInterface) bool {
+	i := m.indexOf(key)
+	if i < 0 {
+		return false
+	}
+	m._keys = append(m._keys[:i], m._keys[i+1:]...)
+	m._values = append(m._values[:i], m._values[i+1:]...)
+	return true
+}
+
+func (m *Map) ContainsKey(key StackItemInterface) bool {
+	return m.indexOf(key) >= 0
+}
+
+func (m *Map) TryGetValue(key StackItemInterface) (StackItemInterface, bool) {
+	i := m.indexOf(key)
+	if i < 0 {
+		return nil, false
+	}
+	return m._values[i], true
+}
+
+func (m *Map) Count() int {
+	return len(m._keys)
+}
+
+func (m *Map) GetKeys() []StackItemInterface {
+	keys := make([]StackItemInterface, len(m._keys))
+	copy(keys, m._keys)
+	return keys
+}
+
+func (m *Map) GetValues() []StackItemInterface {
+	values := make([]StackItemInterface, len(m._values))
+	copy(values, m._values)
+	return values
+}
+
+// Equals compares maps by reference, as they are mutable.
+func (m *Map) Equals(other StackItemInterface) bool {
+	if o, ok := other.(*Map); ok {
+		return m == o
+	}
+	return false
+}
+
+func (m *Map) GetBigInteger() *big.Int {
+	return big.NewInt(0)
+}
+
+func (m *Map) GetBoolean() bool {
+	return true
+}
+
+func (m *Map) GetByteArray() []byte {
+	return []byte{}
+}
+
+func (m *Map) GetInterface() interfaces.IInteropInterface {
+	return nil
+}
+
+func (m *Map) GetArray() []StackItemInterface {
+	return m.GetValues()
+}
+
+func (m *Map) GetStruct() []StackItemInterface {
+	return m.GetValues()
+}
+
+// IsMapKey reports whether item may be used as a map key.
+func IsMapKey(item StackItemInterface) bool {
+	switch item.(type) {
+	case *ByteArray, *Integer, *Boolean:
+		return true
+	}
+	return false
+}
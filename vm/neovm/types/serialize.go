@@ -0,0 +1,201 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/Ontology/common/serialization"
+	"github.com/Ontology/errors"
+)
+
+// type tags of serialized stack items
+const (
+	ByteArrayType byte = 0x00
+	BooleanType   byte = 0x01
+	IntegerType   byte = 0x02
+	ArrayType     byte = 0x80
+	StructType    byte = 0x81
+	MapType       byte = 0x82
+)
+
+// SerializeStackItem encodes an item as its type tag followed by var bytes
+// for ByteArray and Integer, a byte for Boolean, or a var uint count and the
+// elements for Array, Struct and Map. Interop interfaces and arrays that
+// contain themselves can not be serialized. The result is at most maxSize bytes.
+func SerializeStackItem(item StackItemInterface, maxSize int) ([]byte, error) {
+	w := new(bytes.Buffer)
+	if err := serializeItem(w, item, make(map[StackItemInterface]bool), maxSize); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+func serializeItem(w *bytes.Buffer, item StackItemInterface, path map[StackItemInterface]bool, maxSize int) error {
+	switch v := item.(type) {
+	case *ByteArray:
+		w.WriteByte(ByteArrayType)
+		serialization.WriteVarBytes(w, v.GetByteArray())
+	case *Boolean:
+		w.WriteByte(BooleanType)
+		serialization.WriteBool(w, v.GetBoolean())
+	case *Integer:
+		w.WriteByte(IntegerType)
+		serialization.WriteVarBytes(w, v.GetByteArray())
+	case *Array, *Struct, *Map:
+		if path[item] {
+			return errors.NewErr("[SerializeStackItem] Circular reference!")
+		}
+		path[item] = true
+		defer delete(path, item)
+		if m, ok := v.(*Map); ok {
+			w.WriteByte(MapType)
+			keys, values := m.GetKeys(), m.GetValues()
+			serialization.WriteVarUint(w, uint64(len(keys)))
+			for i := range keys {
+				if err := serializeItem(w, keys[i], path, maxSize); err != nil {
+					return err
+				}
+				if err := serializeItem(w, values[i], path, maxSize); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if _, ok := v.(*Struct); ok {
+			w.WriteByte(StructType)
+		} else {
+			w.WriteByte(ArrayType)
+		}
+		items := item.GetArray()
+		serialization.WriteVarUint(w, uint64(len(items)))
+		for _, it := range items {
+			if err := serializeItem(w, it, path, maxSize); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.NewErr("[SerializeStackItem] Not support stack item type!")
+	}
+	if w.Len() > maxSize {
+		return errors.NewErr("[SerializeStackItem] Serialized data over max item size!")
+	}
+	return nil
+}
+
+// DeserializeStackItem decodes data written by SerializeStackItem. Arrays and
+// maps may hold at most maxArraySize elements and nest at most maxArraySize
+// levels deep, byte arrays may be at most maxItemSize long.
+func DeserializeStackItem(data []byte, maxArraySize, maxItemSize int) (StackItemInterface, error) {
+	r := bytes.NewReader(data)
+	item, err := deserializeItem(r, maxArraySize, maxItemSize, 0)
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, errors.NewErr("[DeserializeStackItem] Unexpected trailing data!")
+	}
+	return item, nil
+}
+
+func readBytes(r *bytes.Reader, max int) ([]byte, error) {
+	n, err := serialization.ReadVarUint(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(max) || n > uint64(r.Len()) {
+		return nil, errors.NewErr("[DeserializeStackItem] Invalid data length!")
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func deserializeItem(r *bytes.Reader, maxArraySize, maxItemSize, depth int) (StackItemInterface, error) {
+	t, err := r.ReadByte()
+	if err != nil {
+		return nil, errors.NewErr("[DeserializeStackItem] Unexpected end of data!")
+	}
+	switch t {
+	case ByteArrayType:
+		b, err := readBytes(r, maxItemSize)
+		if err != nil {
+			return nil, err
+		}
+		return NewByteArray(b), nil
+	case BooleanType:
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, errors.NewErr("[DeserializeStackItem] Unexpected end of data!")
+		}
+		return NewBoolean(b != 0), nil
+	case IntegerType:
+		b, err := readBytes(r, maxItemSize)
+		if err != nil {
+			return nil, err
+		}
+		return NewInteger(ConvertBytesToBigInteger(b)), nil
+	case ArrayType, StructType, MapType:
+		if depth >= maxArraySize {
+			return nil, errors.NewErr("[DeserializeStackItem] Nested too deep!")
+		}
+		n, err := serialization.ReadVarUint(r, 0)
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(maxArraySize) {
+			return nil, errors.NewErr("[DeserializeStackItem] Array size over max array size!")
+		}
+		if t == MapType {
+			m := NewMap()
+			for i := uint64(0); i < n; i++ {
+				key, err := deserializeItem(r, maxArraySize, maxItemSize, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				if !IsMapKey(key) {
+					return nil, errors.NewErr("[DeserializeStackItem] Invalid map key!")
+				}
+				value, err := deserializeItem(r, maxArraySize, maxItemSize, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				m.Add(key, value)
+			}
+			return m, nil
+		}
+		items := make([]StackItemInterface, 0, n)
+		for i := uint64(0); i < n; i++ {
+			item, err := deserializeItem(r, maxArraySize, maxItemSize, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if t == StructType {
+			return NewStruct(items), nil
+		}
+		return NewArray(items), nil
+	}
+	return nil, errors.NewErr("[DeserializeStackItem] Unknown stack item type!")
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2018 Onchain <onchain@onchain.com>
+ *
+ * This file is part of The ontology_Zero.
+ *
+ * The ontology_Zero is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology_Zero is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology_Zero.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+const (
+	testMaxArraySize = 1024
+	testMaxItemSize  = 1024 * 1024
+)
+
+func TestSerializeRoundTrip(t *testing.T) {
+	m := NewMap()
+	m.Add(NewByteArray([]byte("key")), NewInteger(big.NewInt(-129)))
+	m.Add(NewInteger(big.NewInt(7)), NewArray([]StackItemInterface{NewBoolean(true)}))
+	items := []StackItemInterface{
+		NewByteArray([]byte{}),
+		NewByteArray([]byte("hello")),
+		NewBoolean(false),
+		NewInteger(big.NewInt(0)),
+		NewInteger(big.NewInt(1000000)),
+		NewArray([]StackItemInterface{NewInteger(big.NewInt(-1)), NewStruct([]StackItemInterface{NewByteArray([]byte{1})})}),
+		m,
+	}
+	for i, item := range items {
+		data, err := SerializeStackItem(item, testMaxItemSize)
+		if err != nil {
+			t.Fatalf("item %d: %v", i, err)
+		}
+		decoded, err := DeserializeStackItem(data, testMaxArraySize, testMaxItemSize)
+		if err != nil {
+			t.Fatalf("item %d: %v", i, err)
+		}
+		again, err := SerializeStackItem(decoded, testMaxItemSize)
+		if err != nil {
+			t.Fatalf("item %d: %v", i, err)
+		}
+		if !bytes.Equal(data, again) {
+			t.Fatalf("item %d: round trip mismatch %x %x", i, data, again)
+		}
+	}
+}
+
+func TestSerializeCircular(t *testing.T) {
+	items := []StackItemInterface{NewInteger(big.NewInt(1))}
+	a := NewArray(items)
+	items[0] = a
+	if _, err := SerializeStackItem(a, testMaxItemSize); err == nil {
+		t.Fatal("circular array serialized")
+	}
+
+	// the same array twice is not a cycle
+	inner := NewArray([]StackItemInterface{NewBoolean(true)})
+	outer := NewArray([]StackItemInterface{inner, inner})
+	if _, err := SerializeStackItem(outer, testMaxItemSize); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSerializeLimits(t *testing.T) {
+	if _, err := SerializeStackItem(NewByteArray(make([]byte, 100)), 50); err == nil {
+		t.Fatal("oversized item serialized")
+	}
+	// array claiming more elements than allowed
+	if _, err := DeserializeStackItem([]byte{ArrayType, 0xfd, 0x01, 0x10}, testMaxArraySize, testMaxItemSize); err == nil {
+		t.Fatal("oversized array deserialized")
+	}
+	// byte array longer than the remaining data
+	if _, err := DeserializeStackItem([]byte{ByteArrayType, 0x05, 0x01}, testMaxArraySize, testMaxItemSize); err == nil {
+		t.Fatal("truncated data deserialized")
+	}
+	// array as map key
+	if _, err := DeserializeStackItem([]byte{MapType, 0x01, ArrayType, 0x00, BooleanType, 0x01}, testMaxArraySize, testMaxItemSize); err == nil {
+		t.Fatal("invalid map key deserialized")
+	}
+}